@@ -0,0 +1,43 @@
+// Package apierr types errors from the speech and language services so the
+// UI can suggest what to do about them.
+package apierr
+
+import "net/http"
+
+// AuthError is a missing or rejected API key.
+type AuthError struct {
+	Service string
+	Err     error
+}
+
+func (e *AuthError) Error() string { return e.Err.Error() }
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// QuotaError is a rate limit or an exhausted plan or balance.
+type QuotaError struct {
+	Service string
+	Err     error
+}
+
+func (e *QuotaError) Error() string { return e.Err.Error() }
+func (e *QuotaError) Unwrap() error { return e.Err }
+
+// NetworkError is a service that couldn't be reached or didn't answer in time.
+type NetworkError struct {
+	Service string
+	Err     error
+}
+
+func (e *NetworkError) Error() string { return e.Err.Error() }
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// FromStatus types an error from an HTTP response by its status code.
+func FromStatus(service string, status int, err error) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthError{Service: service, Err: err}
+	case http.StatusPaymentRequired, http.StatusTooManyRequests:
+		return &QuotaError{Service: service, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package apierr
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryableError is a failure worth retrying, such as a rate limit, a server
+// error or a dropped connection.
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration // Delay requested by the server, if any
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// RetryableStatus reports whether an HTTP status is worth retrying.
+func RetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// ParseRetryAfter reads a Retry-After header, in seconds or as a date.
+func ParseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,371 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+// Sources that can be captured
+const (
+	Microphone = "microphone"
+	System     = "system" // What's playing, through a loopback device
+)
+
+// Capture devices whose names contain one of these are treated as loopback
+// sources on platforms without native loopback support: PulseAudio/PipeWire
+// monitor sources, and virtual devices like BlackHole on macOS.
+var loopbackDeviceHints = []string{"monitor", "blackhole", "loopback", "soundflower"}
+
+// DeviceError is an audio device that couldn't be found or opened.
+type DeviceError struct {
+	Err error
+}
+
+func (e *DeviceError) Error() string { return e.Err.Error() }
+func (e *DeviceError) Unwrap() error { return e.Err }
+
+// Capture records audio sources. Each source's audio is passed to its
+// callback as SampleRate mono 16-bit PCM, on an audio thread that mustn't
+// block, and may be reused once the callback returns.
+type Capture interface {
+	// Start opens a source and starts passing its audio to onPCM.
+	Start(source string, onPCM func(pcm []byte)) error
+	// Close stops every source.
+	Close()
+}
+
+// Devices captures from the system's audio devices. A source whose device
+// disappears, such as a Bluetooth headset switching profile, or that stops
+// delivering audio, is reopened on whatever device is there once it can be,
+// without the caller having to start it again.
+type Devices struct {
+	// Microphone picks the microphone by part of its name; empty uses the
+	// system default.
+	Microphone string
+	// Channel picks one of the microphone's channels, counting from 1,
+	// for interfaces that put it on a single channel; zero mixes them all
+	// to mono.
+	Channel int
+	// Period is how much audio each callback delivers; zero for 50 ms.
+	Period time.Duration
+
+	// OnLost and OnRecovered, if set, are called when a source's device is
+	// lost and when capture resumes, from a goroutine of their own.
+	OnLost      func(source string, err error)
+	OnRecovered func(source string)
+
+	ctx *malgo.AllocatedContext
+
+	mu      sync.Mutex
+	sources []*deviceSource
+	closed  bool
+	stop    chan struct{}  // Closed to stop the watchdog and reopening
+	reopens sync.WaitGroup // Lost sources being reopened
+}
+
+const (
+	stallTimeout   = 3 * time.Second // Without audio before a microphone counts as lost
+	reopenInterval = time.Second
+)
+
+// deviceSource is one started source and the device capturing it, nil while
+// it's lost.
+type deviceSource struct {
+	source string
+	onPCM  func(pcm []byte)
+	device *malgo.Device
+	last   atomic.Int64 // Unix nanoseconds of the last audio
+}
+
+// NewDevices initializes the audio backend.
+func NewDevices() (*Devices, error) {
+	slog.Debug("initializing audio context")
+	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, func(message string) {
+		slog.Debug("malgo", "message", strings.TrimSpace(message))
+	})
+	if err != nil {
+		return nil, &DeviceError{Err: fmt.Errorf("failed to initialize audio context: %v", err)}
+	}
+	slog.Debug("audio context initialized")
+	return &Devices{ctx: ctx, stop: make(chan struct{})}, nil
+}
+
+func (d *Devices) Start(source string, onPCM func(pcm []byte)) error {
+	s := &deviceSource{source: source, onPCM: onPCM}
+	device, err := d.open(s)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s.device = device
+	d.sources = append(d.sources, s)
+	if len(d.sources) == 1 {
+		go d.watch()
+	}
+	return nil
+}
+
+// open initializes and starts a device for s.
+func (d *Devices) open(s *deviceSource) (*malgo.Device, error) {
+	source := s.source
+	slog.Debug("setting up audio device", "source", source)
+	deviceConfig, err := deviceConfig(d.ctx.Context, source, d.Microphone)
+	if err != nil {
+		return nil, &DeviceError{Err: err}
+	}
+	deviceConfig.Capture.Format = malgo.FormatS16
+	deviceConfig.Capture.Channels = 0 // The device's own, downmixed here
+	deviceConfig.SampleRate = 0       // Use the device's native rate and resample ourselves
+	deviceConfig.PeriodSizeInMilliseconds = 50
+	if d.Period > 0 {
+		deviceConfig.PeriodSizeInMilliseconds = uint32(d.Period.Milliseconds())
+	}
+	deviceConfig.Alsa.NoMMap = 1
+	slog.Debug("audio device config", "format", deviceConfig.Capture.Format)
+
+	channel := d.Channel
+	if source == System {
+		channel = 0
+	}
+	var channels int
+	var resampler *Resampler
+	var device *malgo.Device
+	device, err = malgo.InitDevice(d.ctx.Context, deviceConfig, malgo.DeviceCallbacks{
+		Data: func(_, pSample []byte, _ uint32) {
+			s.last.Store(time.Now().UnixNano())
+			if pcm := resampler.Process(Downmix(pSample, channels, channel)); len(pcm) > 0 {
+				s.onPCM(pcm)
+			}
+		},
+		// Called when the device goes away, as well as when it's stopped
+		Stop: func() {
+			go d.lose(s, device, errors.New("device stopped"))
+		},
+	})
+	if err != nil {
+		slog.Error("failed to initialize audio device", "source", source, "err", err)
+		return nil, &DeviceError{Err: fmt.Errorf("failed to initialize capture device: %v", err)}
+	}
+	channels = int(device.CaptureChannels())
+	if channel > channels {
+		device.Uninit()
+		return nil, &DeviceError{Err: fmt.Errorf("capture device has no channel %d, only %d", channel, channels)}
+	}
+	resampler = NewResampler(int(device.SampleRate()), SampleRate)
+	slog.Info("audio device initialized", "source", source, "rate", device.SampleRate(), "channels", channels, "channel", channel, "resampling", !resampler.Passthrough())
+
+	slog.Debug("starting audio device", "source", source)
+	s.last.Store(time.Now().UnixNano())
+	if err := device.Start(); err != nil {
+		slog.Error("failed to start audio device", "source", source, "err", err)
+		device.Uninit()
+		return nil, &DeviceError{Err: fmt.Errorf("failed to start device: %v", err)}
+	}
+	return device, nil
+}
+
+// watch treats a device that has stopped delivering audio as lost, since
+// some backends keep a vanished device running silently. System audio is
+// left to the Stop notification: WASAPI loopback delivers nothing while
+// nothing is playing.
+func (d *Devices) watch() {
+	ticker := time.NewTicker(reopenInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+		d.mu.Lock()
+		sources := slices.Clone(d.sources)
+		d.mu.Unlock()
+		for _, s := range sources {
+			if s.source == System {
+				continue
+			}
+			if stalled := time.Since(time.Unix(0, s.last.Load())); stalled > stallTimeout {
+				d.lose(s, nil, fmt.Errorf("no audio for %v", stalled.Round(time.Second)))
+			}
+		}
+	}
+}
+
+// lose closes the device capturing s, if it's still device (or any, if
+// device is nil), and reopens s once it can. Not to be called from a device
+// callback.
+func (d *Devices) lose(s *deviceSource, device *malgo.Device, err error) {
+	d.mu.Lock()
+	if d.closed || s.device == nil || (device != nil && s.device != device) {
+		d.mu.Unlock()
+		return
+	}
+	device = s.device
+	s.device = nil
+	d.reopens.Add(1)
+	defer d.reopens.Done()
+	d.mu.Unlock()
+
+	slog.Warn("audio device lost", "source", s.source, "err", err)
+	device.Uninit()
+	if d.OnLost != nil {
+		d.OnLost(s.source, err)
+	}
+	ticker := time.NewTicker(reopenInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+		device, err := d.open(s)
+		if err != nil {
+			slog.Debug("audio device not back yet", "source", s.source, "err", err)
+			continue
+		}
+		d.mu.Lock()
+		if d.closed {
+			d.mu.Unlock()
+			device.Uninit()
+			return
+		}
+		s.device = device
+		d.mu.Unlock()
+		slog.Info("audio device recovered", "source", s.source)
+		if d.OnRecovered != nil {
+			d.OnRecovered(s.source)
+		}
+		return
+	}
+}
+
+func (d *Devices) Close() {
+	d.mu.Lock()
+	var devices []*malgo.Device
+	for _, s := range d.sources {
+		if s.device != nil {
+			devices = append(devices, s.device)
+		}
+	}
+	d.sources = nil
+	if !d.closed {
+		d.closed = true
+		close(d.stop)
+	}
+	d.mu.Unlock()
+	for _, device := range devices {
+		device.Stop()
+		device.Uninit()
+	}
+	d.reopens.Wait()
+	d.ctx.Uninit()
+}
+
+// CaptureChannels returns how many channels the microphone has: the most
+// any of its native formats offers.
+func (d *Devices) CaptureChannels() (int, error) {
+	var info malgo.DeviceInfo
+	if d.Microphone != "" {
+		found, err := findDevice(d.ctx.Context, d.Microphone)
+		if err != nil {
+			return 0, err
+		}
+		info = found
+	} else {
+		devices, err := d.ctx.Devices(malgo.Capture)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list capture devices: %v", err)
+		}
+		i := slices.IndexFunc(devices, func(device malgo.DeviceInfo) bool { return device.IsDefault != 0 })
+		if i < 0 {
+			return 0, fmt.Errorf("no default capture device")
+		}
+		info = devices[i]
+	}
+	info, err := d.ctx.DeviceInfo(malgo.Capture, info.ID, malgo.Shared)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query capture device: %v", err)
+	}
+	channels := 0
+	for _, format := range info.Formats {
+		channels = max(channels, int(format.Channels))
+	}
+	if channels == 0 {
+		return 0, fmt.Errorf("%s doesn't report its channels", info.Name())
+	}
+	return channels, nil
+}
+
+// deviceConfig returns the base device config for the given source, with
+// the microphone whose name contains microphone if it's set.
+func deviceConfig(ctx malgo.Context, source, microphone string) (malgo.DeviceConfig, error) {
+	if source != System {
+		config := malgo.DefaultDeviceConfig(malgo.Capture)
+		if microphone == "" {
+			return config, nil
+		}
+		info, err := findDevice(ctx, microphone)
+		if err != nil {
+			return malgo.DeviceConfig{}, err
+		}
+		slog.Info("using capture device", "device", info.Name())
+		config.Capture.DeviceID = info.ID.Pointer()
+		return config, nil
+	}
+
+	// WASAPI can capture any playback device directly
+	if runtime.GOOS == "windows" {
+		slog.Info("using WASAPI loopback capture")
+		return malgo.DefaultDeviceConfig(malgo.Loopback), nil
+	}
+
+	devices, err := ctx.Devices(malgo.Capture)
+	if err != nil {
+		return malgo.DeviceConfig{}, fmt.Errorf("failed to list capture devices: %v", err)
+	}
+
+	for _, info := range devices {
+		name := strings.ToLower(info.Name())
+		for _, hint := range loopbackDeviceHints {
+			if strings.Contains(name, hint) {
+				slog.Info("using loopback capture device", "device", info.Name())
+				config := malgo.DefaultDeviceConfig(malgo.Capture)
+				config.Capture.DeviceID = info.ID.Pointer()
+				return config, nil
+			}
+		}
+	}
+
+	if runtime.GOOS == "darwin" {
+		return malgo.DeviceConfig{}, fmt.Errorf("no loopback device found; install a virtual audio device such as BlackHole to capture system audio")
+	}
+	return malgo.DeviceConfig{}, fmt.Errorf("no monitor source found for system audio capture")
+}
+
+// findDevice returns the first capture device whose name contains name,
+// ignoring case.
+func findDevice(ctx malgo.Context, name string) (malgo.DeviceInfo, error) {
+	devices, err := ctx.Devices(malgo.Capture)
+	if err != nil {
+		return malgo.DeviceInfo{}, fmt.Errorf("failed to list capture devices: %v", err)
+	}
+	var names []string
+	for _, info := range devices {
+		if strings.Contains(strings.ToLower(info.Name()), strings.ToLower(name)) {
+			return info, nil
+		}
+		names = append(names, info.Name())
+	}
+	return malgo.DeviceInfo{}, fmt.Errorf("no capture device matches %q; found %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"sync"
+	"time"
+)
+
+// fileChunk is how much of a file File passes on at a time.
+const fileChunk = 50 * time.Millisecond
+
+// File plays audio in place of the capture devices, at the speed it was
+// recorded, and then silence until it's closed, as a device would. The
+// microphone hears the audio; other sources hear only silence.
+type File struct {
+	pcm  []byte
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFile plays SampleRate mono PCM, such as from DecodeWAV. Nil plays only
+// silence.
+func NewFile(pcm []byte) *File {
+	return &File{pcm: pcm, stop: make(chan struct{})}
+}
+
+func (f *File) Start(source string, onPCM func(pcm []byte)) error {
+	var pcm []byte
+	if source == Microphone {
+		pcm = f.pcm
+	}
+	chunkBytes := int(SampleRate * 2 * fileChunk / time.Second)
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		ticker := time.NewTicker(fileChunk)
+		defer ticker.Stop()
+		chunk := make([]byte, chunkBytes)
+		for offset := 0; ; offset += chunkBytes {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+			}
+			clear(chunk)
+			if offset < len(pcm) {
+				copy(chunk, pcm[offset:])
+			}
+			onPCM(chunk)
+		}
+	}()
+	return nil
+}
+
+func (f *File) Close() {
+	close(f.stop)
+	f.wg.Wait()
+}
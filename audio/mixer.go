@@ -0,0 +1,47 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// Keep at most one second of secondary audio
+const maxMixerPending = SampleRate * 2
+
+// Mixer adds a secondary source into the primary one. The primary source
+// drives timing; secondary audio is buffered and treated as silence when
+// missing, since loopback devices may deliver nothing while nothing plays.
+type Mixer struct {
+	mu      sync.Mutex
+	pending []byte
+}
+
+// Push buffers secondary audio for the next calls to Mix.
+func (m *Mixer) Push(pcm []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = append(m.pending, pcm...)
+	if over := len(m.pending) - maxMixerPending; over > 0 {
+		over += over % 2
+		m.pending = m.pending[over:]
+	}
+}
+
+// Mix returns primary with the buffered secondary audio added.
+func (m *Mixer) Mix(primary []byte) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]byte, len(primary))
+	for i := 0; i+1 < len(primary); i += 2 {
+		sum := int(int16(binary.LittleEndian.Uint16(primary[i:])))
+		if i+1 < len(m.pending) {
+			sum += int(int16(binary.LittleEndian.Uint16(m.pending[i:])))
+		}
+		sum = max(math.MinInt16, min(math.MaxInt16, sum))
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(sum)))
+	}
+	m.pending = m.pending[min(len(m.pending), len(primary)):]
+	return out
+}
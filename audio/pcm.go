@@ -0,0 +1,84 @@
+// Package audio captures, processes and plays 16-bit mono PCM.
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// SampleRate is the rate captured audio is resampled to, which the
+// streaming transcription service expects.
+const SampleRate = 16000
+
+// Duration converts a count of SampleRate mono PCM bytes to a duration.
+func Duration(bytes int64) time.Duration {
+	return time.Duration(bytes) * time.Second / (SampleRate * 2)
+}
+
+// Bytes is the size of d of SampleRate mono PCM, in whole samples.
+func Bytes(d time.Duration) int {
+	return int(int64(d)*SampleRate/int64(time.Second)) * 2
+}
+
+// KeepRecent appends pcm to buf, dropping the oldest audio beyond limit
+// bytes.
+func KeepRecent(buf, pcm []byte, limit int) []byte {
+	buf = append(buf, pcm...)
+	if over := len(buf) - limit&^1; over > 0 {
+		buf = append(buf[:0], buf[over:]...)
+	}
+	return buf
+}
+
+// DB returns the RMS loudness of 16-bit PCM in dB relative to full scale,
+// -Inf for digital silence.
+func DB(pcm []byte) float64 {
+	if len(pcm) < 2 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for i := 0; i+1 < len(pcm); i += 2 {
+		sample := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) / math.MaxInt16
+		sum += sample * sample
+	}
+	return 10 * math.Log10(sum/float64(len(pcm)/2))
+}
+
+// Downmix converts interleaved 16-bit PCM of channels channels to mono:
+// channel alone, counting from 1, or the average of them all if it's zero.
+func Downmix(pcm []byte, channels, channel int) []byte {
+	if channels <= 1 {
+		return pcm
+	}
+	frames := len(pcm) / (2 * channels)
+	out := make([]byte, frames*2)
+	for i := 0; i < frames; i++ {
+		frame := pcm[i*2*channels:]
+		var v int
+		if channel > 0 {
+			v = int(int16(binary.LittleEndian.Uint16(frame[(channel-1)*2:])))
+		} else {
+			for c := 0; c < channels; c++ {
+				v += int(int16(binary.LittleEndian.Uint16(frame[c*2:])))
+			}
+			v /= channels
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(int16(v)))
+	}
+	return out
+}
+
+// Gain scales 16-bit PCM, clipping at full scale.
+func Gain(pcm []byte, gain float64) []byte {
+	if gain == 1 {
+		return pcm
+	}
+	out := make([]byte, len(pcm))
+	for i := 0; i+1 < len(pcm); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) * gain
+		v = max(math.MinInt16, min(math.MaxInt16, v))
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(v)))
+	}
+	return out
+}
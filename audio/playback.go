@@ -0,0 +1,102 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+// ToneRate is the sample rate of synthesized tones.
+const ToneRate = 44100
+
+// Note is one tone of a cue, in Hz and milliseconds.
+type Note struct {
+	Freq     float64
+	Duration int
+}
+
+// Tones renders notes as mono 16-bit PCM with short fades so
+// consecutive notes don't click.
+func Tones(notes []Note) []byte {
+	var pcm []byte
+	for _, note := range notes {
+		n := ToneRate * note.Duration / 1000
+		fade := min(n/4, ToneRate/100)
+		for i := 0; i < n; i++ {
+			gain := 1.0
+			if i < fade {
+				gain = float64(i) / float64(fade)
+			} else if i > n-fade {
+				gain = float64(n-i) / float64(fade)
+			}
+			v := 0.3 * gain * math.Sin(2*math.Pi*note.Freq*float64(i)/ToneRate)
+			pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v*math.MaxInt16)))
+		}
+	}
+	return pcm
+}
+
+// Play plays mono 16-bit PCM at the given sample rate until it finishes
+// or ctx is cancelled. If played isn't nil it tracks how many bytes have been
+// played, for following along.
+func Play(ctx context.Context, pcm []byte, rate int, played *atomic.Int64) error {
+	mctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to initialize audio context: %v", err)
+	}
+	defer func() {
+		mctx.Uninit()
+		mctx.Free()
+	}()
+
+	config := malgo.DefaultDeviceConfig(malgo.Playback)
+	config.Playback.Format = malgo.FormatS16
+	config.Playback.Channels = 1
+	config.SampleRate = uint32(rate)
+
+	var mu sync.Mutex
+	pos := 0
+	done := make(chan struct{})
+	onSamples := func(pOutput, pInput []byte, framecount uint32) {
+		mu.Lock()
+		defer mu.Unlock()
+		n := copy(pOutput, pcm[pos:])
+		clear(pOutput[n:])
+		pos += n
+		if played != nil {
+			played.Store(int64(pos))
+		}
+		if pos >= len(pcm) && done != nil {
+			close(done)
+			done = nil
+		}
+	}
+
+	device, err := malgo.InitDevice(mctx.Context, config, malgo.DeviceCallbacks{Data: onSamples})
+	if err != nil {
+		return fmt.Errorf("failed to initialize playback device: %v", err)
+	}
+	defer device.Uninit()
+
+	wait := done
+	if err := device.Start(); err != nil {
+		return fmt.Errorf("failed to start playback device: %v", err)
+	}
+
+	duration := time.Duration(len(pcm)/2) * time.Second / time.Duration(rate)
+	select {
+	case <-ctx.Done():
+	case <-wait:
+		// Let the final period drain
+		time.Sleep(100 * time.Millisecond)
+	case <-time.After(duration + time.Second):
+	}
+	device.Stop()
+	return nil
+}
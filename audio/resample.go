@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Downsampling uses a windowed-sinc low-pass filter spanning this many zero
+// crossings on each side, with filterPhases table entries per input sample
+// interpolated between.
+const (
+	filterZeros  = 24
+	filterPhases = 128
+)
+
+// Resampler converts mono signed 16-bit little-endian PCM from a device's
+// native sample rate to the rate expected by the transcription provider.
+// State is carried between calls so chunk boundaries don't introduce clicks.
+type Resampler struct {
+	inRate  int
+	outRate int
+
+	// Downsampling: one side of the filter, the input it still needs, and
+	// the time of the next output in history in units of 1/outRate input
+	// samples, so it stays exact however the input is chunked
+	filter  []float64
+	half    int
+	history []float64
+	pos     int
+
+	// Upsampling: linear interpolation position, where -1 refers to the
+	// last sample of the previous chunk
+	fpos float64
+	last int16
+}
+
+func NewResampler(inRate, outRate int) *Resampler {
+	r := &Resampler{inRate: inRate, outRate: outRate}
+	if !r.Passthrough() && inRate > outRate {
+		r.design()
+	}
+	return r
+}
+
+// design builds a Blackman-windowed sinc low-pass with its cutoff just
+// under the output's Nyquist frequency. The history starts with half a
+// filter of silence, so output is delayed by that much.
+func (r *Resampler) design() {
+	cutoff := 0.45 * float64(r.outRate) / float64(r.inRate) // Cycles per input sample
+	r.half = int(math.Ceil(filterZeros / (2 * cutoff)))
+	r.filter = make([]float64, r.half*filterPhases+2)
+	for i := range r.filter {
+		d := float64(i) / filterPhases
+		if d > float64(r.half) {
+			break
+		}
+		h := 2 * cutoff
+		if d > 0 {
+			h = math.Sin(2*math.Pi*cutoff*d) / (math.Pi * d)
+		}
+		w := 0.42 + 0.5*math.Cos(math.Pi*d/float64(r.half)) + 0.08*math.Cos(2*math.Pi*d/float64(r.half))
+		r.filter[i] = h * w
+	}
+	r.history = make([]float64, r.half)
+	r.pos = r.half * r.outRate
+}
+
+// tap returns the filter at d input samples from its center.
+func (r *Resampler) tap(d float64) float64 {
+	x := math.Abs(d) * filterPhases
+	i := int(x)
+	if i+1 >= len(r.filter) {
+		return 0
+	}
+	frac := x - float64(i)
+	return r.filter[i] + (r.filter[i+1]-r.filter[i])*frac
+}
+
+// Passthrough reports whether the input is already at the output rate.
+func (r *Resampler) Passthrough() bool {
+	return r.inRate == r.outRate || r.inRate <= 0
+}
+
+func (r *Resampler) Process(in []byte) []byte {
+	if r.Passthrough() {
+		return in
+	}
+
+	n := len(in) / 2
+	if n == 0 {
+		return nil
+	}
+
+	if r.inRate > r.outRate {
+		return r.downsample(in, n)
+	}
+	return r.upsample(in, n)
+}
+
+// downsample filters the input at each output time, once the input half a
+// filter past that time has arrived.
+func (r *Resampler) downsample(in []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		r.history = append(r.history, float64(int16(binary.LittleEndian.Uint16(in[i*2:]))))
+	}
+
+	out := make([]byte, 0, (n*r.outRate/r.inRate+1)*2)
+	for r.pos/r.outRate+r.half < len(r.history) {
+		center := r.pos / r.outRate
+		frac := float64(r.pos%r.outRate) / float64(r.outRate)
+		var sum float64
+		for k := 1 - r.half; k <= r.half; k++ {
+			sum += r.history[center+k] * r.tap(float64(k)-frac)
+		}
+		sum = max(math.MinInt16, min(math.MaxInt16, math.Round(sum)))
+		out = binary.LittleEndian.AppendUint16(out, uint16(int16(sum)))
+		r.pos += r.inRate
+	}
+
+	// Keep what the next output still needs
+	if drop := r.pos/r.outRate - r.half + 1; drop > 0 {
+		r.history = append(r.history[:0], r.history[drop:]...)
+		r.pos -= drop * r.outRate
+	}
+	return out
+}
+
+func (r *Resampler) upsample(in []byte, n int) []byte {
+	sample := func(i int) float64 {
+		if i < 0 {
+			return float64(r.last)
+		}
+		return float64(int16(binary.LittleEndian.Uint16(in[i*2:])))
+	}
+
+	step := float64(r.inRate) / float64(r.outRate)
+	out := make([]byte, 0, (n*r.outRate/r.inRate+2)*2)
+	for r.fpos < float64(n-1) {
+		idx := int(r.fpos)
+		if r.fpos < 0 {
+			idx = -1
+		}
+		frac := r.fpos - float64(idx)
+		a, b := sample(idx), sample(idx+1)
+		out = binary.LittleEndian.AppendUint16(out, uint16(int16(a+(b-a)*frac)))
+		r.fpos += step
+	}
+	r.fpos -= float64(n)
+	r.last = int16(binary.LittleEndian.Uint16(in[(n-1)*2:]))
+	return out
+}
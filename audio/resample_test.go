@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// tone returns seconds of a sine at freq Hz, sampled at rate.
+func tone(freq float64, rate int, seconds float64) []byte {
+	n := int(float64(rate) * seconds)
+	pcm := make([]byte, 0, n*2)
+	for i := 0; i < n; i++ {
+		v := 0.5 * math.MaxInt16 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	return pcm
+}
+
+// resampleChunks resamples pcm in chunks of 10ms, as capture delivers it.
+func resampleChunks(pcm []byte, inRate, outRate int) []byte {
+	r := NewResampler(inRate, outRate)
+	chunk := inRate / 100 * 2
+	var out []byte
+	for len(pcm) > 0 {
+		n := min(chunk, len(pcm))
+		out = append(out, r.Process(pcm[:n])...)
+		pcm = pcm[n:]
+	}
+	return out
+}
+
+// rms returns the root mean square of pcm after its first skip samples.
+func rms(pcm []byte, skip int) float64 {
+	var sum float64
+	n := 0
+	for i := skip * 2; i+1 < len(pcm); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(pcm[i:])))
+		sum += v * v
+		n++
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+func TestResampleLength(t *testing.T) {
+	for _, rate := range []int{44100, 48000, 96000} {
+		pcm := tone(440, rate, 1)
+		out := resampleChunks(pcm, rate, SampleRate)
+		// Short of a second only by the filter's delay, a few milliseconds
+		if got := len(out) / 2; got > SampleRate || got < SampleRate*99/100 {
+			t.Errorf("%d Hz: %d samples out of a second, want about %d", rate, got, SampleRate)
+		}
+		if whole := NewResampler(rate, SampleRate).Process(pcm); len(whole) != len(out) {
+			t.Errorf("%d Hz: %d bytes in one call, %d in chunks", rate, len(whole), len(out))
+		}
+	}
+}
+
+func TestResampleFrequencyResponse(t *testing.T) {
+	for _, rate := range []int{44100, 48000} {
+		in := rms(tone(1000, rate, 1), 0)
+		for _, tt := range []struct {
+			freq     float64
+			min, max float64 // Gain in dB
+		}{
+			{300, -0.5, 0.5},
+			{1000, -0.5, 0.5},
+			{6000, -1, 0.5},
+			// Above the output's Nyquist frequency, these would alias
+			// into the speech band
+			{9000, math.Inf(-1), -40},
+			{12000, math.Inf(-1), -60},
+			{20000, math.Inf(-1), -60},
+		} {
+			out := resampleChunks(tone(tt.freq, rate, 1), rate, SampleRate)
+			gain := 20 * math.Log10(rms(out, SampleRate/10)/in)
+			if gain < tt.min || gain > tt.max {
+				t.Errorf("%d Hz: %v Hz gain %.1f dB, want %v to %v", rate, tt.freq, gain, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestUpsampleLength(t *testing.T) {
+	out := resampleChunks(tone(440, 8000, 1), 8000, SampleRate)
+	if got := len(out) / 2; got > SampleRate || got < SampleRate*99/100 {
+		t.Errorf("%d samples out of a second, want about %d", got, SampleRate)
+	}
+}
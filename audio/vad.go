@@ -0,0 +1,255 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Voice activity detection backends for silence suppression
+const (
+	Energy = "energy"
+	WebRTC = "webrtc"
+	Silero = "silero"
+)
+
+// Sensitivity presets: higher sensitivity lets quieter speech through but
+// also more background noise
+const (
+	Low    = "low"
+	Medium = "medium"
+	High   = "high"
+)
+
+const (
+	vadFrameMillis = 30
+	vadFrameBytes  = SampleRate * vadFrameMillis / 1000 * 2
+	vadHangover    = 400 / vadFrameMillis // Frames kept after speech so word endings aren't clipped
+	vadHelper      = "voice-typing-vad"   // Runs the WebRTC and Silero backends, see vad/
+	energyMinDB    = -55                  // Frames quieter than this are never speech
+)
+
+// energyMargins is how far above the noise floor, in dB, a frame must be to
+// count as speech at each sensitivity.
+var energyMargins = map[string]float64{Low: 15, Medium: 10, High: 6}
+
+// VoiceDetector decides whether a frame of 16 kHz 16-bit mono PCM holds
+// speech.
+type VoiceDetector interface {
+	speech(frame []byte) (bool, error)
+	close()
+}
+
+// EnergyDetector compares a frame's loudness to a running estimate of the
+// background noise. It needs nothing installed but is easily fooled by
+// noise that comes and goes, such as typing.
+type EnergyDetector struct {
+	margin float64
+	floor  float64 // Noise floor in dB
+}
+
+func newEnergyDetector(sensitivity string) *EnergyDetector {
+	margin, ok := energyMargins[sensitivity]
+	if !ok {
+		margin = energyMargins[Medium]
+	}
+	return &EnergyDetector{margin: margin, floor: energyMinDB}
+}
+
+func (d *EnergyDetector) speech(frame []byte) (bool, error) {
+	db := max(DB(frame), -100)
+	speech := db > energyMinDB && db > d.floor+d.margin
+	// The floor follows quieter frames quickly and louder ones slowly, so
+	// it tracks the room rather than the speaker
+	switch {
+	case db < d.floor:
+		d.floor += (db - d.floor) * 0.2
+	case !speech:
+		d.floor += (db - d.floor) * 0.02
+	}
+	return speech, nil
+}
+
+func (d *EnergyDetector) close() {}
+
+// HelperDetector runs a backend in the voice-typing-vad helper, which
+// answers each frame written to it with "1" for speech or "0". The helper
+// is fed from its own goroutine so a slow model never holds up the capture
+// callback: frames queue for it, and are dropped while the queue is full.
+// Each frame is judged by the helper's latest answer, which lags the audio
+// by the helper's latency; the hangover covers that at the end of speech.
+type HelperDetector struct {
+	cmd      *exec.Cmd
+	in       io.WriteCloser
+	out      *bufio.Reader
+	frames   chan []byte
+	errs     chan error
+	done     chan struct{}
+	speaking atomic.Bool
+	dropped  int
+}
+
+// helperQueueFrames is how far the helper may fall behind, in frames.
+const helperQueueFrames = 16
+
+func startHelperDetector(backend, sensitivity string) (*HelperDetector, error) {
+	cmd := exec.Command(vadHelper, "--backend", backend, "--sensitivity", sensitivity)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", vadHelper, err)
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", vadHelper, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", vadHelper, err)
+	}
+	d := &HelperDetector{cmd: cmd, in: in, out: bufio.NewReader(out)}
+
+	// Loading a model can take a few seconds, so wait until it's ready
+	if line, err := d.out.ReadString('\n'); err != nil || strings.TrimSpace(line) != "ready" {
+		in.Close()
+		cmd.Wait()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %s", vadHelper, msg)
+		}
+		return nil, fmt.Errorf("%s failed to start", vadHelper)
+	}
+	d.frames = make(chan []byte, helperQueueFrames)
+	d.errs = make(chan error, 1)
+	d.done = make(chan struct{})
+	go d.run()
+	return d, nil
+}
+
+// run passes queued frames to the helper and keeps its latest answer.
+func (d *HelperDetector) run() {
+	defer close(d.done)
+	defer d.in.Close()
+	for frame := range d.frames {
+		if err := d.ask(frame); err != nil {
+			d.errs <- err
+			// Drain the queue so close doesn't wait on a dead helper
+			for range d.frames {
+			}
+			return
+		}
+	}
+}
+
+func (d *HelperDetector) ask(frame []byte) error {
+	if _, err := d.in.Write(frame); err != nil {
+		return fmt.Errorf("failed to write to %s: %v", vadHelper, err)
+	}
+	answer, err := d.out.ReadByte()
+	if err != nil {
+		return fmt.Errorf("failed to read from %s: %v", vadHelper, err)
+	}
+	d.speaking.Store(answer == '1')
+	return nil
+}
+
+func (d *HelperDetector) speech(frame []byte) (bool, error) {
+	select {
+	case err := <-d.errs:
+		return false, err
+	default:
+	}
+	select {
+	case d.frames <- append([]byte(nil), frame...):
+	default:
+		d.dropped++
+	}
+	return d.speaking.Load(), nil
+}
+
+func (d *HelperDetector) close() {
+	close(d.frames)
+	select {
+	case <-d.done:
+	case <-time.After(time.Second):
+		// A helper that stopped answering won't notice its input closing
+		d.cmd.Process.Kill()
+		<-d.done
+	}
+	d.cmd.Wait()
+	if d.dropped > 0 {
+		slog.Warn("voice detection fell behind, skipped frames", "helper", vadHelper, "frames", d.dropped)
+	}
+}
+
+// VAD suppresses the audio between utterances: frames without speech, once
+// the hangover after the last speech has passed, are sent as digital
+// silence so background noise isn't transcribed.
+type VAD struct {
+	detector VoiceDetector
+	pending  []byte // Audio short of a whole frame
+	quiet    int    // Frames since the last speech
+	failed   bool
+}
+
+// NewVAD starts a backend, falling back to the energy detector
+// (with the error) if the helper can't run.
+func NewVAD(backend, sensitivity string) (*VAD, error) {
+	if sensitivity == "" {
+		sensitivity = Medium
+	}
+	if backend == Energy {
+		return &VAD{detector: newEnergyDetector(sensitivity), quiet: vadHangover}, nil
+	}
+	detector, err := startHelperDetector(backend, sensitivity)
+	if err != nil {
+		return &VAD{detector: newEnergyDetector(sensitivity), quiet: vadHangover}, err
+	}
+	return &VAD{detector: detector, quiet: vadHangover}, nil
+}
+
+// Process classifies the whole frames in pcm, holding any remainder back
+// for the next call. It returns those frames with the non-speech silenced,
+// and whether the last of them was speech.
+func (v *VAD) Process(pcm []byte) ([]byte, bool) {
+	v.pending = append(v.pending, pcm...)
+	frames := len(v.pending) / vadFrameBytes
+	out := make([]byte, frames*vadFrameBytes)
+	copy(out, v.pending)
+	v.pending = append(v.pending[:0], v.pending[len(out):]...)
+
+	speech := v.quiet == 0
+	for i := 0; i < len(out); i += vadFrameBytes {
+		frame := out[i : i+vadFrameBytes]
+		speech = true
+		if !v.failed {
+			var err error
+			speech, err = v.detector.speech(frame)
+			if err != nil {
+				// Sending everything is better than dropping speech
+				slog.Warn("voice detection failed, sending all audio", "err", err)
+				v.failed = true
+				speech = true
+			}
+		}
+		if speech {
+			v.quiet = 0
+		} else {
+			v.quiet++
+		}
+		if v.quiet > vadHangover {
+			clear(frame)
+		}
+	}
+	return out, speech
+}
+
+// Close stops the backend.
+func (v *VAD) Close() {
+	v.detector.close()
+}
@@ -0,0 +1,152 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const wavHeaderSize = 44
+
+// Recording saves audio as a WAV file while it's captured. Only one
+// goroutine may write to it.
+type Recording struct {
+	Path  string
+	file  *os.File
+	bytes int64
+}
+
+// Record creates a WAV file for SampleRate mono PCM. Its sizes are
+// left unknown (0xFFFFFFFF) until it's closed, which readers of a growing
+// file accept.
+func Record(path string) (*Recording, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recordings folder: %v", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %v", err)
+	}
+	if _, err := f.Write(wavHeader(0xFFFFFFFF)); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write recording: %v", err)
+	}
+	return &Recording{Path: path, file: f}, nil
+}
+
+// wavHeader returns the header for dataSize bytes of SampleRate mono 16-bit
+// PCM.
+func wavHeader(dataSize uint32) []byte {
+	riffSize := dataSize
+	if dataSize < 0xFFFFFFFF-36 {
+		riffSize = dataSize + 36
+	}
+	h := []byte("RIFF")
+	h = binary.LittleEndian.AppendUint32(h, riffSize)
+	h = append(h, "WAVEfmt "...)
+	h = binary.LittleEndian.AppendUint32(h, 16)
+	h = binary.LittleEndian.AppendUint16(h, 1) // PCM
+	h = binary.LittleEndian.AppendUint16(h, 1) // Mono
+	h = binary.LittleEndian.AppendUint32(h, SampleRate)
+	h = binary.LittleEndian.AppendUint32(h, SampleRate*2)
+	h = binary.LittleEndian.AppendUint16(h, 2)
+	h = binary.LittleEndian.AppendUint16(h, 16)
+	h = append(h, "data"...)
+	return binary.LittleEndian.AppendUint32(h, dataSize)
+}
+
+// Write appends pcm, logging any failure rather than interrupting capture.
+func (r *Recording) Write(pcm []byte) {
+	n, err := r.file.Write(pcm)
+	r.bytes += int64(n)
+	if err != nil {
+		slog.Warn("failed to write session audio", "path", r.Path, "err", err)
+	}
+}
+
+// Close fills in the header's sizes.
+func (r *Recording) Close() {
+	if _, err := r.file.WriteAt(wavHeader(uint32(min(r.bytes, 0xFFFFFFFF-36))), 0); err != nil {
+		slog.Warn("failed to finish session audio", "path", r.Path, "err", err)
+	}
+	r.file.Close()
+}
+
+// ReadSegment reads the PCM between two offsets of a recording, which may
+// still be growing.
+func ReadSegment(path string, start, end time.Duration) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %v", err)
+	}
+	defer f.Close()
+	from := int64(max(start, 0)) * SampleRate / int64(time.Second) * 2
+	to := int64(max(end, start)) * SampleRate / int64(time.Second) * 2
+	pcm := make([]byte, to-from)
+	n, err := f.ReadAt(pcm, wavHeaderSize+from)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read recording: %v", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("the recording ends before this turn")
+	}
+	return pcm[:n], nil
+}
+
+// DecodeWAV returns a 16-bit PCM WAV file's audio as SampleRate mono PCM,
+// mixing down its channels and resampling it as needed.
+func DecodeWAV(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+	var channels, rate int
+	var pcm []byte
+	for rest := data[12:]; len(rest) >= 8; {
+		id := string(rest[:4])
+		body := rest[8:]
+		// Recordings that were never closed leave their sizes unknown
+		size := int(min(int64(binary.LittleEndian.Uint32(rest[4:8])), int64(len(body))))
+		chunk := body[:size]
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("invalid WAV format chunk")
+			}
+			format := binary.LittleEndian.Uint16(chunk[0:2])
+			channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			bits := binary.LittleEndian.Uint16(chunk[14:16])
+			if (format != 1 && format != 0xFFFE) || bits != 16 || channels == 0 {
+				return nil, errors.New("unsupported WAV format: only 16-bit PCM is supported")
+			}
+		case "data":
+			pcm = chunk
+		}
+		rest = body[min(size+size%2, len(body)):]
+	}
+	if channels == 0 {
+		return nil, errors.New("WAV file has no format chunk")
+	}
+	if len(pcm) == 0 {
+		return nil, errors.New("WAV file has no audio")
+	}
+
+	if channels > 1 {
+		frame := channels * 2
+		mono := make([]byte, 0, len(pcm)/channels)
+		for i := 0; i+frame <= len(pcm); i += frame {
+			sum := 0
+			for c := 0; c < channels; c++ {
+				sum += int(int16(binary.LittleEndian.Uint16(pcm[i+c*2:])))
+			}
+			mono = binary.LittleEndian.AppendUint16(mono, uint16(int16(sum/channels)))
+		}
+		pcm = mono
+	}
+	return NewResampler(rate, SampleRate).Process(pcm), nil
+}
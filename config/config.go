@@ -0,0 +1,384 @@
+// Package config stores the settings as a versioned JSON file with a section
+// for each part of the app.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Version is the current format. Version 1 was a flat object of strings,
+// which is migrated when loaded.
+const Version = 2
+
+// Config is the settings file. Durations are in the unit their name gives,
+// or seconds.
+type Config struct {
+	Version    int               `json:"version"`
+	Profile    string            `json:"profile"`
+	Audio      Audio             `json:"audio"`
+	STT        STT               `json:"stt"`
+	Transcript Transcript        `json:"transcript"`
+	Meeting    Meeting           `json:"meeting"`
+	Snippet    Snippet           `json:"snippet"`
+	HandsFree  HandsFree         `json:"hands_free"`
+	Lecture    Lecture           `json:"lecture"`
+	LLM        LLM               `json:"llm"`
+	Translate  Translate         `json:"translate"`
+	Sinks      Sinks             `json:"sinks"`
+	Digest     Digest            `json:"digest"`
+	Storage    Storage           `json:"storage"`
+	Usage      Usage             `json:"usage"`
+	Network    Network           `json:"network"`
+	Team       Team              `json:"team"`
+	UI         UI                `json:"ui"`
+	Shortcuts  map[string]string `json:"shortcuts"` // Key bindings by action ID
+}
+
+type Audio struct {
+	CaptureSource   string `json:"capture_source"`
+	Device          string `json:"device"`
+	Channel         int    `json:"channel"` // Counting from 1; 0 mixes every channel
+	Record          bool   `json:"record"`
+	VADBackend      string `json:"vad_backend"`
+	VADSensitivity  string `json:"vad_sensitivity"`
+	AutoStopSilence int    `json:"auto_stop_silence"`
+	SessionLimit    int    `json:"session_limit_minutes"`
+	ChunkMS         int    `json:"chunk_ms"` // Milliseconds of audio per message
+}
+
+type STT struct {
+	APIKey        string        `json:"api_key"`
+	AutoLanguage  bool          `json:"auto_language"`
+	Vocabulary    string        `json:"vocabulary"` // One term per line
+	TurnDetection TurnDetection `json:"turn_detection"`
+}
+
+type TurnDetection struct {
+	Confidence float64 `json:"confidence"`
+	MinSilence int     `json:"min_silence_ms"`
+	MaxSilence int     `json:"max_silence_ms"`
+}
+
+type Transcript struct {
+	TurnPlacement   string  `json:"turn_placement"`
+	TurnSeparator   string  `json:"turn_separator"`
+	ParagraphPause  int     `json:"paragraph_pause"`
+	SmartJoin       bool    `json:"smart_join"`
+	ShowLatency     bool    `json:"show_latency"`
+	ClearAfterCopy  bool    `json:"clear_after_copy"`
+	WatchKeywords   string  `json:"watch_keywords"`
+	StripPhrases    string  `json:"strip_phrases"`
+	SoundsLike      string  `json:"sounds_like"`
+	TextSnippets    string  `json:"text_snippets"`
+	TurnFilters     string  `json:"turn_filters"`
+	RedactPII       string  `json:"redact_pii"`
+	RedactNames     string  `json:"redact_names"`
+	FilterProfanity bool    `json:"filter_profanity"`
+	SpellLanguage   string  `json:"spell_language"`
+	Numbers         Numbers `json:"numbers"`
+}
+
+type Numbers struct {
+	Style           string `json:"style"`
+	CurrencySymbols bool   `json:"currency_symbols"`
+	UnitSymbols     bool   `json:"unit_symbols"`
+	DateFormat      string `json:"date_format"`
+	PhoneFormat     string `json:"phone_format"`
+}
+
+type Meeting struct {
+	Mixed               bool   `json:"mixed"`
+	SpeakerMe           string `json:"speaker_me"`
+	SpeakerOthers       string `json:"speaker_others"`
+	ConsentPrompt       bool   `json:"consent_prompt"`
+	ConsentChecklist    string `json:"consent_checklist"`
+	ConsentAnnounce     bool   `json:"consent_announce"`
+	ConsentAnnouncement string `json:"consent_announcement"`
+	Notes               bool   `json:"notes"`
+	NotesPipeline       string `json:"notes_pipeline"`
+	CalendarURL         string `json:"calendar_url"`
+}
+
+type Snippet struct {
+	Inject string `json:"inject"`
+	Format bool   `json:"format"`
+}
+
+type HandsFree struct {
+	Enabled     bool   `json:"enabled"`
+	WakeWord    string `json:"wake_word"`
+	SleepPhrase string `json:"sleep_phrase"`
+	PreRoll     int    `json:"pre_roll_ms"`
+}
+
+type Lecture struct {
+	Enabled  bool `json:"enabled"`
+	Interval int  `json:"interval_minutes"`
+}
+
+type LLM struct {
+	APIKey        string   `json:"api_key"`
+	Model         string   `json:"model"`
+	Endpoint      string   `json:"endpoint"`
+	SystemPrompt  string   `json:"system_prompt"`
+	Temperature   *float64 `json:"temperature"` // Nil for the model's default
+	TopP          *float64 `json:"top_p"`
+	MaxTokens     int      `json:"max_tokens"`
+	MaxRetries    int      `json:"max_retries"`
+	Timeout       int      `json:"timeout"`
+	CleanReplies  bool     `json:"clean_replies"`
+	ReplyPatterns string   `json:"reply_patterns"`
+	Compare       Compare  `json:"compare"`
+}
+
+// Compare is model B of an A/B comparison.
+type Compare struct {
+	Model        string `json:"model"`
+	Endpoint     string `json:"endpoint"`
+	APIKey       string `json:"api_key"`
+	SystemPrompt string `json:"system_prompt"`
+}
+
+type Translate struct {
+	Provider     string `json:"provider"`
+	DeepLAPIKey  string `json:"deepl_api_key"`
+	GoogleAPIKey string `json:"google_api_key"`
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+}
+
+type Sinks struct {
+	FilePath        string `json:"file_path"`
+	FileTemplate    string `json:"file_template"`
+	PipePath        string `json:"pipe_path"`
+	PipeTemplate    string `json:"pipe_template"`
+	PipeFormat      string `json:"pipe_format"`
+	WebhookURL      string `json:"webhook_url"`
+	WebhookTemplate string `json:"webhook_template"`
+}
+
+type Digest struct {
+	Enabled bool   `json:"enabled"`
+	Day     int    `json:"day"` // time.Weekday
+	Folder  string `json:"folder"`
+}
+
+type Storage struct {
+	RetainAudioDays        int `json:"retain_audio_days"`
+	RetainTranscriptMonths int `json:"retain_transcript_months"`
+	MaxMB                  int `json:"max_mb"`
+}
+
+type Usage struct {
+	AudioPerHour         float64 `json:"audio_per_hour"`
+	PromptPerMillion     float64 `json:"prompt_per_million"`
+	CompletionPerMillion float64 `json:"completion_per_million"`
+	MonthlyBudget        float64 `json:"monthly_budget"`
+}
+
+type Network struct {
+	ProxyURL   string `json:"proxy_url"`
+	CACertFile string `json:"ca_cert_file"`
+}
+
+// Team is where shared presets are fetched from.
+type Team struct {
+	ConfigURL string `json:"config_url"`
+}
+
+type UI struct {
+	Theme          string `json:"theme"`
+	TranscriptFont string `json:"transcript_font"`
+	FontSize       int    `json:"font_size"`
+	ReadAloudRate  int    `json:"read_aloud_rate"`
+	Locale         string `json:"locale"`
+	Language       string `json:"language"` // Of the UI; empty for the system's
+	Announce       bool   `json:"announce"`
+	SoundCues      bool   `json:"sound_cues"`
+	FlashCues      bool   `json:"flash_cues"`
+	Notifications  bool   `json:"notifications"`
+	SeenHints      string `json:"seen_hints"`
+	LogLevel       string `json:"log_level"`
+}
+
+// Values is a config as stored: a JSON object with an object for each
+// section. Settings it leaves out are unset, so configs can be layered,
+// such as the user's over a team's.
+type Values map[string]any
+
+// Values returns c with every setting set.
+func (c *Config) Values() Values {
+	data, _ := json.Marshal(c)
+	var v Values
+	json.Unmarshal(data, &v)
+	return v
+}
+
+// Decode sets the settings v sets in c, leaving the rest. Settings of the
+// wrong type are skipped, and the first is reported.
+func (v Values) Decode(c *Config) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Without returns v without the settings it sets to the same value as base.
+func (v Values) Without(base Values) Values {
+	out := make(Values)
+	for key, value := range v {
+		if key == "version" {
+			out[key] = value
+			continue
+		}
+		section, isSection := value.(map[string]any)
+		baseSection, baseIsSection := base[key].(map[string]any)
+		switch {
+		case isSection && baseIsSection:
+			if rest := Values(section).Without(baseSection); len(rest) > 0 {
+				out[key] = map[string]any(rest)
+			}
+		case reflect.DeepEqual(value, base[key]):
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// Get returns a setting by its path, such as "team.config_url".
+func (v Values) Get(path string) (any, bool) {
+	section, name, nested := strings.Cut(path, ".")
+	if !nested {
+		value, ok := v[path]
+		return value, ok
+	}
+	values, ok := v[section].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return Values(values).Get(name)
+}
+
+// Set sets a setting by its path, creating its sections.
+func (v Values) Set(path string, value any) {
+	section, name, nested := strings.Cut(path, ".")
+	if !nested {
+		v[path] = value
+		return
+	}
+	values, ok := v[section].(map[string]any)
+	if !ok {
+		values = make(map[string]any)
+		v[section] = values
+	}
+	Values(values).Set(name, value)
+}
+
+// Delete removes a setting by its path.
+func (v Values) Delete(path string) {
+	section, name, nested := strings.Cut(path, ".")
+	if !nested {
+		delete(v, path)
+		return
+	}
+	if values, ok := v[section].(map[string]any); ok {
+		Values(values).Delete(name)
+	}
+}
+
+// Store loads and saves the settings.
+type Store interface {
+	Load() (Values, error)
+	Save(v Values) error
+}
+
+// FileStore keeps the settings in a JSON file, readable only by the user.
+// Older files are migrated when loaded, keeping the original beside it.
+type FileStore struct {
+	Path string
+}
+
+func (s *FileStore) Load() (Values, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var v Values
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filepath.Base(s.Path), err)
+	}
+	migrated, version := Migrate(v)
+	if version >= Version {
+		return migrated, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d", s.Path, version)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		slog.Warn("failed to keep the settings before migrating", "path", backup, "err", err)
+	} else if err := s.Save(migrated); err != nil {
+		slog.Warn("failed to save migrated settings", "path", s.Path, "err", err)
+	}
+	slog.Info("migrated settings", "path", s.Path, "from", version, "to", Version)
+	return migrated, nil
+}
+
+func (s *FileStore) Save(v Values) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// portableDir holds everything in portable mode; see UsePortable.
+var portableDir string
+
+// UsePortable keeps the settings and user files in a folder beside the
+// executable instead of the user's home, so they travel with it, such as on
+// a USB stick.
+func UsePortable() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the executable: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	dir := filepath.Join(filepath.Dir(exe), "voice-typing-data")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create portable data folder: %v", err)
+	}
+	portableDir = dir
+	return nil
+}
+
+// Path is the settings file.
+func Path() string {
+	if portableDir != "" {
+		return filepath.Join(portableDir, "settings.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".assemblyai-transcriber.json")
+}
+
+// Dir holds user files other than the settings, such as profiles, export
+// templates and history.
+func Dir() string {
+	if portableDir != "" {
+		return portableDir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = home
+	}
+	return filepath.Join(dir, "voice-typing")
+}
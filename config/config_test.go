@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	store := &FileStore{Path: filepath.Join(t.TempDir(), "settings.json")}
+	temperature := 0.5
+	c := &Config{Version: Version, STT: STT{APIKey: "key", Vocabulary: "Fyne\nmalgo"}, Shortcuts: map[string]string{"record": "F8"}}
+	c.LLM.Temperature = &temperature
+	if err := store.Save(c.Values()); err != nil {
+		t.Fatal(err)
+	}
+	v, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := v.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, c) {
+		t.Errorf("loaded %+v, want %+v", got, c)
+	}
+
+	// It holds API keys
+	info, err := os.Stat(store.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("settings file mode = %v, want private", perm)
+	}
+}
+
+func TestFileStoreLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := (&FileStore{Path: filepath.Join(dir, "missing.json")}).Load(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want not exist", err)
+	}
+
+	path := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(path, []byte(`{"theme": `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := (&FileStore{Path: path}).Load()
+	if err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("invalid JSON: err = %v, want one naming the file", err)
+	}
+}
+
+func TestFileStoreMigratesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	v1 := `{"assembly_api_key": "key", "record_audio": "true", "font_size": "18", "llm_temperature": "",
+		"end_of_turn_confidence": "0.5", "shortcut_record": "F8", "retired_setting": "x", "font_size_typo": "1"}`
+	if err := os.WriteFile(path, []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := &FileStore{Path: path}
+	v, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Config
+	if err := v.Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Version:   Version,
+		Audio:     Audio{Record: true},
+		STT:       STT{APIKey: "key", TurnDetection: TurnDetection{Confidence: 0.5}},
+		UI:        UI{FontSize: 18},
+		Shortcuts: map[string]string{"record": "F8"},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("migrated to %+v, want %+v", c, want)
+	}
+
+	// The original is kept, and the file is saved in the current format
+	if data, err := os.ReadFile(path + ".v1"); err != nil || string(data) != v1 {
+		t.Errorf("kept %q, %v, want the version 1 file", data, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct{ Version int }
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Version != Version {
+		t.Errorf("saved version %d, %v, want %d", saved.Version, err, Version)
+	}
+}
+
+func TestValuesWithout(t *testing.T) {
+	base := Values{"llm": map[string]any{"model": "team", "api_key": "team-key"}, "profile": "Work"}
+	v := Values{
+		"version": float64(Version),
+		"llm":     map[string]any{"model": "team", "api_key": "mine"},
+		"ui":      map[string]any{"theme": "dark"},
+		"profile": "Work",
+	}
+	want := Values{
+		"version": float64(Version),
+		"llm":     map[string]any{"api_key": "mine"},
+		"ui":      map[string]any{"theme": "dark"},
+	}
+	if got := v.Without(base); !reflect.DeepEqual(got, want) {
+		t.Errorf("Without() = %v, want %v", got, want)
+	}
+
+	v.Delete("llm.api_key")
+	v.Delete("profile")
+	if _, ok := v["llm"].(map[string]any)["api_key"]; ok {
+		t.Error("Delete left llm.api_key")
+	}
+	if _, ok := v["profile"]; ok {
+		t.Error("Delete left profile")
+	}
+}
+
+func TestExportSecrets(t *testing.T) {
+	c := &Config{Version: Version, STT: STT{APIKey: "assembly-key", Vocabulary: "Fyne"}}
+	c.LLM.Compare.APIKey = "compare-key"
+	v := c.Values()
+
+	plain, err := NewExport(v, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Encrypted() {
+		t.Error("export without a passphrase has secrets")
+	}
+	if key, ok := plain.Settings.Get("stt.api_key"); ok {
+		t.Errorf("exported key %v, want it left out", key)
+	}
+	if key, _ := v.Get("stt.api_key"); key != "assembly-key" {
+		t.Error("exporting changed the settings")
+	}
+
+	e, err := NewExport(v, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "assembly-key") {
+		t.Errorf("export %s has the key in the clear", data)
+	}
+	parsed, err := ParseExport(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsed.Open("wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong passphrase: err = %v, want ErrPassphrase", err)
+	}
+	opened, err := parsed.Open("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := opened.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, c) {
+		t.Errorf("imported %+v, want %+v", got, c)
+	}
+	withoutSecrets, err := parsed.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, ok := withoutSecrets.Get("stt.api_key"); ok {
+		t.Errorf("opened key %v without a passphrase", key)
+	}
+}
+
+func TestParseExportReadsSettingsFile(t *testing.T) {
+	e, err := ParseExport([]byte(`{"assembly_api_key": "key", "turn_separator": "space"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := e.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A version 1 file is migrated, keeping its key since it isn't encrypted
+	if got, _ := v.Get("transcript.turn_separator"); got != "space" {
+		t.Errorf("turn separator = %v, want space", got)
+	}
+	if got, _ := v.Get("stt.api_key"); got != "key" {
+		t.Errorf("key = %v, want the file's", got)
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Secrets are the settings that hold credentials, by path. Exports leave
+// them out unless they're encrypted.
+var Secrets = []string{
+	"stt.api_key",
+	"llm.api_key",
+	"llm.compare.api_key",
+	"translate.deepl_api_key",
+	"translate.google_api_key",
+	"network.proxy_url", // May include a password
+	"meeting.calendar_url",
+}
+
+// ErrPassphrase is returned when an export's secrets can't be decrypted.
+var ErrPassphrase = errors.New("wrong passphrase")
+
+// Export is a settings file for moving a setup to another machine.
+type Export struct {
+	Settings Values  `json:"settings"`
+	Secrets  *Sealed `json:"secrets,omitempty"`
+}
+
+// Sealed is data encrypted with AES-GCM, under a key derived from a
+// passphrase with scrypt.
+type Sealed struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// NewExport exports v without its secrets, or with them encrypted if a
+// passphrase is given.
+func NewExport(v Values, passphrase string) (*Export, error) {
+	e := &Export{Settings: v.clone()}
+	secrets := make(Values)
+	for _, path := range Secrets {
+		if value, ok := e.Settings.Get(path); ok {
+			e.Settings.Delete(path)
+			if value != "" {
+				secrets.Set(path, value)
+			}
+		}
+	}
+	if passphrase == "" || len(secrets) == 0 {
+		return e, nil
+	}
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	if e.Secrets, err = seal(data, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to encrypt secrets: %v", err)
+	}
+	return e, nil
+}
+
+// ParseExport reads an export. A settings file is read as an export
+// without secrets, so one can be imported directly.
+func ParseExport(data []byte) (*Export, error) {
+	var e Export
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("not a settings export: %v", err)
+	}
+	if e.Settings == nil {
+		var v Values
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("not a settings export: %v", err)
+		}
+		e = Export{Settings: v}
+	}
+	e.Settings, _ = Migrate(e.Settings)
+	return &e, nil
+}
+
+// Open returns the exported settings, with the secrets decrypted. With no
+// passphrase the secrets are left out.
+func (e *Export) Open(passphrase string) (Values, error) {
+	v := e.Settings.clone()
+	if e.Secrets == nil || passphrase == "" {
+		return v, nil
+	}
+	data, err := e.Secrets.open(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var secrets Values
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secrets: %v", err)
+	}
+	for _, path := range Secrets {
+		if value, ok := secrets.Get(path); ok {
+			v.Set(path, value)
+		}
+	}
+	return v, nil
+}
+
+// Encrypted reports whether the export has secrets that need a passphrase.
+func (e *Export) Encrypted() bool {
+	return e.Secrets != nil
+}
+
+// clone returns a deep copy of v.
+func (v Values) clone() Values {
+	data, _ := json.Marshal(v)
+	var out Values
+	json.Unmarshal(data, &out)
+	return out
+}
+
+// gcm returns the cipher for passphrase and salt. The scrypt parameters are
+// the ones recommended for interactive logins.
+func gcm(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(data []byte, passphrase string) (*Sealed, error) {
+	s := &Sealed{Salt: make([]byte, 16)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := gcm(passphrase, s.Salt)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Data = aead.Seal(nil, s.Nonce, data, nil)
+	return s, nil
+}
+
+func (s *Sealed) open(passphrase string) ([]byte, error) {
+	aead, err := gcm(passphrase, s.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid secrets: nonce is %d bytes", len(s.Nonce))
+	}
+	data, err := aead.Open(nil, s.Nonce, s.Data, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return data, nil
+}
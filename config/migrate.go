@@ -0,0 +1,188 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Types of version 1 settings, which were all stored as strings
+const (
+	v1String = iota
+	v1Bool
+	v1Int
+	v1Float
+)
+
+// v1Settings maps each version 1 key to its path in the current format.
+var v1Settings = map[string]struct {
+	path string
+	kind int
+}{
+	"profile": {"profile", v1String},
+
+	"capture_source":    {"audio.capture_source", v1String},
+	"record_audio":      {"audio.record", v1Bool},
+	"vad_backend":       {"audio.vad_backend", v1String},
+	"vad_sensitivity":   {"audio.vad_sensitivity", v1String},
+	"auto_stop_silence": {"audio.auto_stop_silence", v1Int},
+	"session_limit":     {"audio.session_limit_minutes", v1Int},
+
+	"assembly_api_key":        {"stt.api_key", v1String},
+	"auto_language":           {"stt.auto_language", v1Bool},
+	"custom_vocabulary":       {"stt.vocabulary", v1String},
+	"end_of_turn_confidence":  {"stt.turn_detection.confidence", v1Float},
+	"min_end_of_turn_silence": {"stt.turn_detection.min_silence_ms", v1Int},
+	"max_turn_silence":        {"stt.turn_detection.max_silence_ms", v1Int},
+
+	"turn_placement":   {"transcript.turn_placement", v1String},
+	"turn_separator":   {"transcript.turn_separator", v1String},
+	"paragraph_pause":  {"transcript.paragraph_pause", v1Int},
+	"smart_join":       {"transcript.smart_join", v1Bool},
+	"show_latency":     {"transcript.show_latency", v1Bool},
+	"clear_after_copy": {"transcript.clear_after_copy", v1Bool},
+	"watch_keywords":   {"transcript.watch_keywords", v1String},
+	"strip_phrases":    {"transcript.strip_phrases", v1String},
+	"sounds_like":      {"transcript.sounds_like", v1String},
+	"text_snippets":    {"transcript.text_snippets", v1String},
+	"turn_filters":     {"transcript.turn_filters", v1String},
+	"redact_pii":       {"transcript.redact_pii", v1String},
+	"redact_names":     {"transcript.redact_names", v1String},
+	"filter_profanity": {"transcript.filter_profanity", v1Bool},
+	"spell_language":   {"transcript.spell_language", v1String},
+	"number_style":     {"transcript.numbers.style", v1String},
+	"currency_symbols": {"transcript.numbers.currency_symbols", v1Bool},
+	"unit_symbols":     {"transcript.numbers.unit_symbols", v1Bool},
+	"date_format":      {"transcript.numbers.date_format", v1String},
+	"phone_format":     {"transcript.numbers.phone_format", v1String},
+
+	"meeting_mixed":          {"meeting.mixed", v1Bool},
+	"speaker_me":             {"meeting.speaker_me", v1String},
+	"speaker_others":         {"meeting.speaker_others", v1String},
+	"consent_prompt":         {"meeting.consent_prompt", v1Bool},
+	"consent_checklist":      {"meeting.consent_checklist", v1String},
+	"consent_announce":       {"meeting.consent_announce", v1Bool},
+	"consent_announcement":   {"meeting.consent_announcement", v1String},
+	"meeting_notes":          {"meeting.notes", v1Bool},
+	"meeting_notes_pipeline": {"meeting.notes_pipeline", v1String},
+	"calendar_url":           {"meeting.calendar_url", v1String},
+
+	"snippet_inject": {"snippet.inject", v1String},
+	"snippet_format": {"snippet.format", v1Bool},
+
+	"hands_free":   {"hands_free.enabled", v1Bool},
+	"wake_word":    {"hands_free.wake_word", v1String},
+	"sleep_phrase": {"hands_free.sleep_phrase", v1String},
+	"pre_roll":     {"hands_free.pre_roll_ms", v1Int},
+
+	"lecture_mode":     {"lecture.enabled", v1Bool},
+	"lecture_interval": {"lecture.interval_minutes", v1Int},
+
+	"groq_api_key":          {"llm.api_key", v1String},
+	"groq_model":            {"llm.model", v1String},
+	"groq_endpoint":         {"llm.endpoint", v1String},
+	"system_prompt":         {"llm.system_prompt", v1String},
+	"llm_temperature":       {"llm.temperature", v1Float},
+	"llm_top_p":             {"llm.top_p", v1Float},
+	"llm_max_tokens":        {"llm.max_tokens", v1Int},
+	"llm_max_retries":       {"llm.max_retries", v1Int},
+	"llm_timeout":           {"llm.timeout", v1Int},
+	"clean_replies":         {"llm.clean_replies", v1Bool},
+	"reply_patterns":        {"llm.reply_patterns", v1String},
+	"compare_model":         {"llm.compare.model", v1String},
+	"compare_endpoint":      {"llm.compare.endpoint", v1String},
+	"compare_api_key":       {"llm.compare.api_key", v1String},
+	"compare_system_prompt": {"llm.compare.system_prompt", v1String},
+
+	"translate_provider":       {"translate.provider", v1String},
+	"deepl_api_key":            {"translate.deepl_api_key", v1String},
+	"google_translate_api_key": {"translate.google_api_key", v1String},
+	"translate_source":         {"translate.source", v1String},
+	"translate_target":         {"translate.target", v1String},
+
+	"sink_file_path":        {"sinks.file_path", v1String},
+	"sink_file_template":    {"sinks.file_template", v1String},
+	"sink_pipe_path":        {"sinks.pipe_path", v1String},
+	"sink_pipe_template":    {"sinks.pipe_template", v1String},
+	"sink_pipe_format":      {"sinks.pipe_format", v1String},
+	"sink_webhook_url":      {"sinks.webhook_url", v1String},
+	"sink_webhook_template": {"sinks.webhook_template", v1String},
+
+	"weekly_digest": {"digest.enabled", v1Bool},
+	"digest_day":    {"digest.day", v1Int},
+	"digest_folder": {"digest.folder", v1String},
+
+	"retain_audio_days":  {"storage.retain_audio_days", v1Int},
+	"retain_transcripts": {"storage.retain_transcript_months", v1Int},
+	"max_storage_mb":     {"storage.max_mb", v1Int},
+
+	"usage_audio_rate":      {"usage.audio_per_hour", v1Float},
+	"usage_prompt_rate":     {"usage.prompt_per_million", v1Float},
+	"usage_completion_rate": {"usage.completion_per_million", v1Float},
+	"usage_budget":          {"usage.monthly_budget", v1Float},
+
+	"proxy_url":          {"network.proxy_url", v1String},
+	"ca_cert_file":       {"network.ca_cert_file", v1String},
+	"managed_config_url": {"team.config_url", v1String},
+
+	"theme":           {"ui.theme", v1String},
+	"transcript_font": {"ui.transcript_font", v1String},
+	"font_size":       {"ui.font_size", v1Int},
+	"read_aloud_rate": {"ui.read_aloud_rate", v1Int},
+	"locale":          {"ui.locale", v1String},
+	"seen_hints":      {"ui.seen_hints", v1String},
+	"log_level":       {"ui.log_level", v1String},
+}
+
+// Migrate converts settings to the current version, returning the version
+// they were. Settings without a version whose values are all strings are
+// version 1; anything else without one, such as a team's settings written
+// by hand, is taken to be current.
+func Migrate(v Values) (Values, int) {
+	version, ok := v["version"].(float64)
+	if !ok {
+		for _, value := range v {
+			if _, ok := value.(string); !ok {
+				return v, Version
+			}
+		}
+		version = 1
+	}
+	if version == 1 {
+		return migrateV1(v), 1
+	}
+	return v, int(version)
+}
+
+// migrateV1 moves each setting into its section, with its type. Values that
+// don't parse are dropped, as version 1 ignored them.
+func migrateV1(v Values) Values {
+	out := Values{"version": float64(Version)}
+	for key, value := range v {
+		text, _ := value.(string)
+		if id, ok := strings.CutPrefix(key, "shortcut_"); ok {
+			out.Set("shortcuts."+id, text)
+			continue
+		}
+		setting, ok := v1Settings[key]
+		if !ok {
+			continue
+		}
+		switch setting.kind {
+		case v1String:
+			out.Set(setting.path, text)
+		case v1Bool:
+			if b, err := strconv.ParseBool(text); err == nil {
+				out.Set(setting.path, b)
+			}
+		case v1Int:
+			if n, err := strconv.Atoi(text); err == nil {
+				out.Set(setting.path, float64(n))
+			}
+		case v1Float:
+			if f, err := strconv.ParseFloat(text, 64); err == nil {
+				out.Set(setting.path, f)
+			}
+		}
+	}
+	return out
+}
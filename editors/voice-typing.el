@@ -0,0 +1,81 @@
+;;; voice-typing.el --- Receive dictation at point  -*- lexical-binding: t -*-
+
+;; Listens on a Unix socket for voice-typing's editor protocol and inserts
+;; each finalized turn at point in the selected window.
+;;
+;; In voice-typing, set Settings > Live Output > Pipe or Socket to the path
+;; in `voice-typing-socket' and check "Editor protocol".  Then:
+;;
+;;   (load "/path/to/voice-typing.el")
+;;   M-x voice-typing-listen
+;;
+;; Each utterance is its own undo step, except that a turn carrying on the
+;; previous turn's sentence ("undo": "join") is merged into its step.
+;; Requires Emacs 27 or later.
+
+;;; Code:
+
+(defvar voice-typing-socket
+  (expand-file-name "voice-typing.sock" temporary-file-directory)
+  "Socket path voice-typing writes turns to.")
+
+(defvar voice-typing--server nil)
+(defvar voice-typing--group nil
+  "Change group of the last utterance, for joining the next one to it.")
+
+(defun voice-typing--insert (msg)
+  "Insert the turn in MSG at point in the selected window."
+  (when (equal (gethash "type" msg) "insert")
+    (with-current-buffer (window-buffer (selected-window))
+      (unless buffer-read-only
+        (let ((text (gethash "text" msg))
+              (speaker (gethash "speaker" msg))
+              (join (and (equal (gethash "undo" msg) "join")
+                         voice-typing--group
+                         (eq (caar voice-typing--group) (current-buffer)))))
+          (when (and speaker (not (string-empty-p speaker)))
+            (setq text (concat speaker ": " text)))
+          (unless join
+            (undo-boundary)
+            (setq voice-typing--group (prepare-change-group)))
+          (unless (or (bolp) (memq (char-before) '(?\s ?\t ?\n)))
+            (insert " "))
+          (insert text)
+          (when join
+            (undo-amalgamate-change-group voice-typing--group)))))))
+
+(defun voice-typing--filter (proc string)
+  "Handle each complete line PROC has sent, keeping the rest of STRING."
+  (let ((pending (concat (or (process-get proc 'pending) "") string)))
+    (while (string-match "\n" pending)
+      (let ((line (substring pending 0 (match-beginning 0))))
+        (setq pending (substring pending (match-end 0)))
+        (unless (string-empty-p line)
+          (condition-case err
+              (voice-typing--insert (json-parse-string line))
+            (json-error (message "voice-typing: bad message: %S" err))))))
+    (process-put proc 'pending pending)))
+
+(defun voice-typing-listen ()
+  "Start receiving dictation on `voice-typing-socket'."
+  (interactive)
+  (voice-typing-stop)
+  (when (file-exists-p voice-typing-socket)
+    (delete-file voice-typing-socket))
+  (setq voice-typing--server
+        (make-network-process :name "voice-typing"
+                              :server t
+                              :family 'local
+                              :service voice-typing-socket
+                              :filter #'voice-typing--filter))
+  (message "Listening for dictation on %s" voice-typing-socket))
+
+(defun voice-typing-stop ()
+  "Stop receiving dictation."
+  (interactive)
+  (when voice-typing--server
+    (delete-process voice-typing--server)
+    (setq voice-typing--server nil)))
+
+(provide 'voice-typing)
+;;; voice-typing.el ends here
@@ -0,0 +1,91 @@
+-- voice-typing.lua: receive dictation at the cursor in Neovim.
+--
+-- Listens on a Unix socket for voice-typing's editor protocol and inserts
+-- each finalized turn at the cursor of the current window.
+--
+-- In voice-typing, set Settings > Live Output > Pipe or Socket to the path
+-- in M.socket and check "Editor protocol". Put this file on your runtimepath
+-- as lua/voice-typing.lua, then:
+--
+--   :lua require("voice-typing").listen()
+--
+-- Each utterance is its own undo step, except that a turn carrying on the
+-- previous turn's sentence ("undo": "join") is merged into its step.
+
+local M = {}
+
+M.socket = (vim.env.TMPDIR or "/tmp") .. "/voice-typing.sock"
+
+local uv = vim.uv or vim.loop
+local server
+
+local function insert(msg)
+  if msg.type ~= "insert" or not vim.bo.modifiable then
+    return
+  end
+  local text = msg.text
+  if msg.speaker and msg.speaker ~= "" then
+    text = msg.speaker .. ": " .. text
+  end
+  local row, col = unpack(vim.api.nvim_win_get_cursor(0))
+  local before = vim.api.nvim_get_current_line():sub(col, col)
+  if col > 0 and not before:match("%s") then
+    text = " " .. text
+  end
+  if msg.undo == "join" then
+    -- Fails if the user undid in between, which leaves a separate step
+    pcall(vim.cmd, "undojoin")
+  end
+  local lines = vim.split(text, "\n", { plain = true })
+  vim.api.nvim_buf_set_text(0, row - 1, col, row - 1, col, lines)
+  local last = lines[#lines]
+  if #lines == 1 then
+    vim.api.nvim_win_set_cursor(0, { row, col + #last })
+  else
+    vim.api.nvim_win_set_cursor(0, { row + #lines - 1, #last })
+  end
+end
+
+function M.listen()
+  M.stop()
+  uv.fs_unlink(M.socket)
+  server = uv.new_pipe(false)
+  local ok, err = server:bind(M.socket)
+  if not ok then
+    vim.notify("voice-typing: " .. err, vim.log.levels.ERROR)
+    return
+  end
+  server:listen(8, function()
+    local client = uv.new_pipe(false)
+    server:accept(client)
+    local pending = ""
+    client:read_start(function(read_err, data)
+      if read_err or not data then
+        client:close()
+        return
+      end
+      pending = pending .. data
+      for line in pending:gmatch("([^\n]*)\n") do
+        if line ~= "" then
+          vim.schedule(function()
+            local decoded, msg = pcall(vim.json.decode, line)
+            if decoded then
+              insert(msg)
+            end
+          end)
+        end
+      end
+      pending = pending:match("[^\n]*$")
+    end)
+  end)
+  vim.notify("Listening for dictation on " .. M.socket)
+end
+
+function M.stop()
+  if server then
+    server:close()
+    server = nil
+  end
+end
+
+return M
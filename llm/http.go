@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"dict/apierr"
+)
+
+// HTTPClient is a chat completions endpoint, such as Groq's, and its key.
+type HTTPClient struct {
+	Endpoint string
+	APIKey   string
+	HTTP     *http.Client
+}
+
+type chatResponse struct {
+	Choices []choice `json:"choices"`
+	Error   *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+type choice struct {
+	Message Message `json:"message"`
+}
+
+// Complete sends a request. Rate limits, server errors and failed
+// connections are returned as *apierr.RetryableError.
+func (c *HTTPClient) Complete(ctx context.Context, request Request) (Response, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.HTTP.Do(req)
+	if ctx.Err() != nil {
+		return Response{}, ctx.Err()
+	}
+	if err != nil {
+		return Response{}, &apierr.RetryableError{Err: &apierr.NetworkError{Service: "the LLM endpoint", Err: fmt.Errorf("failed to call Groq API: %v", err)}}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := apierr.FromStatus("Groq", resp.StatusCode, fmt.Errorf("Groq API error (status %d): %s", resp.StatusCode, string(body)))
+		if apierr.RetryableStatus(resp.StatusCode) {
+			return Response{}, &apierr.RetryableError{Err: err, RetryAfter: apierr.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return Response{}, err
+	}
+
+	var response chatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return Response{}, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if response.Error != nil {
+		return Response{}, fmt.Errorf("Groq API error: %s", response.Error.Message)
+	}
+
+	var result Response
+	if response.Usage != nil {
+		result.Usage = *response.Usage
+	}
+	if len(response.Choices) == 0 {
+		return result, fmt.Errorf("no response from Groq API")
+	}
+	result.Text = response.Choices[0].Message.Content
+	return result, nil
+}
+
+// modelsURL derives the OpenAI-compatible /models route from a chat
+// completions endpoint, e.g. https://api.groq.com/openai/v1/models.
+func modelsURL(endpoint string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint URL: %q", endpoint)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	path = strings.TrimSuffix(path, "/chat/completions")
+	path = strings.TrimSuffix(path, "/completions")
+	u.Path = path + "/models"
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// Models lists the model IDs the endpoint offers, sorted.
+func (c *HTTPClient) Models() ([]string, error) {
+	source, err := modelsURL(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, &apierr.NetworkError{Service: "the LLM endpoint", Err: fmt.Errorf("failed to fetch models: %v", err)}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read models: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apierr.FromStatus("Groq", resp.StatusCode, fmt.Errorf("failed to fetch models (status %d): %s", resp.StatusCode, string(body)))
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse models: %v", err)
+	}
+	var models []string
+	for _, m := range list.Data {
+		if m.ID != "" {
+			models = append(models, m.ID)
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
@@ -0,0 +1,70 @@
+// Package llm calls OpenAI-compatible chat completions endpoints.
+package llm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Client completes chat requests with a language model.
+type Client interface {
+	Complete(ctx context.Context, request Request) (Response, error)
+}
+
+type Request struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Params
+
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Response is a model's reply and the tokens it used.
+type Response struct {
+	Text  string
+	Usage Usage
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Params are optional sampling parameters sent with requests. Unset values
+// use the provider's defaults.
+type Params struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+// Override returns p with the values set in o replacing its own.
+func (p Params) Override(o Params) Params {
+	if o.Temperature != nil {
+		p.Temperature = o.Temperature
+	}
+	if o.MaxTokens > 0 {
+		p.MaxTokens = o.MaxTokens
+	}
+	if o.TopP != nil {
+		p.TopP = o.TopP
+	}
+	return p
+}
+
+// ResponseFormat asks the provider for JSON: matching a schema with
+// "json_schema", or any JSON object with "json_object" (JSON mode).
+type ResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type JSONSchemaFormat struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
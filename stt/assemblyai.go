@@ -0,0 +1,178 @@
+package stt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"dict/apierr"
+)
+
+const (
+	assemblyURL      = "wss://streaming.assemblyai.com/v3/ws"
+	assemblyTokenURL = "https://streaming.assemblyai.com/v3/token"
+)
+
+// AssemblyAI streams to AssemblyAI's Universal-Streaming API.
+type AssemblyAI struct {
+	Dialer *websocket.Dialer // Nil for websocket.DefaultDialer
+	URL    string            // Empty for the production API
+}
+
+func (s *AssemblyAI) Connect(opts Options) (Conn, error) {
+	params := url.Values{}
+	params.Set("sample_rate", strconv.Itoa(opts.SampleRate))
+	params.Set("format_turns", strconv.FormatBool(opts.FormatTurns))
+	params.Set("end_of_turn_confidence_threshold", strconv.FormatFloat(opts.EndOfTurnConfidence, 'f', 2, 64))
+	params.Set("min_end_of_turn_silence_when_confident", strconv.Itoa(opts.MinEndOfTurnSilence))
+	params.Set("max_turn_silence", strconv.Itoa(opts.MaxTurnSilence))
+	if opts.Multilingual {
+		params.Set("speech_model", "universal-streaming-multilingual")
+		params.Set("language_detection", "true")
+	}
+	if len(opts.Keyterms) > 0 {
+		keyterms, _ := json.Marshal(opts.Keyterms)
+		params.Set("keyterms_prompt", string(keyterms))
+	}
+
+	base := s.URL
+	if base == "" {
+		base = assemblyURL
+	}
+	wsURL := base + "?" + params.Encode()
+	slog.Debug("connecting to AssemblyAI", "url", wsURL)
+
+	dialer := s.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	headers := map[string][]string{"Authorization": {opts.APIKey}}
+	ws, resp, err := dialer.Dial(wsURL, headers)
+	if err != nil {
+		err = fmt.Errorf("failed to connect to AssemblyAI: %v", err)
+		if resp == nil {
+			return nil, &apierr.NetworkError{Service: "AssemblyAI", Err: err}
+		}
+		return nil, apierr.FromStatus("AssemblyAI", resp.StatusCode, err)
+	}
+	return &assemblyConn{ws: ws, trace: opts.Trace}, nil
+}
+
+// ChunkLimits reports the audio message lengths the service accepts.
+func (s *AssemblyAI) ChunkLimits() (min, max time.Duration) {
+	return 50 * time.Millisecond, time.Second
+}
+
+// CheckKey finds out whether apiKey works without streaming, by asking for
+// a short-lived streaming token, which costs nothing. Errors are typed as
+// in Connect.
+func (s *AssemblyAI) CheckKey(ctx context.Context, client *http.Client, apiKey string) error {
+	source, err := s.tokenURL()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", source+"?expires_in_seconds=60", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", apiKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		return &apierr.NetworkError{Service: "AssemblyAI", Err: fmt.Errorf("failed to reach AssemblyAI: %v", err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return apierr.FromStatus("AssemblyAI", resp.StatusCode, fmt.Errorf("AssemblyAI API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	return nil
+}
+
+// tokenURL is the token route beside the streaming URL.
+func (s *AssemblyAI) tokenURL() (string, error) {
+	if s.URL == "" {
+		return assemblyTokenURL, nil
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid AssemblyAI URL: %v", err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/ws") + "/token"
+	return u.String(), nil
+}
+
+type assemblyConn struct {
+	ws    *websocket.Conn
+	trace func(sent bool, data []byte)
+}
+
+func (c *assemblyConn) SendAudio(pcm []byte) error {
+	return c.ws.WriteMessage(websocket.BinaryMessage, pcm)
+}
+
+func (c *assemblyConn) EndTurn() error {
+	return c.sendText(`{"type":"ForceEndpoint"}`)
+}
+
+func (c *assemblyConn) Terminate() error {
+	return c.sendText(`{"type":"Terminate"}`)
+}
+
+func (c *assemblyConn) sendText(msg string) error {
+	if c.trace != nil {
+		c.trace(true, []byte(msg))
+	}
+	return c.ws.WriteMessage(websocket.TextMessage, []byte(msg))
+}
+
+func (c *assemblyConn) Receive() (Message, error) {
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return Message{}, io.EOF
+		}
+		if err != nil {
+			return Message{}, err
+		}
+		if c.trace != nil {
+			c.trace(false, data)
+		}
+
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			slog.Warn("invalid AssemblyAI message", "err", err)
+			continue
+		}
+		return msg, nil
+	}
+}
+
+func (c *assemblyConn) Ping(payload []byte, deadline time.Time) error {
+	return c.ws.WriteControl(websocket.PingMessage, payload, deadline)
+}
+
+func (c *assemblyConn) OnPong(handler func(payload string)) {
+	c.ws.SetPongHandler(func(data string) error {
+		handler(data)
+		return nil
+	})
+}
+
+func (c *assemblyConn) Close() error {
+	return c.ws.Close()
+}
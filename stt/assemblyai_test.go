@@ -0,0 +1,194 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"dict/apierr"
+)
+
+// serve runs handler as a WebSocket server, returning its URL.
+func serve(t *testing.T, handler func(r *http.Request, conn *websocket.Conn)) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(r, conn)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestConnectOptions(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	wsURL := serve(t, func(r *http.Request, conn *websocket.Conn) {
+		requests <- r
+	})
+	conn, err := (&AssemblyAI{URL: wsURL}).Connect(Options{
+		APIKey:              "key",
+		SampleRate:          16000,
+		FormatTurns:         true,
+		Multilingual:        true,
+		Keyterms:            []string{"Fyne", "malgo"},
+		EndOfTurnConfidence: 0.7,
+		MinEndOfTurnSilence: 160,
+		MaxTurnSilence:      2400,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	r := <-requests
+	if key := r.Header.Get("Authorization"); key != "key" {
+		t.Errorf("Authorization = %q, want the API key", key)
+	}
+	want := url.Values{
+		"sample_rate":                            {"16000"},
+		"format_turns":                           {"true"},
+		"end_of_turn_confidence_threshold":       {"0.70"},
+		"min_end_of_turn_silence_when_confident": {"160"},
+		"max_turn_silence":                       {"2400"},
+		"speech_model":                           {"universal-streaming-multilingual"},
+		"language_detection":                     {"true"},
+		"keyterms_prompt":                        {`["Fyne","malgo"]`},
+	}
+	if got := r.URL.Query(); got.Encode() != want.Encode() {
+		t.Errorf("query = %v, want %v", got, want)
+	}
+}
+
+func TestConnectErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	_, err := (&AssemblyAI{URL: "ws" + strings.TrimPrefix(srv.URL, "http")}).Connect(Options{})
+	var auth *apierr.AuthError
+	if !errors.As(err, &auth) {
+		t.Errorf("rejected key: err = %v, want an AuthError", err)
+	}
+
+	srv.Close()
+	_, err = (&AssemblyAI{URL: "ws" + strings.TrimPrefix(srv.URL, "http")}).Connect(Options{})
+	var network *apierr.NetworkError
+	if !errors.As(err, &network) {
+		t.Errorf("no server: err = %v, want a NetworkError", err)
+	}
+}
+
+func TestSession(t *testing.T) {
+	received := make(chan string, 10)
+	wsURL := serve(t, func(r *http.Request, conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Begin","id":"abc"}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`not json`))
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				received <- "audio:" + string(data)
+				continue
+			}
+			received <- string(data)
+			if strings.Contains(string(data), "Terminate") {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Turn","turn_order":2,"end_of_turn":true,"transcript":"Hi.","words":[{"text":"Hi.","start":10,"end":250,"confidence":0.9}]}`))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Termination","audio_duration_seconds":1.5}`))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			}
+		}
+	})
+	var traced []string
+	conn, err := (&AssemblyAI{URL: wsURL}).Connect(Options{
+		Trace: func(sent bool, data []byte) {
+			traced = append(traced, map[bool]string{true: "> ", false: "< "}[sent]+string(data))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if msg, err := conn.Receive(); err != nil || msg.Type != "Begin" || msg.ID != "abc" {
+		t.Fatalf("Receive = %+v, %v, want Begin", msg, err)
+	}
+	if err := conn.SendAudio([]byte("pcm")); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.EndTurn(); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"audio:pcm", `{"type":"ForceEndpoint"}`, `{"type":"Terminate"}`} {
+		if got := <-received; got != want {
+			t.Errorf("server received %q, want %q", got, want)
+		}
+	}
+
+	// The invalid message is skipped
+	msg, err := conn.Receive()
+	if err != nil || msg.Type != "Turn" || msg.TurnOrder != 2 || !msg.EndOfTurn || len(msg.Words) != 1 || msg.Words[0].End != 250 {
+		t.Errorf("Receive = %+v, %v, want the turn", msg, err)
+	}
+	if msg, err := conn.Receive(); err != nil || msg.Type != "Termination" || msg.AudioDurationSeconds != 1.5 {
+		t.Errorf("Receive = %+v, %v, want Termination", msg, err)
+	}
+	if _, err := conn.Receive(); err != io.EOF {
+		t.Errorf("Receive after the session ended: err = %v, want io.EOF", err)
+	}
+	if len(traced) != 6 || traced[1] != `> {"type":"ForceEndpoint"}` {
+		t.Errorf("traced %q, want the text messages both ways", traced)
+	}
+}
+
+func TestCheckKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/token" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Header.Get("Authorization") {
+		case "good":
+			io.WriteString(w, `{"token":"t","expires_in_seconds":60}`)
+		case "spent":
+			http.Error(w, `{"error":"insufficient balance"}`, http.StatusPaymentRequired)
+		default:
+			http.Error(w, `{"error":"Invalid API key"}`, http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	s := &AssemblyAI{URL: "ws" + strings.TrimPrefix(srv.URL, "http") + "/v3/ws"}
+	ctx := context.Background()
+
+	if err := s.CheckKey(ctx, srv.Client(), "good"); err != nil {
+		t.Errorf("good key: %v", err)
+	}
+	var auth *apierr.AuthError
+	if err := s.CheckKey(ctx, srv.Client(), "bad"); !errors.As(err, &auth) {
+		t.Errorf("bad key: err = %v, want an AuthError", err)
+	}
+	var quota *apierr.QuotaError
+	if err := s.CheckKey(ctx, srv.Client(), "spent"); !errors.As(err, &quota) {
+		t.Errorf("spent key: err = %v, want a QuotaError", err)
+	}
+	srv.Close()
+	var network *apierr.NetworkError
+	if err := s.CheckKey(ctx, srv.Client(), "good"); !errors.As(err, &network) {
+		t.Errorf("server down: err = %v, want a NetworkError", err)
+	}
+}
@@ -0,0 +1,197 @@
+package stt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Replay plays back a recorded session in place of the service, so the app
+// can be exercised without using API minutes. Audio sent to it is
+// discarded. Turns arrive at the pace they were spoken, going by their last
+// word's end time, and Termination once the session is terminated, as the
+// service would.
+type Replay struct {
+	messages [][]byte
+}
+
+// ParseReplay reads a message log: one JSON message per line, as received
+// from the service.
+func ParseReplay(data []byte) (*Replay, error) {
+	r := &Replay{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, fmt.Errorf("invalid message on line %d: %v", line, err)
+		}
+		r.messages = append(r.messages, append([]byte(nil), raw...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read message log: %v", err)
+	}
+	if len(r.messages) == 0 {
+		return nil, fmt.Errorf("the message log is empty")
+	}
+	return r, nil
+}
+
+func (r *Replay) Connect(opts Options) (Conn, error) {
+	return &replayConn{
+		messages:   r.messages,
+		trace:      opts.Trace,
+		started:    time.Now(),
+		terminated: make(chan struct{}),
+		closed:     make(chan struct{}),
+	}, nil
+}
+
+type replayConn struct {
+	messages [][]byte // Still to receive
+	ended    bool
+	trace    func(sent bool, data []byte)
+	started  time.Time
+
+	terminateOnce sync.Once
+	terminated    chan struct{}
+	closeOnce     sync.Once
+	closed        chan struct{}
+
+	mu     sync.Mutex
+	onPong func(payload string)
+}
+
+func (c *replayConn) SendAudio(pcm []byte) error {
+	return c.check()
+}
+
+func (c *replayConn) EndTurn() error {
+	return c.sendText(`{"type":"ForceEndpoint"}`)
+}
+
+func (c *replayConn) Terminate() error {
+	if err := c.sendText(`{"type":"Terminate"}`); err != nil {
+		return err
+	}
+	c.terminateOnce.Do(func() { close(c.terminated) })
+	return nil
+}
+
+func (c *replayConn) sendText(msg string) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	if c.trace != nil {
+		c.trace(true, []byte(msg))
+	}
+	return nil
+}
+
+func (c *replayConn) check() error {
+	select {
+	case <-c.closed:
+		return net.ErrClosed
+	default:
+		return nil
+	}
+}
+
+// Receive returns the log's messages in turn. The log's own Termination is
+// skipped: one is sent once the session is terminated, ending the replay
+// early if it's terminated before the log runs out.
+func (c *replayConn) Receive() (Message, error) {
+	if c.ended {
+		return Message{}, io.EOF
+	}
+
+	for len(c.messages) > 0 {
+		raw := c.messages[0]
+		var msg Message
+		json.Unmarshal(raw, &msg)
+		if msg.Type == "Termination" {
+			c.messages = c.messages[1:]
+			continue
+		}
+		if n := len(msg.Words); n > 0 {
+			if err := c.waitUntil(c.started.Add(time.Duration(msg.Words[n-1].End) * time.Millisecond)); err != nil {
+				return Message{}, err
+			}
+		}
+		select {
+		case <-c.terminated:
+			return c.terminate()
+		default:
+		}
+		c.messages = c.messages[1:]
+		c.received(raw)
+		return msg, nil
+	}
+
+	select {
+	case <-c.terminated:
+		return c.terminate()
+	case <-c.closed:
+		return Message{}, net.ErrClosed
+	}
+}
+
+// waitUntil waits for t, returning early once the session is terminated.
+func (c *replayConn) waitUntil(t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-c.terminated:
+	case <-c.closed:
+		return net.ErrClosed
+	}
+	return nil
+}
+
+func (c *replayConn) terminate() (Message, error) {
+	c.ended = true
+	raw := []byte(`{"type":"Termination"}`)
+	c.received(raw)
+	return Message{Type: "Termination"}, nil
+}
+
+func (c *replayConn) received(raw []byte) {
+	if c.trace != nil {
+		c.trace(false, raw)
+	}
+}
+
+// Ping is answered straight away.
+func (c *replayConn) Ping(payload []byte, deadline time.Time) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	onPong := c.onPong
+	c.mu.Unlock()
+	if onPong != nil {
+		go onPong(string(payload))
+	}
+	return nil
+}
+
+func (c *replayConn) OnPong(handler func(payload string)) {
+	c.mu.Lock()
+	c.onPong = handler
+	c.mu.Unlock()
+}
+
+func (c *replayConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
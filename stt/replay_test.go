@@ -0,0 +1,70 @@
+package stt
+
+import (
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+const replayLog = `{"type":"Begin","id":"1"}
+
+{"type":"Turn","turn_order":0,"end_of_turn":true,"transcript":"one","words":[{"text":"one","start":0,"end":100}]}
+{"type":"Turn","turn_order":1,"end_of_turn":true,"transcript":"two","words":[{"text":"two","start":150,"end":200}]}
+{"type":"Termination"}
+{"type":"Turn","turn_order":2,"end_of_turn":true,"transcript":"late","words":[{"text":"late","start":60000,"end":60100}]}
+`
+
+func TestReplay(t *testing.T) {
+	replay, err := ParseReplay([]byte(replayLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var traced int
+	start := time.Now()
+	conn, err := replay.Connect(Options{Trace: func(bool, []byte) { traced++ }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var transcripts []string
+	for i := 0; i < 3; i++ {
+		msg, err := conn.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		transcripts = append(transcripts, msg.Type+":"+msg.Transcript)
+	}
+	if got := time.Since(start); got < 200*time.Millisecond {
+		t.Errorf("received the turns after %v, want at the pace they were spoken", got)
+	}
+	if got, want := transcripts, []string{"Begin:", "Turn:one", "Turn:two"}; !slices.Equal(got, want) {
+		t.Errorf("received %q, want %q", got, want)
+	}
+
+	// Terminating skips the rest of the log
+	if err := conn.SendAudio(make([]byte, 320)); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := conn.Receive(); err != nil || msg.Type != "Termination" {
+		t.Fatalf("Receive() = %+v, %v, want Termination", msg, err)
+	}
+	if _, err := conn.Receive(); err != io.EOF {
+		t.Errorf("Receive() after Termination = %v, want io.EOF", err)
+	}
+	if traced != 5 {
+		t.Errorf("traced %d messages, want 5", traced)
+	}
+}
+
+func TestParseReplayErrors(t *testing.T) {
+	for _, log := range []string{"", "\n\n", `{"type":"Begin"}` + "\nnot json\n"} {
+		if _, err := ParseReplay([]byte(log)); err == nil {
+			t.Errorf("ParseReplay(%q) succeeded, want an error", log)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package stt streams audio to a speech-to-text service and receives its
+// transcripts as they're recognized.
+package stt
+
+import "time"
+
+// Streamer opens streaming transcription sessions.
+type Streamer interface {
+	Connect(opts Options) (Conn, error)
+}
+
+// ChunkLimiter is implemented by streamers whose service only accepts audio
+// messages of a certain length.
+type ChunkLimiter interface {
+	ChunkLimits() (min, max time.Duration)
+}
+
+// Conn is one streaming session. SendAudio, EndTurn and Terminate must be
+// called from one goroutine at a time, and Receive from another; Ping may be
+// called from any.
+type Conn interface {
+	SendAudio(pcm []byte) error
+	// EndTurn ends the current turn, so its final transcript is sent.
+	EndTurn() error
+	// Terminate asks the service to end the session, which it does once
+	// the final transcripts are sent.
+	Terminate() error
+	// Receive returns the next message, or io.EOF once the session has
+	// ended normally.
+	Receive() (Message, error)
+	// Ping checks the connection; the payload comes back to OnPong.
+	Ping(payload []byte, deadline time.Time) error
+	OnPong(handler func(payload string))
+	Close() error
+}
+
+// Options configure a session.
+type Options struct {
+	APIKey      string
+	SampleRate  int
+	FormatTurns bool
+
+	// Multilingual transcribes any supported language, reporting which
+	// it heard.
+	Multilingual bool
+	// Keyterms are words and phrases to favor in recognition.
+	Keyterms []string
+
+	// End-of-turn tuning; see TurnDetection in the settings
+	EndOfTurnConfidence float64
+	MinEndOfTurnSilence int // Milliseconds
+	MaxTurnSilence      int // Milliseconds
+
+	// Trace, if set, is passed each text message sent and received, for
+	// the protocol inspector.
+	Trace func(sent bool, data []byte)
+}
+
+// Message is a message from the service: "Begin", "Turn" or "Termination".
+type Message struct {
+	Type                   string  `json:"type"`
+	ID                     string  `json:"id,omitempty"`
+	ExpiresAt              int64   `json:"expires_at,omitempty"`
+	Transcript             string  `json:"transcript,omitempty"`
+	TurnIsFormatted        bool    `json:"turn_is_formatted,omitempty"`
+	EndOfTurn              bool    `json:"end_of_turn,omitempty"`
+	TurnOrder              int     `json:"turn_order,omitempty"`
+	AudioDurationSeconds   float64 `json:"audio_duration_seconds,omitempty"`
+	SessionDurationSeconds float64 `json:"session_duration_seconds,omitempty"`
+	Words                  []Word  `json:"words,omitempty"`
+	LanguageCode           string  `json:"language_code,omitempty"`
+	LanguageConfidence     float64 `json:"language_confidence,omitempty"`
+}
+
+// Word times are milliseconds since the start of the session's audio.
+type Word struct {
+	Text        string  `json:"text"`
+	Start       int64   `json:"start"`
+	End         int64   `json:"end"`
+	Confidence  float64 `json:"confidence"`
+	WordIsFinal bool    `json:"word_is_final"`
+}
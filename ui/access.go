@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+// Fyne doesn't expose its widgets to screen readers, so spoken feedback
+// stands in: with Announce set, status changes and the control that has
+// keyboard focus are read out with the system's text to speech.
+
+const (
+	announceTimeout   = 30 * time.Second
+	focusPollInterval = 200 * time.Millisecond
+)
+
+// Announcer speaks one announcement at a time; a new one interrupts it.
+type Announcer struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	quiet  bool          // While the microphone is open
+	stop   chan struct{} // Closed to stop following focus
+}
+
+// announce speaks text if spoken feedback is on. Call it from any
+// goroutine.
+func (a *App) announce(text string) {
+	cfg := a.settings()
+	if !cfg.Announce || strings.TrimSpace(text) == "" {
+		return
+	}
+	an := &a.announcer
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	if an.quiet {
+		return
+	}
+	if an.cancel != nil {
+		an.cancel()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), announceTimeout)
+	an.cancel = cancel
+	go func() {
+		defer cancel()
+		pcm, rate, err := synthesizeSpeech(ctx, text, cfg.ReadAloudRate)
+		if err == nil {
+			err = audio.Play(ctx, pcm, rate, nil)
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("announcement failed", "err", err)
+		}
+	}()
+}
+
+// hushAnnouncements stops announcing while recording, so announcements
+// aren't transcribed.
+func (a *App) hushAnnouncements() {
+	an := &a.announcer
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	an.quiet = true
+	if an.cancel != nil {
+		an.cancel()
+		an.cancel = nil
+	}
+}
+
+func (a *App) resumeAnnouncements() {
+	a.announcer.mu.Lock()
+	a.announcer.quiet = false
+	a.announcer.mu.Unlock()
+}
+
+// applyAnnounce follows keyboard focus, announcing each control as it's
+// reached, while spoken feedback is on.
+func (a *App) applyAnnounce() {
+	if a.announcer.stop != nil {
+		close(a.announcer.stop)
+		a.announcer.stop = nil
+	}
+	if !a.settings().Announce {
+		return
+	}
+	stop := make(chan struct{})
+	a.announcer.stop = stop
+	go func() {
+		ticker := time.NewTicker(focusPollInterval)
+		defer ticker.Stop()
+		var last fyne.Focusable
+		var lastName string
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			fyne.DoAndWait(func() {
+				c := a.window.Canvas()
+				focused := c.Focused()
+				if focused == nil {
+					last, lastName = nil, ""
+					return
+				}
+				root := c.Content()
+				if top := c.Overlays().Top(); top != nil {
+					root = top
+				}
+				// A control is announced again when its state changes,
+				// such as a check box toggled with Space
+				obj, _ := focused.(fyne.CanvasObject)
+				name := accessibleName(focused, controlLabel(root, obj))
+				if focused == last && name == lastName {
+					return
+				}
+				last, lastName = focused, name
+				a.announce(name)
+			})
+		}
+	}()
+}
+
+// focusTranscript moves keyboard focus to the transcript, or to the record
+// button while the live view is shown.
+func (a *App) focusTranscript() {
+	if a.textArea.Visible() {
+		a.window.Canvas().Focus(a.textArea)
+	} else {
+		a.window.Canvas().Focus(a.recordBtn)
+	}
+}
+
+// accessibleName describes a control the way a screen reader would: its
+// name, role and state. label is the text labelling it in the layout, if
+// any.
+func accessibleName(obj fyne.Focusable, label string) string {
+	var parts []string
+	add := func(s ...string) {
+		for _, part := range s {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	checked := func(on bool) string {
+		if on {
+			return tr("checked")
+		}
+		return tr("not checked")
+	}
+	if e := asEntry(obj); e != nil {
+		name := label
+		if name == "" {
+			name = e.PlaceHolder
+		}
+		role := tr("text field")
+		if e.Password {
+			role = tr("password field")
+		}
+		add(name, role)
+		if e.Disabled() {
+			add(tr("unavailable"))
+		}
+		return strings.Join(parts, ", ")
+	}
+	switch w := obj.(type) {
+	case *TipButton:
+		add(w.Text, w.Tip, tr("button"))
+		if w.Disabled() {
+			add(tr("unavailable"))
+		}
+	case *widget.Button:
+		add(label, w.Text, tr("button"))
+		if w.Disabled() {
+			add(tr("unavailable"))
+		}
+	case *widget.Check:
+		add(w.Text, tr("check box"), checked(w.Checked))
+	case *widget.Select:
+		add(label, w.Selected, tr("pop-up button"))
+	case *widget.SelectEntry:
+		add(label, w.Text, tr("combo box"))
+	case *widget.Slider:
+		add(label, tr("slider"), strconv.FormatFloat(w.Value, 'f', -1, 64))
+	case *widget.List:
+		add(label, tr("list"))
+	case *misspeltWord:
+		add(w.word, tr("unknown word, press Space for suggestions"))
+	default:
+		add(label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// asEntry returns the text entry obj is, or nil.
+func asEntry(obj fyne.Focusable) *widget.Entry {
+	switch e := obj.(type) {
+	case *widget.Entry:
+		return e
+	case *TextArea:
+		return &e.Entry
+	case *TranscriptEntry:
+		return &e.Entry
+	case *PaletteEntry:
+		return &e.Entry
+	}
+	return nil
+}
+
+var borderLayoutType = reflect.TypeOf(layout.NewBorderLayout(nil, nil, nil, nil))
+
+// controlLabel finds the text labelling obj under root: its form item's
+// label, or else the label beside or above it in its container.
+func controlLabel(root, obj fyne.CanvasObject) string {
+	switch o := root.(type) {
+	case *widget.Form:
+		for _, item := range o.Items {
+			if contains(item.Widget, obj) {
+				if label := controlLabel(item.Widget, obj); label != "" {
+					return label
+				}
+				return strings.TrimSuffix(item.Text, ":")
+			}
+		}
+	case *fyne.Container:
+		for i, child := range o.Objects {
+			if !contains(child, obj) {
+				continue
+			}
+			if label := controlLabel(child, obj); label != "" {
+				return label
+			}
+			// The label beside it in a border layout, or just above it
+			if reflect.TypeOf(o.Layout) == borderLayoutType {
+				for _, sibling := range o.Objects {
+					if l, ok := sibling.(*widget.Label); ok {
+						return strings.TrimSuffix(l.Text, ":")
+					}
+				}
+			} else if i > 0 {
+				if l, ok := o.Objects[i-1].(*widget.Label); ok {
+					return strings.TrimSuffix(l.Text, ":")
+				}
+			}
+			return ""
+		}
+	default:
+		for _, child := range children(root) {
+			if contains(child, obj) {
+				return controlLabel(child, obj)
+			}
+		}
+	}
+	return ""
+}
+
+// contains reports whether obj is root or inside it.
+func contains(root, obj fyne.CanvasObject) bool {
+	if root == obj {
+		return true
+	}
+	if c, ok := root.(*fyne.Container); ok {
+		for _, child := range c.Objects {
+			if contains(child, obj) {
+				return true
+			}
+		}
+	}
+	for _, child := range children(root) {
+		if contains(child, obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the content of the widgets that hold other objects.
+func children(o fyne.CanvasObject) []fyne.CanvasObject {
+	switch w := o.(type) {
+	case *widget.PopUp:
+		return []fyne.CanvasObject{w.Content}
+	case *container.Scroll:
+		return []fyne.CanvasObject{w.Content}
+	case *container.Split:
+		return []fyne.CanvasObject{w.Leading, w.Trailing}
+	case *container.AppTabs:
+		var out []fyne.CanvasObject
+		for _, item := range w.Items {
+			out = append(out, item.Content)
+		}
+		return out
+	case *container.DocTabs:
+		var out []fyne.CanvasObject
+		for _, item := range w.Items {
+			out = append(out, item.Content)
+		}
+		return out
+	case *widget.Form:
+		var out []fyne.CanvasObject
+		for _, item := range w.Items {
+			out = append(out, item.Widget)
+		}
+		return out
+	case *widget.Accordion:
+		var out []fyne.CanvasObject
+		for _, item := range w.Items {
+			out = append(out, item.Detail)
+		}
+		return out
+	}
+	return nil
+}
+
+// TextArea is a multi-line entry that leaves Tab to move focus, so keyboard
+// users can get out of it again.
+type TextArea struct {
+	widget.Entry
+}
+
+func newTextArea() *TextArea {
+	e := &TextArea{}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *TextArea) AcceptsTab() bool {
+	return false
+}
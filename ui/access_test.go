@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestAccessibleNames(t *testing.T) {
+	test.NewTempApp(t)
+	key := widget.NewPasswordEntry()
+	testBtn := widget.NewButton("Test", nil)
+	size := widget.NewEntry()
+	themeSelect := widget.NewSelect([]string{"Dark", "Light"}, nil)
+	themeSelect.SetSelected("Dark")
+	check := widget.NewCheck("Smart joining", nil)
+	check.SetChecked(true)
+	help := newTipButton("", nil, "Help: shortcuts", nil)
+	notes := newTextArea()
+	notes.SetPlaceHolder("One name per line")
+	root := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("API Key:", container.NewBorder(nil, nil, nil, testBtn, key))),
+		container.NewBorder(nil, nil, widget.NewLabel("Text size:"), nil, size),
+		widget.NewLabel("Theme:"),
+		themeSelect,
+		check,
+		container.NewVScroll(container.NewHBox(help)),
+		notes,
+	)
+
+	for _, tt := range []struct {
+		obj  fyne.Focusable
+		want string
+	}{
+		{key, "API Key, password field"},
+		{testBtn, "API Key, Test, button"},
+		{size, "Text size, text field"},
+		{themeSelect, "Theme, Dark, pop-up button"},
+		{check, "Smart joining, check box, checked"},
+		{help, "Help: shortcuts, button"},
+		{notes, "One name per line, text field"},
+	} {
+		if got := accessibleName(tt.obj, controlLabel(root, tt.obj.(fyne.CanvasObject))); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
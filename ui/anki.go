@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"dict/apierr"
+)
+
+const ankiPrompt = `Turn the following study notes into flashcards for spaced repetition.
+Write one card per fact or idea: a short, specific question and a concise answer.
+Reply with JSON only, in the form {"cards": [{"question": "...", "answer": "..."}]}.`
+
+// Flashcard is a question/answer pair for Anki.
+type Flashcard struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// parseFlashcards reads the cards from an LLM reply, ignoring any text or
+// code fences around the JSON.
+func parseFlashcards(reply string) ([]Flashcard, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("the LLM reply contained no flashcards")
+	}
+	var parsed struct {
+		Cards []Flashcard `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse flashcards: %v", err)
+	}
+	var cards []Flashcard
+	for _, card := range parsed.Cards {
+		card.Question = strings.TrimSpace(card.Question)
+		card.Answer = strings.TrimSpace(card.Answer)
+		if card.Question != "" && card.Answer != "" {
+			cards = append(cards, card)
+		}
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("the LLM reply contained no flashcards")
+	}
+	return cards, nil
+}
+
+// ankiField escapes a field for an HTML-enabled Anki text import, where tabs
+// separate fields and newlines separate notes.
+func ankiField(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, "\t", " ")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// formatAnkiTSV writes cards as a tab-separated file that Anki's File →
+// Import recognises from its header lines.
+func formatAnkiTSV(cards []Flashcard) []byte {
+	var b strings.Builder
+	b.WriteString("#separator:tab\n#html:true\n#columns:Front\tBack\n#tags:voice-typing\n")
+	for _, card := range cards {
+		b.WriteString(ankiField(card.Question) + "\t" + ankiField(card.Answer) + "\n")
+	}
+	return []byte(b.String())
+}
+
+// exportAnki asks the LLM to write flashcards from the transcript and saves
+// them for import into Anki.
+func (a *App) exportAnki() {
+	cfg := a.settings()
+	if cfg.GroqAPIKey == "" {
+		a.showError(&apierr.AuthError{Service: "Groq", Err: errors.New("Please configure Groq API key in Settings")})
+		return
+	}
+	text := a.textArea.Text
+	if strings.TrimSpace(text) == "" {
+		a.updateStatus(tr("No text to export"))
+		return
+	}
+
+	a.updateStatus(tr("Writing flashcards with LLM..."))
+	ctx, done := a.startLLMTask()
+	go func() {
+		reply, err := a.callGroqModel(ctx, cfg.GroqModel, cfg.LLMParams, ankiPrompt, text)
+		var cards []Flashcard
+		if err == nil {
+			cards, err = parseFlashcards(reply)
+		}
+		fyne.Do(func() {
+			done()
+			if err := a.llmError(err); err != nil {
+				a.updateStatus(tr("Flashcards failed: %s", err))
+				a.showError(err)
+				return
+			}
+			if cards == nil {
+				a.updateStatus(tr("Flashcards cancelled"))
+				return
+			}
+			slog.Info("flashcards written", "cards", len(cards))
+			a.notify(tr("Flashcards are ready to save"), "")
+			a.saveExport("flashcards.txt", formatAnkiTSV(cards), "Anki")
+		})
+	}()
+}
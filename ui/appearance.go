@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	themeSystem = "system"
+	themeDark   = "dark"
+	themeLight  = "light"
+
+	fontDefault   = ""
+	fontMonospace = "monospace"
+
+	defaultFontSize = 14 // Fyne's default text size
+	minFontSize     = 8
+	maxFontSize     = 48
+	fontSizeStep    = 2
+)
+
+var themeLabels = map[string]string{
+	themeSystem: trMark("Follow system"),
+	themeDark:   trMark("Dark"),
+	themeLight:  trMark("Light"),
+}
+
+// AppTheme is the default theme, optionally forced to dark or light.
+type AppTheme struct {
+	fyne.Theme
+	variant string
+}
+
+func (t *AppTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case themeDark:
+		variant = theme.VariantDark
+	case themeLight:
+		variant = theme.VariantLight
+	}
+	return t.Theme.Color(name, variant)
+}
+
+// TranscriptTheme sets the transcript's font and text size, leaving the rest
+// of the window alone.
+type TranscriptTheme struct {
+	fyne.Theme
+	size      float32
+	font      fyne.Resource // Nil for the theme's font
+	monospace bool
+}
+
+func (t *TranscriptTheme) Size(name fyne.ThemeSizeName) float32 {
+	if name == theme.SizeNameText {
+		return t.size
+	}
+	// Keep captions, such as the latency badge, in proportion
+	if name == theme.SizeNameCaptionText {
+		return t.size * t.Theme.Size(name) / t.Theme.Size(theme.SizeNameText)
+	}
+	return t.Theme.Size(name)
+}
+
+func (t *TranscriptTheme) Font(style fyne.TextStyle) fyne.Resource {
+	if t.font != nil && !style.Monospace && !style.Symbol {
+		return t.font
+	}
+	if t.monospace && !style.Symbol {
+		style.Monospace = true
+	}
+	return t.Theme.Font(style)
+}
+
+// loadFont reads a TrueType or OpenType font for the transcript.
+func loadFont(path string) (fyne.Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return fyne.NewStaticResource(filepath.Base(path), data), nil
+}
+
+// transcriptTheme builds the transcript's theme from the settings.
+func (a *App) transcriptTheme(cfg *Settings) fyne.Theme {
+	t := &TranscriptTheme{Theme: a.fyneApp.Settings().Theme(), size: float32(cfg.FontSize)}
+	switch cfg.TranscriptFont {
+	case fontDefault:
+	case fontMonospace:
+		t.monospace = true
+	default:
+		font, err := loadFont(cfg.TranscriptFont)
+		if err != nil {
+			slog.Warn("using the default transcript font", "font", cfg.TranscriptFont, "err", err)
+			break
+		}
+		t.font = font
+	}
+	return t
+}
+
+// newAppearanceView wraps the transcript so its font can differ from the
+// rest of the window.
+func (a *App) newAppearanceView(transcript fyne.CanvasObject) fyne.CanvasObject {
+	a.transcriptOverride = container.NewThemeOverride(transcript, theme.DefaultTheme())
+	return a.transcriptOverride
+}
+
+// applyAppearance applies the theme, font and text size from the settings.
+func (a *App) applyAppearance() {
+	cfg := a.settings()
+	a.fyneApp.Settings().SetTheme(&AppTheme{Theme: theme.DefaultTheme(), variant: cfg.Theme})
+	if a.transcriptOverride != nil {
+		a.transcriptOverride.Theme = a.transcriptTheme(cfg)
+		a.transcriptOverride.Refresh()
+	}
+}
+
+// zoom changes the transcript's text size by steps, or resets it with 0,
+// and saves it.
+func (a *App) zoom(steps int) {
+	a.updateSettings(func(s *Settings) {
+		if steps == 0 {
+			s.FontSize = defaultFontSize
+			return
+		}
+		s.FontSize = min(max(s.FontSize+steps*fontSizeStep, minFontSize), maxFontSize)
+	})
+	a.applyAppearance()
+	a.updateStatus(tr("Text size %d", a.settings().FontSize))
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		slog.Warn("failed to save text size", "err", err)
+	}
+}
+
+func (a *App) zoomIn()    { a.zoom(1) }
+func (a *App) zoomOut()   { a.zoom(-1) }
+func (a *App) zoomReset() { a.zoom(0) }
+
+// newAppearanceSettings is the Appearance section of the settings dialog.
+func newAppearanceSettings(cfg *Settings) (fyne.CanvasObject, func(s *Settings), func() error) {
+	themeSelect := widget.NewSelect([]string{tr(themeLabels[themeSystem]), tr(themeLabels[themeDark]), tr(themeLabels[themeLight])}, nil)
+	themeSelect.SetSelected(tr(themeLabels[themeSystem]))
+	if label, ok := themeLabels[cfg.Theme]; ok {
+		themeSelect.SetSelected(tr(label))
+	}
+
+	fontEntry := widget.NewSelectEntry([]string{"Default", "Monospace"})
+	fontEntry.SetPlaceHolder(tr("Default, Monospace or a .ttf/.otf file"))
+	switch cfg.TranscriptFont {
+	case fontDefault:
+		fontEntry.SetText("Default")
+	case fontMonospace:
+		fontEntry.SetText("Monospace")
+	default:
+		fontEntry.SetText(cfg.TranscriptFont)
+	}
+	sizeEntry := widget.NewEntry()
+	sizeEntry.SetText(strconv.Itoa(cfg.FontSize))
+	languageSelect := widget.NewSelect([]string{tr("Follow system")}, nil)
+	languageSelect.SetSelectedIndex(0)
+	for _, code := range uiLanguageOrder {
+		languageSelect.Options = append(languageSelect.Options, uiLanguages[code])
+		if code == cfg.UILanguage {
+			languageSelect.SetSelected(uiLanguages[code])
+		}
+	}
+
+	announceCheck := widget.NewCheck(tr("Spoken feedback: read out status changes and the focused control"), nil)
+	announceCheck.SetChecked(cfg.Announce)
+	soundCuesCheck := widget.NewCheck(tr("Sound cues: beep when recording starts, stops, reconnects or fails"), nil)
+	soundCuesCheck.SetChecked(cfg.SoundCues)
+	flashCuesCheck := widget.NewCheck(tr("Flash the window icon then too, and show a red one while recording"), nil)
+	flashCuesCheck.SetChecked(cfg.FlashCues)
+	notificationsCheck := widget.NewCheck(tr("Desktop notifications when results are ready or errors happen while the window is in the background"), nil)
+	notificationsCheck.SetChecked(cfg.Notifications)
+
+	readFont := func() string {
+		switch text := fontEntry.Text; text {
+		case "", "Default":
+			return fontDefault
+		case "Monospace":
+			return fontMonospace
+		default:
+			return text
+		}
+	}
+	content := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Theme:")), nil, themeSelect),
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Transcript font:")), nil, fontEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Text size (Ctrl+= / Ctrl+- to zoom):")), nil, sizeEntry),
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Language:")), nil, languageSelect),
+		announceCheck,
+		soundCuesCheck,
+		flashCuesCheck,
+		notificationsCheck,
+	)
+	read := func(s *Settings) {
+		for variant, label := range themeLabels {
+			if tr(label) == themeSelect.Selected {
+				s.Theme = variant
+			}
+		}
+		s.TranscriptFont = readFont()
+		if size, err := strconv.Atoi(sizeEntry.Text); err == nil {
+			s.FontSize = size
+		}
+		s.UILanguage = uiLanguageSystem
+		if i := languageSelect.SelectedIndex(); i > 0 {
+			s.UILanguage = uiLanguageOrder[i-1]
+		}
+		s.Announce = announceCheck.Checked
+		s.SoundCues = soundCuesCheck.Checked
+		s.FlashCues = flashCuesCheck.Checked
+		s.Notifications = notificationsCheck.Checked
+	}
+	validate := func() error {
+		if size, err := strconv.Atoi(sizeEntry.Text); err != nil || size < minFontSize || size > maxFontSize {
+			return fmt.Errorf("Text size must be a whole number from %d to %d", minFontSize, maxFontSize)
+		}
+		if font := readFont(); font != fontDefault && font != fontMonospace {
+			if _, err := loadFont(font); err != nil {
+				return fmt.Errorf("Can't load the transcript font: %v", err)
+			}
+		}
+		return nil
+	}
+	return content, read, validate
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	maxKeyterms      = 100 // AssemblyAI's limit on keyterms_prompt
+	maxKeytermLength = 50
+)
+
+// Phrases that make a sentence mentioning someone an action item for them.
+var actionPhrases = []string{
+	"will ", "'ll ", "can you", "could you", "would you", "please", "action item",
+	"to do", "follow up", "take care of", "assign", "needs to", "need you to", "should ", "by tomorrow", "by friday",
+}
+
+var sentencePattern = regexp.MustCompile(`[^.!?]+[.!?]*`)
+
+// PersonSummary lists where an attendee was mentioned and what they were
+// asked to do.
+type PersonSummary struct {
+	Name        string       `json:"name"`
+	Mentions    []ExportTurn `json:"mentions,omitempty"`
+	ActionItems []string     `json:"action_items,omitempty"`
+}
+
+// parseAttendees reads names one per line or comma separated.
+func parseAttendees(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' || r == ';' }) {
+		name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+		if name != "" && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// mentionPattern matches an attendee by full or first name, optionally
+// written as an @mention.
+func mentionPattern(name string) *regexp.Regexp {
+	alternatives := []string{regexp.QuoteMeta(name)}
+	if first, _, ok := strings.Cut(name, " "); ok && len(first) > 1 {
+		alternatives = append(alternatives, regexp.QuoteMeta(first))
+	}
+	return regexp.MustCompile(`(?i)(^|[^\pL\pN])@?(` + strings.Join(alternatives, "|") + `)($|[^\pL\pN])`)
+}
+
+func isActionItem(sentence string) bool {
+	lower := strings.ToLower(sentence)
+	for _, phrase := range actionPhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizePeople finds each attendee's mentions in the turns, and the
+// sentences that look like tasks for them.
+func summarizePeople(attendees []string, turns []ExportTurn) []PersonSummary {
+	var people []PersonSummary
+	for _, name := range attendees {
+		pattern := mentionPattern(name)
+		person := PersonSummary{Name: name}
+		for _, turn := range turns {
+			if !pattern.MatchString(turn.Text) {
+				continue
+			}
+			person.Mentions = append(person.Mentions, turn)
+			for _, sentence := range sentencePattern.FindAllString(turn.Text, -1) {
+				sentence = strings.TrimSpace(sentence)
+				if pattern.MatchString(sentence) && isActionItem(sentence) {
+					person.ActionItems = append(person.ActionItems, sentence)
+				}
+			}
+		}
+		people = append(people, person)
+	}
+	return people
+}
+
+// withAttendeeKeyterms adds the attendees to the vocabulary boosted in
+// transcription, within AssemblyAI's limits.
+func withAttendeeKeyterms(cfg *Settings, attendees []string) *Settings {
+	if len(attendees) == 0 {
+		return cfg
+	}
+	s := *cfg
+	s.Vocabulary = append([]string{}, cfg.Vocabulary...)
+	for _, name := range attendees {
+		if len(s.Vocabulary) == maxKeyterms {
+			break
+		}
+		if len(name) <= maxKeytermLength {
+			s.Vocabulary = append(s.Vocabulary, name)
+		}
+	}
+	return &s
+}
+
+// showAttendees edits the meeting title and attendee roster, and shows how
+// often each attendee has been mentioned so far.
+func (a *App) showAttendees() {
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder(tr("Meeting title (optional)"))
+	titleEntry.SetText(a.sessionTitle)
+	attendeesEntry := newTextArea()
+	attendeesEntry.SetPlaceHolder(tr("One name per line"))
+	attendeesEntry.SetText(strings.Join(a.sessionAttendees, "\n"))
+	attendeesEntry.SetMinRowsVisible(6)
+
+	var mentions []string
+	for _, person := range summarizePeople(a.sessionAttendees, a.exportDocument().Turns) {
+		mentions = append(mentions, fmt.Sprintf("%s: %d mentions, %d action items", person.Name, len(person.Mentions), len(person.ActionItems)))
+	}
+	mentionsLbl := widget.NewLabel(strings.Join(mentions, "\n"))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Title"), titleEntry),
+		widget.NewFormItem(tr("Attendees"), attendeesEntry),
+	}
+	if len(mentions) > 0 {
+		items = append(items, widget.NewFormItem(tr("So far"), mentionsLbl))
+	}
+	d := dialog.NewForm(tr("Meeting Attendees"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		attendees := parseAttendees(attendeesEntry.Text)
+		slog.Info("attendees set", "count", len(attendees))
+		a.setSession(strings.TrimSpace(titleEntry.Text), attendees)
+		if a.recording.Load() {
+			a.updateStatus(tr("Attendees saved — names are boosted from the next recording"))
+		}
+	}, a.window)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSpeakerMe     = "Me"
+	defaultSpeakerOthers = "Others"
+
+	echoWindow     = 1500 * time.Millisecond // Slack between the two streams' timings
+	echoSimilarity = 0.6                     // Share of the mic turn's words also heard from the call
+)
+
+// Streams in a meeting recorded as two streams
+const (
+	streamMe     = 0
+	streamOthers = 1
+)
+
+// speakerLabels returns the labels for the microphone and system audio
+// streams of a meeting.
+func speakerLabels(cfg *Settings) (me, others string) {
+	me, others = strings.TrimSpace(cfg.SpeakerMe), strings.TrimSpace(cfg.SpeakerOthers)
+	if me == "" {
+		me = defaultSpeakerMe
+	}
+	if others == "" {
+		others = defaultSpeakerOthers
+	}
+	return me, others
+}
+
+// isEcho reports whether a microphone turn is the other side of the call
+// picked up from the speakers: said at the same time as one of their turns,
+// with mostly the same words.
+func isEcho(mine, theirs Turn) bool {
+	if mine.Start.After(theirs.End.Add(echoWindow)) || mine.End.Before(theirs.Start.Add(-echoWindow)) {
+		return false
+	}
+	words := strings.Fields(normalizeCommand(mine.Text))
+	if len(words) == 0 {
+		return false
+	}
+	heard := make(map[string]int)
+	for _, word := range strings.Fields(normalizeCommand(theirs.Text)) {
+		heard[word]++
+	}
+	matched := 0
+	for _, word := range words {
+		if heard[word] > 0 {
+			heard[word]--
+			matched++
+		}
+	}
+	if len(words) == 1 {
+		return matched == 1 && len(heard) == 0
+	}
+	return float64(matched)/float64(len(words)) >= echoSimilarity
+}
+
+// dropEcho keeps turns attributed to the right side when the call plays
+// through speakers. A microphone turn that echoes one from the call is
+// blanked, and so are earlier microphone turns a new call turn turns out to
+// echo. It reports whether turn itself was blanked. Caller must hold a.mu.
+func (a *App) dropEcho(turn Turn) bool {
+	if len(a.streams) != 2 || turn.Text == "" {
+		return false
+	}
+	for i := len(a.turns) - 1; i >= 0; i-- {
+		other := &a.turns[i]
+		if other.Session != turn.Session || other.Text == "" || other.Stream == turn.Stream {
+			continue
+		}
+		if other.End.Before(turn.Start.Add(-time.Minute)) {
+			break
+		}
+		switch {
+		case turn.Stream == streamMe && isEcho(turn, *other):
+			slog.Info("dropping microphone echo", "turn", turn.Order, "echoes", other.Order)
+			a.blankTurn(turn)
+			return true
+		case turn.Stream == streamOthers && isEcho(*other, turn):
+			slog.Info("dropping microphone echo", "turn", other.Order, "echoes", turn.Order)
+			other.Text = ""
+		}
+	}
+	return false
+}
+
+// blankTurn empties a recorded turn's text. Caller must hold a.mu.
+func (a *App) blankTurn(turn Turn) {
+	for i := range a.turns {
+		t := &a.turns[i]
+		if t.Session == turn.Session && t.Stream == turn.Stream && t.Order == turn.Order {
+			t.Text = ""
+			return
+		}
+	}
+}
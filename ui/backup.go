@@ -0,0 +1,423 @@
+package ui
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	backupVersion  = 1
+	backupManifest = "manifest.json"
+)
+
+// BackupManifest describes a history archive: a zip of history/<id>.json
+// files, and optionally the recordings/*.wav files their turns point to.
+type BackupManifest struct {
+	Version    int       `json:"version"`
+	Created    time.Time `json:"created"`
+	Sessions   int       `json:"sessions"`
+	Recordings int       `json:"recordings"`
+}
+
+// ImportResult counts what importing an archive did.
+type ImportResult struct {
+	Added      int // New sessions, including renamed ones
+	Renamed    int // New sessions whose ID was taken by a different session or invalid
+	Merged     int // Sessions already here that gained revisions
+	Duplicates int // Sessions already here with nothing new
+	Recordings int
+}
+
+func (r ImportResult) String() string {
+	return tr("%d sessions added (%d renamed), %d merged, %d duplicates skipped, %d recordings", r.Added, r.Renamed, r.Merged, r.Duplicates, r.Recordings)
+}
+
+func (a *App) getRecordingsDir() string {
+	return filepath.Join(a.getConfigDir(), "recordings")
+}
+
+// writeBackup writes every history session to a zip archive, with the
+// session recordings if audio is set.
+func (a *App) writeBackup(w io.Writer, audio bool) (BackupManifest, error) {
+	m := BackupManifest{Version: backupVersion, Created: time.Now()}
+	zw := zip.NewWriter(w)
+	add := func(name, src string) error {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dst, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, f)
+		return err
+	}
+
+	sessions, err := filepath.Glob(filepath.Join(a.getHistoryDir(), "*.json"))
+	if err != nil {
+		return m, err
+	}
+	for _, src := range sessions {
+		if err := add("history/"+filepath.Base(src), src); err != nil {
+			return m, fmt.Errorf("failed to back up %s: %v", filepath.Base(src), err)
+		}
+		m.Sessions++
+	}
+	if audio {
+		recordings, err := filepath.Glob(filepath.Join(a.getRecordingsDir(), "*.wav"))
+		if err != nil {
+			return m, err
+		}
+		for _, src := range recordings {
+			if err := add("recordings/"+filepath.Base(src), src); err != nil {
+				return m, fmt.Errorf("failed to back up %s: %v", filepath.Base(src), err)
+			}
+			m.Recordings++
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	dst, err := zw.Create(backupManifest)
+	if err != nil {
+		return m, err
+	}
+	if _, err := dst.Write(data); err != nil {
+		return m, err
+	}
+	return m, zw.Close()
+}
+
+// sameSession reports whether two saved sessions are the same recording,
+// which they are when they started together with the same transcript.
+func sameSession(a, b *HistorySession) bool {
+	return a.Started.Equal(b.Started) && a.Revisions[0].Text == b.Revisions[0].Text
+}
+
+// mergeSession adds the revisions of imported that existing lacks, keeping
+// them in the order they were made, and reports whether there were any.
+func mergeSession(existing, imported *HistorySession) bool {
+	have := make(map[string]bool)
+	key := func(r Revision) string { return r.Created.UTC().String() + "\x00" + r.Source + "\x00" + r.Text }
+	for _, r := range existing.Revisions {
+		have[key(r)] = true
+	}
+	added := false
+	for _, r := range imported.Revisions {
+		if !have[key(r)] {
+			existing.Revisions = append(existing.Revisions, r)
+			have[key(r)] = true
+			added = true
+		}
+	}
+	if added {
+		// The transcript stays first
+		rest := existing.Revisions[1:]
+		sort.SliceStable(rest, func(i, j int) bool { return rest[i].Created.Before(rest[j].Created) })
+	}
+	return added
+}
+
+// readZipFile reads a whole file from an archive.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// fileCRC32 returns the CRC-32 of a file's contents as zip archives record
+// it, or 0 if it can't be read.
+func fileCRC32(name string) uint32 {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0
+	}
+	return h.Sum32()
+}
+
+// extractRecording copies a recording out of an archive, unless the same
+// file is already here, going by its size and CRC-32. A different file with the same name is kept under
+// a new name, which is returned.
+func (a *App) extractRecording(f *zip.File) (string, bool, error) {
+	dir := a.getRecordingsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, err
+	}
+	name := path.Base(f.Name)
+	ext := filepath.Ext(name)
+	for i := 2; ; i++ {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err == nil && info.Size() == int64(f.UncompressedSize64) && fileCRC32(filepath.Join(dir, name)) == f.CRC32 {
+			return name, false, nil
+		}
+		name = strings.TrimSuffix(path.Base(f.Name), ext) + "-" + strconv.Itoa(i) + ext
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return "", false, err
+	}
+	defer rc.Close()
+	dst := filepath.Join(dir, name)
+	out, err := os.Create(dst + ".tmp")
+	if err != nil {
+		return "", false, err
+	}
+	_, err = io.Copy(out, rc)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst + ".tmp")
+		return "", false, err
+	}
+	return name, true, os.Rename(dst+".tmp", dst)
+}
+
+// importBackup merges an archive into the history. Sessions already here
+// gain any revisions they're missing, sessions whose ID is taken by a
+// different session are saved under a new ID, and recordings are copied
+// with the turns pointed at them.
+func (a *App) importBackup(zr *zip.Reader) (ImportResult, error) {
+	var result ImportResult
+	var sessionFiles []*zip.File
+	recordings := make(map[string]string) // Archived name to local name
+	for _, f := range zr.File {
+		// Only an entry's base name is used, so entries can't write outside
+		// the folders. Session IDs are checked when the sessions are read.
+		switch dir, name := path.Dir(f.Name), path.Base(f.Name); {
+		case f.Name == backupManifest:
+			data, err := readZipFile(f)
+			if err != nil {
+				return result, fmt.Errorf("failed to read archive: %v", err)
+			}
+			var m BackupManifest
+			if err := json.Unmarshal(data, &m); err != nil {
+				return result, fmt.Errorf("invalid archive manifest: %v", err)
+			}
+			if m.Version > backupVersion {
+				return result, fmt.Errorf("the archive is from a newer version of Voice Typing")
+			}
+		case dir == "history" && strings.HasSuffix(name, ".json"):
+			sessionFiles = append(sessionFiles, f)
+		case dir == "recordings" && strings.HasSuffix(name, ".wav"):
+			local, copied, err := a.extractRecording(f)
+			if err != nil {
+				return result, fmt.Errorf("failed to import recording %s: %v", name, err)
+			}
+			recordings[name] = local
+			if copied {
+				result.Recordings++
+			}
+		}
+	}
+
+	existing, err := a.loadHistory()
+	if err != nil {
+		return result, err
+	}
+	ids := make(map[string]bool)
+	for _, h := range existing {
+		ids[h.ID] = true
+	}
+	for _, f := range sessionFiles {
+		data, err := readZipFile(f)
+		if err != nil {
+			return result, fmt.Errorf("failed to read archive: %v", err)
+		}
+		var h HistorySession
+		if err := json.Unmarshal(data, &h); err != nil || len(h.Revisions) == 0 {
+			slog.Warn("skipping invalid session in archive", "name", f.Name, "err", err)
+			continue
+		}
+		// The ID names the session's file, so one that isn't a plain file
+		// name is replaced rather than trusted
+		renamed := !validHistoryID(h.ID)
+		if renamed {
+			slog.Warn("renaming session with an invalid ID in archive", "name", f.Name, "id", h.ID)
+			h.ID = h.Started.Format(historyIDLayout)
+		}
+		for i, turn := range h.Turns {
+			if turn.Audio == "" {
+				continue
+			}
+			// Recordings made on Windows have backslashes wherever this runs.
+			// Turns only keep recordings that came in the archive.
+			if local, ok := recordings[path.Base(filepath.ToSlash(strings.ReplaceAll(turn.Audio, `\`, "/")))]; ok {
+				h.Turns[i].Audio = filepath.Join(a.getRecordingsDir(), local)
+			} else {
+				h.Turns[i].Audio, h.Turns[i].AudioStart, h.Turns[i].AudioEnd = "", 0, 0
+			}
+		}
+
+		var match *HistorySession
+		for _, e := range existing {
+			if sameSession(e, &h) {
+				match = e
+				break
+			}
+		}
+		switch {
+		case match != nil && mergeSession(match, &h):
+			if err := a.saveHistorySession(match); err != nil {
+				return result, err
+			}
+			result.Merged++
+		case match != nil:
+			result.Duplicates++
+		default:
+			if ids[h.ID] {
+				base := h.ID
+				for i := 2; ids[h.ID]; i++ {
+					h.ID = base + "-" + strconv.Itoa(i)
+				}
+				renamed = true
+			}
+			if renamed {
+				result.Renamed++
+			}
+			if err := a.saveHistorySession(&h); err != nil {
+				return result, err
+			}
+			ids[h.ID] = true
+			existing = append(existing, &h)
+			result.Added++
+		}
+	}
+	return result, nil
+}
+
+// openArchive opens a chosen archive, straight from disk when it's a local
+// file so large archives aren't read into memory.
+func openArchive(reader fyne.URIReadCloser) (*zip.Reader, func(), error) {
+	if reader.URI().Scheme() == "file" {
+		zr, err := zip.OpenReader(reader.URI().Path())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open archive: %v", err)
+		}
+		return &zr.Reader, func() { zr.Close() }, nil
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open archive: %v", err)
+	}
+	return zr, func() {}, nil
+}
+
+// showBackup backs the whole history up to one archive, or imports one,
+// calling onImport after an import.
+func (a *App) showBackup(onImport func()) {
+	audioCheck := widget.NewCheck(tr("Include session audio recordings"), nil)
+	var d dialog.Dialog
+
+	exportBtn := widget.NewButton(tr("Back Up History..."), func() {
+		audio := audioCheck.Checked
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			d.Hide()
+			a.updateStatus(tr("Backing up history..."))
+			go func() {
+				defer writer.Close()
+				m, err := a.writeBackup(writer, audio)
+				fyne.Do(func() {
+					if err != nil {
+						slog.Error("history backup failed", "err", err)
+						a.showError(fmt.Errorf("failed to back up history: %v", err))
+						return
+					}
+					slog.Info("history backed up", "uri", writer.URI(), "sessions", m.Sessions, "recordings", m.Recordings)
+					a.updateStatus(tr("Backed up %d sessions and %d recordings to %s", m.Sessions, m.Recordings, writer.URI().Name()))
+				})
+			}()
+		}, a.window)
+		save.SetFileName("voice-typing-history-" + time.Now().Format("2006-01-02") + ".zip")
+		save.Show()
+	})
+
+	importBtn := widget.NewButton(tr("Import Archive..."), func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			d.Hide()
+			a.updateStatus(tr("Importing history..."))
+			go func() {
+				defer reader.Close()
+				zr, done, err := openArchive(reader)
+				var result ImportResult
+				if err == nil {
+					result, err = a.importBackup(zr)
+					done()
+				}
+				fyne.Do(func() {
+					if err != nil {
+						slog.Error("history import failed", "err", err)
+						a.showError(err)
+						return
+					}
+					slog.Info("history imported", "uri", reader.URI(), "added", result.Added, "renamed", result.Renamed,
+						"merged", result.Merged, "duplicates", result.Duplicates, "recordings", result.Recordings)
+					a.updateStatus(tr("Imported: %s", result.String()))
+					if onImport != nil {
+						onImport()
+					}
+				})
+			}()
+		}, a.window)
+		open.Show()
+	})
+
+	note := widget.NewLabel(tr("A backup is a single zip file of every session and its revisions. Importing merges it into this history: sessions already here gain any new revisions and duplicates are skipped."))
+	note.Wrapping = fyne.TextWrapWord
+	note.Importance = widget.LowImportance
+	content := container.NewVBox(audioCheck, container.NewHBox(exportBtn, importBtn), note)
+	d = dialog.NewCustom(tr("Back Up and Restore"), tr("Close"), content, a.window)
+	d.Resize(fyne.NewSize(460, 0))
+	d.Show()
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImportBackupRejectsUnsafeIDs(t *testing.T) {
+	a := newTestApp(t, newMockAssembly(t))
+	started := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	session := HistorySession{
+		ID:        "../../../.assemblyai-transcriber",
+		Started:   started,
+		Turns:     []HistoryTurn{{Text: "Hello.", Audio: "/etc/passwd", AudioEnd: time.Second}},
+		Revisions: []Revision{{Created: started, Source: revisionTranscript, Text: "Hello."}},
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("history/session.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(data)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The ID points at the settings file, which holds the API keys
+	target := filepath.Join(a.getHistoryDir(), session.ID+".json")
+	before, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := a.importBackup(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 1 || result.Renamed != 1 {
+		t.Errorf("result = %+v, want 1 session added under a new ID", result)
+	}
+	if after, _ := os.ReadFile(target); !bytes.Equal(after, before) {
+		t.Errorf("session written outside the history folder to %s", target)
+	}
+	sessions, err := a.loadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("history has %d sessions, want 1", len(sessions))
+	}
+	if got, want := sessions[0].ID, started.Format(historyIDLayout); got != want {
+		t.Errorf("ID = %q, want %q", got, want)
+	}
+	// The recording wasn't in the archive, so the turn can't point anywhere
+	if turn := sessions[0].Turns[0]; turn.Audio != "" || turn.AudioEnd != 0 {
+		t.Errorf("turn audio = %q %v, want none", turn.Audio, turn.AudioEnd)
+	}
+}
+
+func TestExtractRecordingComparesContents(t *testing.T) {
+	a := newTestApp(t, newMockAssembly(t))
+	dir := a.getRecordingsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "take.wav"), []byte("first take"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string]string{"same/take.wav": "first take", "other/take.wav": "other take"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range zr.File {
+		name, added, err := a.extractRecording(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Both are the same size; only the contents tell them apart
+		want, wantAdded := "take.wav", false
+		if f.Name == "other/take.wav" {
+			want, wantAdded = "take-2.wav", true
+		}
+		if name != want || added != wantAdded {
+			t.Errorf("%s extracted as %q, added %v, want %q, %v", f.Name, name, added, want, wantAdded)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "take-2.wav")); string(data) != "other take" {
+		t.Errorf("take-2.wav = %q, want the archived recording", data)
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Consecutive failures before an integration is paused.
+const breakerThreshold = 3
+
+// Breaker pauses an integration (an output sink or the LLM endpoint) after
+// repeated failures, until the user resumes it.
+type Breaker struct {
+	name     string
+	failures int
+	open     bool
+	lastErr  error
+}
+
+// Breakers tracks a breaker per integration.
+type Breakers struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+	onChange func()
+}
+
+// allow returns an error if the named integration is paused.
+func (bs *Breakers) allow(name string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if b := bs.breakers[name]; b != nil && b.open {
+		return fmt.Errorf("%s is paused after %d failures", name, b.failures)
+	}
+	return nil
+}
+
+// record notes the result of a call, pausing the integration once it has
+// failed breakerThreshold times in a row.
+func (bs *Breakers) record(name string, err error) {
+	bs.mu.Lock()
+	if bs.breakers == nil {
+		bs.breakers = make(map[string]*Breaker)
+	}
+	b := bs.breakers[name]
+	if b == nil {
+		b = &Breaker{name: name}
+		bs.breakers[name] = b
+	}
+	if err == nil {
+		b.failures = 0
+		bs.mu.Unlock()
+		return
+	}
+	b.failures++
+	b.lastErr = err
+	failures := b.failures
+	tripped := !b.open && failures >= breakerThreshold
+	if tripped {
+		b.open = true
+	}
+	onChange := bs.onChange
+	bs.mu.Unlock()
+
+	if tripped {
+		slog.Error("integration paused after repeated failures", "integration", name, "failures", failures, "err", err)
+		if onChange != nil {
+			onChange()
+		}
+	}
+}
+
+// resume closes the named breaker so calls are attempted again.
+func (bs *Breakers) resume(name string) {
+	bs.mu.Lock()
+	if b := bs.breakers[name]; b != nil {
+		b.open = false
+		b.failures = 0
+	}
+	onChange := bs.onChange
+	bs.mu.Unlock()
+
+	slog.Info("integration resumed", "integration", name)
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// paused returns copies of the open breakers, sorted by name.
+func (bs *Breakers) paused() []Breaker {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	var open []Breaker
+	for _, b := range bs.breakers {
+		if b.open {
+			open = append(open, *b)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].name < open[j].name })
+	return open
+}
+
+// newBreakerBanner builds the banner listing paused integrations, each with
+// a Resume button. It's hidden while everything is running.
+func (a *App) newBreakerBanner() fyne.CanvasObject {
+	banner := container.NewVBox()
+	banner.Hide()
+
+	refresh := func() {
+		banner.RemoveAll()
+		for _, b := range a.breakers.paused() {
+			name := b.name
+			label := widget.NewLabel(tr("Paused %s: %v", name, b.lastErr))
+			label.Importance = widget.DangerImportance
+			label.Truncation = fyne.TextTruncateEllipsis
+			resumeBtn := widget.NewButtonWithIcon(tr("Resume"), theme.MediaReplayIcon(), func() {
+				a.breakers.resume(name)
+			})
+			banner.Add(container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), resumeBtn, label))
+		}
+		if len(banner.Objects) == 0 {
+			banner.Hide()
+		} else {
+			banner.Show()
+		}
+	}
+
+	a.breakers.mu.Lock()
+	a.breakers.onChange = func() { fyne.Do(refresh) }
+	a.breakers.mu.Unlock()
+	return banner
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bulkWorkers is how many sessions are reprocessed at once. Retries handle
+// any rate limiting beyond that.
+const bulkWorkers = 3
+
+var systemPromptChoice = trMark("System prompt from Settings")
+
+// reprocessChoices lists what sessions can be reprocessed with: the system
+// prompt, each team preset and each pipeline.
+func (a *App) reprocessChoices() ([]string, map[string]Pipeline) {
+	cfg := a.settings()
+	choices := map[string]Pipeline{}
+	var names []string
+	if cfg.SystemPrompt != "" {
+		names = append(names, tr(systemPromptChoice))
+		choices[tr(systemPromptChoice)] = Pipeline{Name: "System prompt", Steps: []PipelineStep{{Name: "System prompt", Prompt: cfg.SystemPrompt}}}
+	}
+	for _, name := range cfg.promptPresetNames() {
+		label := "Preset: " + name
+		names = append(names, label)
+		choices[label] = Pipeline{Name: name, Steps: []PipelineStep{{Name: name, Prompt: cfg.PromptPresets[name]}}}
+	}
+	pipelines, err := a.loadPipelines()
+	if err != nil {
+		slog.Warn("pipelines unavailable for reprocessing", "err", err)
+	}
+	for _, p := range pipelines {
+		label := "Pipeline: " + p.Name
+		names = append(names, label)
+		choices[label] = p
+	}
+	return names, choices
+}
+
+// showBulkReprocess asks what to run over the sessions, then queues them.
+// refresh is called on the UI thread as each session gets its new revision.
+func (a *App) showBulkReprocess(sessions []*HistorySession, refresh func()) {
+	if a.settings().GroqAPIKey == "" {
+		a.updateStatus(tr("Reprocessing needs a Groq API key in Settings"))
+		return
+	}
+	names, choices := a.reprocessChoices()
+	if len(names) == 0 {
+		dialog.ShowInformation(tr("Reprocess"), tr("Set a system prompt in Settings or add a pipeline first."), a.window)
+		return
+	}
+	choiceSelect := widget.NewSelect(names, nil)
+	choiceSelect.SetSelected(names[0])
+	skipCheck := widget.NewCheck(tr("Skip sessions that already have a revision from it"), nil)
+	skipCheck.SetChecked(true)
+
+	dialog.ShowForm(tr("Reprocess %d Sessions", len(sessions)), tr("Start"), tr("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(tr("Run"), choiceSelect),
+		widget.NewFormItem("", skipCheck),
+		widget.NewFormItem("", widget.NewLabel(tr("Each session's original transcript is processed\nand the result is added as a new revision."))),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		p := choices[choiceSelect.Selected]
+		var queue []*HistorySession
+		for _, h := range sessions {
+			if !skipCheck.Checked || !h.hasRevisionFrom(p.Name) {
+				queue = append(queue, h)
+			}
+		}
+		if len(queue) == 0 {
+			a.updateStatus(tr("Every session already has a revision from %s", p.Name))
+			return
+		}
+		a.reprocessSessions(queue, p, refresh)
+	}, a.window)
+}
+
+func (h *HistorySession) hasRevisionFrom(source string) bool {
+	for _, rev := range h.Revisions {
+		if rev.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// reprocessSessions runs the pipeline over each session's original
+// transcript with a small pool of workers, showing progress. Cancel stops
+// the sessions not yet finished.
+func (a *App) reprocessSessions(queue []*HistorySession, p Pipeline, refresh func()) {
+	slog.Info("bulk reprocessing", "sessions", len(queue), "pipeline", p.Name)
+	ctx, done := a.startBatchLLMTask()
+
+	progress := widget.NewProgressBar()
+	progress.Max = float64(len(queue))
+	statusLbl := widget.NewLabel(tr("Reprocessing %d sessions with %s...", len(queue), p.Name))
+	cancelBtn := widget.NewButton(tr("Cancel"), a.cancelLLMTasks)
+	d := dialog.NewCustomWithoutButtons(tr("Reprocessing"), container.NewVBox(statusLbl, progress, cancelBtn), a.window)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+
+	jobs := make(chan *HistorySession)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	finished, failed := 0, 0
+	report := func(err error) {
+		mu.Lock()
+		finished++
+		if a.llmError(err) != nil {
+			failed++
+		}
+		n, f := finished, failed
+		mu.Unlock()
+		fyne.Do(func() {
+			progress.SetValue(float64(n))
+			statusLbl.SetText(tr("%d of %d sessions done, %d failed", n, len(queue), f))
+		})
+	}
+
+	for i := 0; i < bulkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for h := range jobs {
+				report(a.reprocessSession(ctx, h, p, refresh))
+			}
+		}()
+	}
+	go func() {
+		for _, h := range queue {
+			if ctx.Err() != nil {
+				break
+			}
+			jobs <- h
+		}
+		close(jobs)
+		wg.Wait()
+		fyne.Do(func() {
+			done()
+			d.Hide()
+			mu.Lock()
+			n, f := finished, failed
+			mu.Unlock()
+			status := fmt.Sprintf("Reprocessed %d of %d sessions with %s", n-f, len(queue), p.Name)
+			if f > 0 {
+				status += fmt.Sprintf(" (%d failed, see Logs)", f)
+			}
+			if ctx.Err() != nil {
+				status += ", then cancelled"
+			}
+			a.reportResult(status)
+		})
+	}()
+}
+
+// reprocessSession adds a revision to one session and saves it.
+func (a *App) reprocessSession(parent context.Context, h *HistorySession, p Pipeline, refresh func()) error {
+	ctx, cancel := a.llmContext(parent)
+	defer cancel()
+	vars := PromptContext{Now: time.Now(), Language: defaultLanguage, Title: h.Title, Attendees: h.Attendees}
+	results, err := a.runPipeline(ctx, p, h.Revisions[0].Text, vars, func(int) {})
+	if err == nil && len(results) < len(p.Steps) {
+		err = ctx.Err()
+	}
+	if err != nil {
+		slog.Warn("reprocessing session failed", "id", h.ID, "err", err)
+		return err
+	}
+
+	updated := *h
+	updated.Revisions = append(append([]Revision{}, h.Revisions...), Revision{
+		Created: time.Now(),
+		Source:  p.Name,
+		Text:    results[len(results)-1].text(),
+	})
+	if err := a.saveHistorySession(&updated); err != nil {
+		slog.Error("failed to save reprocessed session", "id", h.ID, "err", err)
+		return err
+	}
+	fyne.Do(func() {
+		*h = updated
+		refresh()
+	})
+	return nil
+}
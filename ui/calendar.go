@@ -0,0 +1,312 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+type CalendarEvent struct {
+	UID       string
+	Summary   string
+	Start     time.Time
+	Attendees []string
+
+	rule         *recurrence
+	extra        []time.Time // RDATE
+	except       []time.Time // EXDATE, and instances moved by another event
+	recurrenceID time.Time   // The instance of a recurring event this one replaces
+}
+
+const (
+	calendarPollInterval    = 30 * time.Second
+	calendarRefreshInterval = 15 * time.Minute
+)
+
+func fetchCalendar(client *http.Client, source string) ([]CalendarEvent, error) {
+	var reader io.Reader
+	if strings.HasPrefix(source, "webcal://") {
+		source = "https://" + strings.TrimPrefix(source, "webcal://")
+	}
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch calendar: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch calendar: status %d", resp.StatusCode)
+		}
+		reader = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open calendar: %v", err)
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	return parseICS(reader)
+}
+
+// parseICS extracts timed VEVENTs from an iCalendar stream, with their
+// recurrences. All-day events are ignored.
+func parseICS(r io.Reader) ([]CalendarEvent, error) {
+	// Unfold continuation lines first (RFC 5545 section 3.1)
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %v", err)
+	}
+
+	var events []CalendarEvent
+	var current *CalendarEvent
+	var rrule string // Parsed once DTSTART, which may come after it, is known
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, params, _ := strings.Cut(name, ";")
+
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = &CalendarEvent{}
+			rrule = ""
+		case line == "END:VEVENT":
+			if current != nil && !current.Start.IsZero() {
+				if rrule != "" {
+					current.rule = parseRRule(rrule, current.Start)
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+		case prop == "UID":
+			current.UID = value
+		case prop == "SUMMARY":
+			current.Summary = unescapeICS(value)
+		case prop == "DTSTART":
+			current.Start = parseICSTime(value, params)
+		case prop == "RRULE":
+			rrule = value
+		case prop == "RDATE":
+			current.extra = append(current.extra, parseICSTimes(value, params)...)
+		case prop == "EXDATE":
+			current.except = append(current.except, parseICSTimes(value, params)...)
+		case prop == "RECURRENCE-ID":
+			current.recurrenceID = parseICSTime(value, params)
+		case prop == "ATTENDEE":
+			current.Attendees = append(current.Attendees, attendeeName(value, params))
+		}
+	}
+
+	// An event with a RECURRENCE-ID stands in for that instance of the
+	// recurring event with its UID
+	for _, moved := range events {
+		if moved.recurrenceID.IsZero() {
+			continue
+		}
+		for i := range events {
+			if events[i].UID == moved.UID && events[i].rule != nil {
+				events[i].except = append(events[i].except, moved.recurrenceID)
+			}
+		}
+	}
+	return events, nil
+}
+
+// occurrences returns when the event starts between from and to, inclusive,
+// in order.
+func (e CalendarEvent) occurrences(from, to time.Time) []time.Time {
+	var starts []time.Time
+	add := func(t time.Time) {
+		if t.Before(from) || t.After(to) || slices.ContainsFunc(e.except, t.Equal) {
+			return
+		}
+		starts = append(starts, t)
+	}
+	if e.rule != nil {
+		e.rule.each(e.Start, from, to, add)
+	} else {
+		add(e.Start)
+	}
+	for _, t := range e.extra {
+		add(t)
+	}
+	slices.SortFunc(starts, time.Time.Compare)
+	return slices.CompactFunc(starts, time.Time.Equal)
+}
+
+func parseICSTime(value, params string) time.Time {
+	if strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME") {
+		return time.Time{}
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	}
+
+	loc := time.Local
+	for _, param := range strings.Split(params, ";") {
+		if tzid, ok := strings.CutPrefix(param, "TZID="); ok {
+			if l, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+				loc = l
+			}
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseICSTimes parses a comma-separated list of times, skipping dates and
+// periods.
+func parseICSTimes(value, params string) []time.Time {
+	var times []time.Time
+	for _, v := range strings.Split(value, ",") {
+		if t := parseICSTime(v, params); !t.IsZero() {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+func attendeeName(value, params string) string {
+	for _, param := range strings.Split(params, ";") {
+		if cn, ok := strings.CutPrefix(param, "CN="); ok {
+			return strings.Trim(cn, `"`)
+		}
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(value, "mailto:"), "MAILTO:")
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// startCalendarWatcher polls the configured calendar and offers to start a
+// transcription when a meeting begins. The calendar is fetched straight away,
+// again whenever its URL changes, and every calendarRefreshInterval after a
+// successful fetch.
+func (a *App) startCalendarWatcher() {
+	go func() {
+		var events []CalendarEvent
+		var source string
+		var lastFetch time.Time
+		prompted := make(map[string]bool)
+		lastCheck := time.Now()
+
+		check := func(now time.Time) {
+			if url := a.settings().CalendarURL; url != source {
+				// Events from the previous calendar no longer apply
+				events, source, lastFetch = nil, url, time.Time{}
+			}
+			if source == "" {
+				lastCheck = now
+				return
+			}
+
+			if now.Sub(lastFetch) > calendarRefreshInterval {
+				client, err := a.httpClient(30 * time.Second)
+				var fetched []CalendarEvent
+				if err == nil {
+					fetched, err = fetchCalendar(client, source)
+				}
+				if err != nil {
+					slog.Warn("calendar refresh failed", "err", err)
+				} else {
+					slog.Info("calendar refreshed", "events", len(fetched))
+					events = fetched
+					lastFetch = now
+				}
+			}
+
+			for _, event := range events {
+				for _, start := range event.occurrences(lastCheck, now) {
+					key := event.UID + start.String()
+					if prompted[key] || !start.After(lastCheck) {
+						continue
+					}
+					prompted[key] = true
+					occurrence := event
+					occurrence.Start = start
+					fyne.Do(func() {
+						a.promptMeetingStart(occurrence)
+					})
+				}
+			}
+			lastCheck = now
+		}
+
+		check(time.Now())
+		ticker := time.NewTicker(calendarPollInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			check(now)
+		}
+	}()
+}
+
+func (a *App) promptMeetingStart(event CalendarEvent) {
+	title := event.Summary
+	if title == "" {
+		title = tr("Untitled event")
+	}
+
+	// Tagged with the meeting even if the prompt is declined
+	a.setSession(title, event.Attendees)
+	message := tr("Meeting '%s' started — begin transcription?", title)
+	dialog.ShowConfirm(tr("Meeting Started"), message, func(ok bool) {
+		if !ok {
+			return
+		}
+		if !a.recording.Load() {
+			a.startRecording()
+		}
+	}, a.window)
+	a.window.RequestFocus()
+}
+
+// setSession tags the current transcript with a meeting title and attendees.
+func (a *App) setSession(title string, attendees []string) {
+	a.mu.Lock()
+	a.sessionTitle = title
+	a.sessionAttendees = attendees
+	a.mu.Unlock()
+
+	header := tr("Voice Typing")
+	if title != "" {
+		header += " — " + title
+		if len(attendees) > 0 {
+			header += " (" + strings.Join(attendees, ", ") + ")"
+		}
+	}
+	a.headerLbl.SetText(header)
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const recurringCalendar = `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Standup
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20260331T235959Z
+DTSTART:20260302T093000Z
+EXDATE:20260311T093000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID:20260316T093000Z
+SUMMARY:Standup (moved)
+DTSTART:20260316T140000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:review
+SUMMARY:Monthly review
+DTSTART:20260130T160000Z
+RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:once
+SUMMARY:One-off
+DTSTART:20260304T120000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParseICSRecurrence(t *testing.T) {
+	events, err := parseICS(strings.NewReader(recurringCalendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	starts := func(from, to string) []string {
+		f, _ := time.Parse(time.DateTime, from)
+		u, _ := time.Parse(time.DateTime, to)
+		var got []string
+		for _, e := range events {
+			for _, start := range e.occurrences(f, u) {
+				got = append(got, e.Summary+" "+start.Format("Jan 2 15:04"))
+			}
+		}
+		return got
+	}
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		// The first week, from DTSTART
+		{"2026-03-01 00:00:00", "2026-03-07 23:59:59", "Standup Mar 2 09:30|Standup Mar 4 09:30|One-off Mar 4 12:00"},
+		// A later day, an excluded one and a moved one
+		{"2026-03-09 00:00:00", "2026-03-09 23:59:59", "Standup Mar 9 09:30"},
+		{"2026-03-11 00:00:00", "2026-03-11 23:59:59", ""},
+		{"2026-03-16 00:00:00", "2026-03-16 23:59:59", "Standup (moved) Mar 16 14:00"},
+		// Past UNTIL
+		{"2026-04-01 00:00:00", "2026-04-30 23:59:59", ""},
+		// Last Friday of the month, three times
+		{"2026-02-01 00:00:00", "2026-02-28 23:59:59", "Monthly review Feb 27 16:00"},
+		{"2026-03-25 00:00:00", "2026-03-31 23:59:59", "Standup Mar 25 09:30|Standup Mar 30 09:30|Monthly review Mar 27 16:00"},
+		{"2026-04-01 00:00:00", "2026-05-31 23:59:59", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(starts(tt.from, tt.to), "|"); got != tt.want {
+			t.Errorf("%s to %s: %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRecurrenceFarFromStart(t *testing.T) {
+	start := time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC) // A Monday
+	e := CalendarEvent{Start: start, rule: parseRRule("FREQ=DAILY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR", start)}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	var got []string
+	for _, t := range e.occurrences(day, day.AddDate(0, 0, 7)) {
+		got = append(got, t.Format("Mon Jan 2"))
+	}
+	// Every other day from 6 January 2020 is odd days later by 2 March 2026,
+	// and Saturday 7 March is skipped
+	if want := "Tue Mar 3|Thu Mar 5"; strings.Join(got, "|") != want {
+		t.Errorf("occurrences = %q, want %q", got, want)
+	}
+}
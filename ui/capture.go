@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"math"
+	"sync/atomic"
+
+	"dict/audio"
+)
+
+// Capture sources
+const (
+	captureSourceMicrophone = audio.Microphone
+	captureSourceSystem     = audio.System
+	captureSourceMeeting    = "meeting"
+)
+
+var captureSourceLabels = map[string]string{
+	captureSourceMicrophone: trMark("Microphone"),
+	captureSourceSystem:     trMark("System audio (loopback)"),
+	captureSourceMeeting:    trMark("Microphone + system audio (meeting)"),
+}
+
+func captureSourceFromLabel(label string) string {
+	for source, l := range captureSourceLabels {
+		if tr(l) == label {
+			return source
+		}
+	}
+	return captureSourceMicrophone
+}
+
+// SourceLevel is a capture source's volume, which can be changed or muted
+// while recording without interrupting the stream: a muted source sends
+// silence.
+type SourceLevel struct {
+	gain  atomic.Uint64 // float64 bits, 1 for unchanged
+	muted atomic.Bool
+}
+
+func newSourceLevel() *SourceLevel {
+	l := &SourceLevel{}
+	l.gain.Store(math.Float64bits(1))
+	return l
+}
+
+func (l *SourceLevel) factor() float64 {
+	if l.muted.Load() {
+		return 0
+	}
+	return math.Float64frombits(l.gain.Load())
+}
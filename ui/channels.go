@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+// newChannelSelect picks the microphone channel to capture, for interfaces
+// that put the microphone on one channel of a stereo device. Its Detect
+// button finds how many channels the microphone named in deviceEntry has.
+// read returns the channel chosen, 0 for all of them mixed to mono.
+func newChannelSelect(channel int, deviceEntry *widget.Entry) (row fyne.CanvasObject, read func() int) {
+	channelSelect := widget.NewSelect(nil, nil)
+	setChannels := func(n int) {
+		options := []string{tr("All channels, mixed to mono")}
+		for i := 1; i <= n; i++ {
+			options = append(options, tr("Channel %d", i))
+		}
+		channelSelect.SetOptions(options)
+	}
+	setChannels(max(2, channel))
+	channelSelect.SetSelectedIndex(channel)
+
+	status := widget.NewLabel("")
+	status.Hide()
+	var detectBtn *widget.Button
+	detectBtn = widget.NewButton(tr("Detect"), func() {
+		device := strings.TrimSpace(deviceEntry.Text)
+		detectBtn.Disable()
+		status.SetText(tr("Detecting..."))
+		status.Show()
+		go func() {
+			n, err := detectChannels(device)
+			fyne.Do(func() {
+				detectBtn.Enable()
+				if err != nil {
+					slog.Warn("failed to detect microphone channels", "device", device, "err", err)
+					status.SetText(tr("Couldn't detect channels: %s", err))
+					return
+				}
+				selected := channelSelect.SelectedIndex()
+				if selected > n {
+					selected = 0
+				}
+				setChannels(n)
+				channelSelect.SetSelectedIndex(selected)
+				status.SetText(tr("Channels found: %d", n))
+			})
+		}()
+	})
+	row = container.NewBorder(nil, status, nil, detectBtn, channelSelect)
+	return row, func() int { return max(channelSelect.SelectedIndex(), 0) }
+}
+
+// detectChannels returns how many channels the microphone whose name
+// contains device has, or the default microphone if it's empty.
+func detectChannels(device string) (int, error) {
+	devices, err := audio.NewDevices()
+	if err != nil {
+		return 0, err
+	}
+	defer devices.Close()
+	devices.Microphone = device
+	return devices.CaptureChannels()
+}
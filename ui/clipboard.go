@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const clipHistorySize = 25
+
+// Clip is a version of the text that was copied, processed or cleared, kept
+// so it can be recovered after later edits. Clips are only touched on the
+// UI thread.
+type Clip struct {
+	Text   string
+	Source string
+	Time   time.Time
+}
+
+func (c Clip) label() string {
+	return c.Time.Format("15:04") + " · " + c.Source + " · " + truncateWords(c.Text, 8)
+}
+
+// rememberClip adds text to the clipboard history, newest first. Text that
+// repeats the newest clip just moves it to the top with the new source.
+func (a *App) rememberClip(source, text string) {
+	if text == "" {
+		return
+	}
+	clip := Clip{Text: text, Source: source, Time: time.Now()}
+	if len(a.clips) > 0 && a.clips[0].Text == text {
+		a.clips[0] = clip
+		return
+	}
+	a.clips = append([]Clip{clip}, a.clips[:min(len(a.clips), clipHistorySize-1)]...)
+}
+
+// copyToClipboard copies text and keeps it in the clipboard history.
+func (a *App) copyToClipboard(source, text string) {
+	a.window.Clipboard().SetContent(text)
+	a.rememberClip(source, text)
+}
+
+// showClipHistory lists recent clips under the history button, each of
+// which can be copied again, inserted or restored as the transcript.
+func (a *App) showClipHistory() {
+	var items []*fyne.MenuItem
+	for _, clip := range a.clips {
+		clip := clip
+		item := fyne.NewMenuItem(clip.label(), nil)
+		item.ChildMenu = fyne.NewMenu("",
+			fyne.NewMenuItem(tr("Copy"), func() {
+				a.copyToClipboard(clip.Source, clip.Text)
+				a.updateStatus(tr("Copied from clipboard history"))
+			}),
+			fyne.NewMenuItem(tr("Insert at Cursor"), func() { a.insertAtCaret(clip.Text) }),
+			fyne.NewMenuItem(tr("Replace Transcript"), func() { a.restoreClip(clip) }),
+		)
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		none := fyne.NewMenuItem(tr("Nothing copied or processed yet"), nil)
+		none.Disabled = true
+		items = append(items, none)
+	}
+
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(a.clipsBtn)
+	pos = pos.Add(fyne.NewPos(0, a.clipsBtn.Size().Height))
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), a.window.Canvas(), pos)
+}
+
+// restoreClip replaces the transcript with a clip, which Undo reverts.
+func (a *App) restoreClip(clip Clip) {
+	a.rememberClip("Replaced", a.textArea.Text)
+	a.previousText = a.textArea.Text
+	a.textArea.SetText(clip.Text)
+	a.undoBtn.Enable()
+	a.addRevision(revisionRestored)
+	a.updateStatus(tr("Restored text from %s", clip.Time.Format("15:04")))
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// VoiceCommand is an action spoken in command mode.
+type VoiceCommand struct {
+	phrases []string
+	run     func(a *App)
+}
+
+// voiceCommands are set in init, as the commands lead back to recording
+// and so to the message handler that runs them.
+var voiceCommands []VoiceCommand
+
+func init() {
+	voiceCommands = []VoiceCommand{
+		{[]string{"copy", "copy that", "copy all"}, (*App).copyText},
+		{[]string{"clear", "clear all", "clear text"}, (*App).clearText},
+		{[]string{"undo", "undo that"}, (*App).undoText},
+		{[]string{"process", "clean up", "clean that up"}, (*App).processWithLLM},
+		{[]string{"stop", "stop recording", "stop listening"}, (*App).stopRecording},
+		{[]string{"edit", "edit mode", "live", "live mode"}, (*App).toggleEditMode},
+		{[]string{"export"}, (*App).showExportMenu},
+		{[]string{"help", "show help"}, (*App).showHelp},
+	}
+}
+
+// normalizeCommand lowercases a transcribed command and drops punctuation,
+// since formatted turns arrive as e.g. "Copy that."
+func normalizeCommand(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// findVoiceCommand matches a command spoken in the session's language.
+func findVoiceCommand(text, language string) *VoiceCommand {
+	spoken := normalizeCommand(text)
+	for i, cmd := range voiceCommands {
+		for _, phrase := range cmd.spokenPhrases(language) {
+			if spoken == normalizeCommand(phrase) {
+				return &voiceCommands[i]
+			}
+		}
+	}
+	return nil
+}
+
+// runVoiceCommand runs a command spoken in command mode. Call it on the UI
+// thread.
+func (a *App) runVoiceCommand(text string) {
+	cmd := findVoiceCommand(text, a.sessionLanguage())
+	if cmd == nil && (a.runTextSnippet(text) || a.runNavigationCommand(text)) {
+		return
+	}
+	if cmd == nil {
+		slog.Info("unknown voice command", "text", text)
+		a.updateStatus(tr("Unknown command: %s", text))
+		return
+	}
+	slog.Info("voice command", "command", cmd.phrases[0])
+	a.updateStatus(tr("Command: %s", cmd.phrases[0]))
+	cmd.run(a)
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+)
+
+// LLMProvider is an OpenAI-compatible chat completions endpoint and its key.
+type LLMProvider struct {
+	Endpoint string
+	APIKey   string
+}
+
+func (s *Settings) llmProvider() LLMProvider {
+	return LLMProvider{Endpoint: s.GroqEndpoint, APIKey: s.GroqAPIKey}
+}
+
+// compareProvider is the endpoint for model B of a comparison, which shares
+// the main endpoint and key unless others are set.
+func (s *Settings) compareProvider() LLMProvider {
+	p := s.llmProvider()
+	if s.CompareEndpoint != "" {
+		p.Endpoint = s.CompareEndpoint
+	}
+	if s.CompareAPIKey != "" {
+		p.APIKey = s.CompareAPIKey
+	}
+	return p
+}
+
+// CompareResult is one side of a comparison.
+type CompareResult struct {
+	Label    string
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+// compareModels sends the transcript to the main model and the comparison
+// model at once, and shows both outputs side by side.
+func (a *App) compareModels() {
+	cfg := a.settings()
+	if cfg.CompareModel == "" {
+		dialog.ShowError(fmt.Errorf("Set a model to compare against in Settings"), a.window)
+		return
+	}
+	if cfg.GroqAPIKey == "" {
+		a.showError(&apierr.AuthError{Service: "Groq", Err: errors.New("Please configure Groq API key in Settings")})
+		return
+	}
+	text := a.textArea.Text
+	if text == "" {
+		a.updateStatus(tr("No text to compare"))
+		return
+	}
+
+	promptA := cfg.SystemPrompt
+	promptB := cfg.CompareSystemPrompt
+	if promptB == "" {
+		promptB = promptA
+	}
+	vars := a.promptContext(text)
+	sides := []struct {
+		provider LLMProvider
+		model    string
+		prompt   string
+	}{
+		{cfg.llmProvider(), cfg.GroqModel, expandPrompt(promptA, vars)},
+		{cfg.compareProvider(), cfg.CompareModel, expandPrompt(promptB, vars)},
+	}
+
+	slog.Info("comparing models", "a", cfg.GroqModel, "b", cfg.CompareModel)
+	a.updateStatus(tr("Comparing models..."))
+	ctx, done := a.startLLMTask()
+	go func() {
+		results := make([]CompareResult, len(sides))
+		var wg sync.WaitGroup
+		for i, side := range sides {
+			side := side
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				started := time.Now()
+				output, err := a.callProviderModel(ctx, side.provider, side.model, cfg.LLMParams, side.prompt, text)
+				results[i] = CompareResult{
+					Label:    fmt.Sprintf("%s: %s (%s)", string(rune('A'+i)), side.model, endpointHost(side.provider.Endpoint)),
+					Output:   output,
+					Err:      err,
+					Duration: time.Since(started),
+				}
+				slog.Info("comparison finished", "model", side.model, "duration", results[i].Duration, "err", err)
+			}(i)
+		}
+		wg.Wait()
+
+		fyne.Do(func() {
+			done()
+			if ctx.Err() != nil && a.llmError(ctx.Err()) == nil {
+				a.updateStatus(tr("Comparison cancelled"))
+				return
+			}
+			a.reportResult(tr("Comparison finished"))
+			a.showComparison(results)
+		})
+	}()
+}
+
+func endpointHost(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return endpoint
+}
+
+// showComparison shows each output, editable, with a button to put it in the
+// text area, where Undo can revert it.
+func (a *App) showComparison(results []CompareResult) {
+	w := fyne.CurrentApp().NewWindow(tr("Compare Models"))
+
+	var columns []fyne.CanvasObject
+	for _, result := range results {
+		result := result
+		output := newTextArea()
+		output.Wrapping = fyne.TextWrapWord
+		output.SetText(result.Output)
+
+		info := fmt.Sprintf("%.1fs · %d words", result.Duration.Seconds(), countWords(result.Output))
+		useBtn := widget.NewButton(tr("Use This One"), func() {
+			a.previousText = a.textArea.Text
+			a.textArea.SetText(output.Text)
+			a.undoBtn.Enable()
+			a.addRevision(result.Label)
+			a.updateStatus(tr("Used %s", result.Label))
+			w.Close()
+		})
+		useBtn.Importance = widget.HighImportance
+		if err := a.llmError(result.Err); err != nil {
+			info = "Failed: " + err.Error()
+			useBtn.Disable()
+		}
+		infoLbl := widget.NewLabel(info)
+		infoLbl.Wrapping = fyne.TextWrapWord
+
+		title := widget.NewLabelWithStyle(result.Label, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		columns = append(columns, container.NewBorder(title, container.NewVBox(infoLbl, useBtn), nil, nil, output))
+	}
+
+	split := container.NewHSplit(columns[0], columns[1])
+	w.SetContent(split)
+	w.Resize(fyne.NewSize(900, 600))
+	w.Show()
+}
@@ -0,0 +1,648 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+
+	"dict/config"
+	"dict/llm"
+)
+
+const (
+	defaultGroqModel    = "meta-llama/llama-4-maverick-17b-128e-instruct"
+	defaultGroqEndpoint = "https://api.groq.com/openai/v1/chat/completions"
+)
+
+// Settings is a snapshot of the user's configuration. Snapshots are never
+// modified once stored: the audio callback, WebSocket handlers and LLM calls
+// read the current one with a.settings(), and changing settings (or switching
+// profile) swaps in a new copy, which takes effect on the next request.
+type Settings struct {
+	Profile string
+
+	AssemblyAPIKey string
+	CaptureSource  string
+	InputDevice    string // Microphone by part of its name; empty for the default
+	InputChannel   int    // Microphone channel to capture, counting from 1; 0 mixes them all
+	ChunkMillis    int    // Audio per capture period, and per message where the service allows
+	MeetingMixed   bool
+	ShowLatency    bool // Badge each turn with its speech-end-to-text delay
+	RecordAudio    bool // Save each session's audio so turns can be replayed
+	AutoLanguage   bool // Multilingual model with language detection
+	TurnDetection  TurnDetection
+	TurnPlacement  string
+	TurnSeparator  string // separatorSpace, separatorNewline or separatorBlankLine
+	ParagraphPause int    // Seconds of silence that start a paragraph, 0 for never
+	VADBackend     string // Silence suppression: vadOff, vadEnergy, vadWebRTC or vadSilero
+	VADSensitivity string // vadLow, vadMedium or vadHigh
+	SmartJoin      bool   // Fix spacing and capitals where turns meet
+
+	ConsentPrompt       bool   // Consent checklist before meeting recordings
+	ConsentChecklist    string // One item per line; empty for the defaults
+	ConsentAnnounce     bool   // Speak ConsentAnnouncement before recording
+	ConsentAnnouncement string
+
+	SnippetInject string // snippetCopy or snippetType
+	SnippetFormat bool   // Wait for the formatted turn
+	snippet       bool   // Session only: recording an instant snippet
+	WatchKeywords string
+	StripPhrases  string
+	SoundsLike    string // One mapping per line, see parseSoundsLike
+	RedactPII     string // Comma separated redactKinds IDs, e.g. "email,phone"
+	RedactNames   string // One name per line, redacted along with the attendees
+	TextSnippets  string // One "trigger => text" per line, see parseTextSnippets
+
+	FilterProfanity bool // Mask profanity in dictated turns
+
+	// Session only: a recording or message log replayed in place of the
+	// microphone and AssemblyAI
+	replay *replay
+
+	// Meeting turn labels for the microphone and system audio streams,
+	// defaulting to defaultSpeakerMe and defaultSpeakerOthers
+	SpeakerMe     string
+	SpeakerOthers string
+
+	// Number formatting of final turns, see NumberFormat
+	NumberStyle     string
+	CurrencySymbols bool
+	UnitSymbols     bool
+	DateFormat      string
+	PhoneFormat     string
+
+	// Auto-stop and auto-clear, 0 for never
+	AutoStopSilence int // Seconds without a turn
+	SessionLimit    int // Minutes
+	ClearAfterCopy  bool
+
+	// Retention limits, 0 for none; see pruneStorage
+	RetainAudioDays        int
+	RetainTranscriptMonths int
+	MaxStorageMB           int
+
+	CustomVocabulary string // One term per line, added to the team's Vocabulary
+	SpellLanguage    string // Hunspell dictionary, e.g. en_GB; empty for the default
+	Locale           string // BCP 47 tag for dates and numbers in exports, e.g. de-DE
+
+	GroqAPIKey      string
+	GroqModel       string
+	GroqEndpoint    string
+	SystemPrompt    string
+	LLMParams       llm.Params
+	LLMMaxRetries   int
+	LLMTimeout      int // Seconds per LLM call, including retries
+	LectureMode     bool
+	LectureInterval int // Minutes between lecture note sections
+
+	CleanReplies  bool   // Strip lead-ins, code fences and quotes from LLM replies
+	ReplyPatterns string // More to strip, one regular expression per line
+
+	MeetingNotes         bool   // Summarize the session when recording stops
+	MeetingNotesPipeline string // Empty uses the built-in meeting notes pipeline
+
+	HandsFree   bool   // Listen locally for WakeWord while not recording
+	WakeWord    string // Model name or path for voice-typing-wake
+	SleepPhrase string // Stops recording in hands-free mode
+	PreRoll     int    // Milliseconds of audio from before the wake word to send
+
+	WeeklyDigest bool
+	DigestDay    int    // time.Weekday the digest is written on
+	DigestFolder string // Where digests are also saved as Markdown, if set
+
+	// Model B of an A/B comparison; empty fields share the main LLM's
+	CompareModel        string
+	CompareEndpoint     string
+	CompareAPIKey       string
+	CompareSystemPrompt string
+
+	TranslateProvider  string // translateLLM, translateDeepL or translateGoogle
+	DeepLAPIKey        string
+	GoogleTranslateKey string
+	TranslateSource    string // Last languages used, by name
+	TranslateTarget    string
+
+	CalendarURL string
+
+	ProxyURL   string // Empty uses HTTP(S)_PROXY from the environment
+	CACertFile string // Extra PEM certificates to trust, e.g. a corporate CA
+
+	// Team presets, fetched from ManagedConfigURL rather than saved locally
+	ManagedConfigURL string
+	PromptPresets    map[string]string
+	PresetParams     map[string]llm.Params
+	RawPresets       []string // Presets whose replies are kept as written
+	Vocabulary       []string
+
+	SinkFilePath        string
+	SinkFileTemplate    string
+	SinkPipePath        string // Named pipe or Unix socket
+	SinkPipeTemplate    string
+	SinkPipeFormat      string // pipeFormatTemplate or pipeFormatEditor
+	SinkWebhookURL      string
+	SinkWebhookTemplate string
+	TurnFilters         string // One rule per line, see parseFilterRules
+
+	UsageRates UsageRates
+
+	Shortcuts map[string]string // Key bindings by action ID, e.g. "record": "Ctrl+R"
+
+	Theme          string // themeSystem, themeDark or themeLight
+	TranscriptFont string // fontDefault, fontMonospace or a font file
+	FontSize       int    // Transcript text size
+	UILanguage     string // A uiLanguages code, or uiLanguageSystem
+	Announce       bool   // Speak status changes and the focused control
+	SoundCues      bool   // Beep on recording start, stop, reconnect and error
+	FlashCues      bool   // Flash the window icon on them too
+	Notifications  bool   // Desktop notifications of results and errors while in the background
+
+	ReadAloudRate int // Words per minute
+
+	SeenHints string // Comma separated first-use hints already shown
+	LogLevel  string
+}
+
+func defaultSettings() *Settings {
+	return &Settings{
+		GroqModel:       defaultGroqModel,
+		GroqEndpoint:    defaultGroqEndpoint,
+		LectureInterval: defaultLectureInterval,
+		DigestDay:       defaultDigestDay,
+		LLMMaxRetries:   defaultLLMRetries,
+		LLMTimeout:      defaultLLMTimeout,
+		LogLevel:        defaultLogLevel,
+		TurnDetection:   defaultTurnDetection,
+		UsageRates:      defaultUsageRates,
+		Shortcuts:       defaultShortcuts(),
+		TurnSeparator:   separatorNewline,
+		VADSensitivity:  vadMedium,
+		Theme:           themeSystem,
+		FontSize:        defaultFontSize,
+		ReadAloudRate:   defaultReadAloudRate,
+		AutoStopSilence: defaultAutoStopSilence,
+		CleanReplies:    true,
+		WakeWord:        defaultWakeWord,
+		SleepPhrase:     defaultSleepPhrase,
+		PreRoll:         defaultPreRoll,
+		SoundCues:       true,
+		FlashCues:       true,
+		Notifications:   true,
+		ChunkMillis:     defaultChunkMillis,
+	}
+}
+
+// settings returns the current configuration snapshot.
+func (a *App) settings() *Settings {
+	return a.config.Load()
+}
+
+// updateSettings stores a modified copy of the current settings.
+func (a *App) updateSettings(update func(s *Settings)) {
+	for {
+		old := a.config.Load()
+		s := *old
+		update(&s)
+		if a.config.CompareAndSwap(old, &s) {
+			return
+		}
+	}
+}
+
+// settingsFromConfig reads settings from c, keeping the default for any
+// that are out of range.
+func settingsFromConfig(c *config.Config) *Settings {
+	s := defaultSettings()
+	s.Profile = c.Profile
+
+	s.CaptureSource = c.Audio.CaptureSource
+	s.InputDevice = c.Audio.Device
+	if c.Audio.Channel >= 0 {
+		s.InputChannel = c.Audio.Channel
+	}
+	s.RecordAudio = c.Audio.Record
+	if ms := c.Audio.ChunkMS; ms >= minChunkMillis && ms <= maxChunkMillis {
+		s.ChunkMillis = ms
+	}
+	if _, ok := vadBackendLabels[c.Audio.VADBackend]; ok {
+		s.VADBackend = c.Audio.VADBackend
+	}
+	if slices.Contains(vadSensitivities, c.Audio.VADSensitivity) {
+		s.VADSensitivity = c.Audio.VADSensitivity
+	}
+	if c.Audio.AutoStopSilence >= 0 {
+		s.AutoStopSilence = c.Audio.AutoStopSilence
+	}
+	if c.Audio.SessionLimit >= 0 {
+		s.SessionLimit = c.Audio.SessionLimit
+	}
+
+	s.AssemblyAPIKey = c.STT.APIKey
+	s.AutoLanguage = c.STT.AutoLanguage
+	s.CustomVocabulary = c.STT.Vocabulary
+	s.TurnDetection = turnDetectionFromConfig(c.STT.TurnDetection)
+
+	t := c.Transcript
+	s.TurnPlacement = t.TurnPlacement
+	if t.TurnSeparator != "" {
+		s.TurnSeparator = t.TurnSeparator
+	}
+	if t.ParagraphPause >= 0 {
+		s.ParagraphPause = t.ParagraphPause
+	}
+	s.SmartJoin = t.SmartJoin
+	s.ShowLatency = t.ShowLatency
+	s.ClearAfterCopy = t.ClearAfterCopy
+	s.WatchKeywords = t.WatchKeywords
+	s.StripPhrases = t.StripPhrases
+	s.SoundsLike = t.SoundsLike
+	s.TextSnippets = t.TextSnippets
+	s.TurnFilters = t.TurnFilters
+	s.RedactPII = t.RedactPII
+	s.RedactNames = t.RedactNames
+	s.FilterProfanity = t.FilterProfanity
+	s.SpellLanguage = t.SpellLanguage
+	if _, ok := numberStyleLabels[t.Numbers.Style]; ok {
+		s.NumberStyle = t.Numbers.Style
+	}
+	s.CurrencySymbols = t.Numbers.CurrencySymbols
+	s.UnitSymbols = t.Numbers.UnitSymbols
+	if slices.Contains(dateFormats, t.Numbers.DateFormat) {
+		s.DateFormat = t.Numbers.DateFormat
+	}
+	if slices.Contains(phoneFormats, t.Numbers.PhoneFormat) {
+		s.PhoneFormat = t.Numbers.PhoneFormat
+	}
+
+	m := c.Meeting
+	s.MeetingMixed = m.Mixed
+	s.SpeakerMe = m.SpeakerMe
+	s.SpeakerOthers = m.SpeakerOthers
+	s.ConsentPrompt = m.ConsentPrompt
+	s.ConsentChecklist = m.ConsentChecklist
+	s.ConsentAnnounce = m.ConsentAnnounce
+	s.ConsentAnnouncement = m.ConsentAnnouncement
+	s.MeetingNotes = m.Notes
+	s.MeetingNotesPipeline = m.NotesPipeline
+	s.CalendarURL = m.CalendarURL
+
+	s.SnippetInject = c.Snippet.Inject
+	s.SnippetFormat = c.Snippet.Format
+
+	s.HandsFree = c.HandsFree.Enabled
+	if word := strings.TrimSpace(c.HandsFree.WakeWord); word != "" {
+		s.WakeWord = word
+	}
+	s.SleepPhrase = strings.TrimSpace(c.HandsFree.SleepPhrase)
+	if preRoll := c.HandsFree.PreRoll; preRoll >= 0 && preRoll <= maxPreRoll {
+		s.PreRoll = preRoll
+	}
+
+	s.LectureMode = c.Lecture.Enabled
+	if c.Lecture.Interval > 0 {
+		s.LectureInterval = c.Lecture.Interval
+	}
+
+	l := c.LLM
+	s.GroqAPIKey = l.APIKey
+	s.GroqModel = l.Model
+	s.GroqEndpoint = l.Endpoint
+	s.SystemPrompt = l.SystemPrompt
+	s.LLMParams = llmParamsFromConfig(l)
+	if l.MaxRetries >= 0 && l.MaxRetries <= maxLLMRetries {
+		s.LLMMaxRetries = l.MaxRetries
+	}
+	if l.Timeout > 0 && l.Timeout <= maxLLMTimeout {
+		s.LLMTimeout = l.Timeout
+	}
+	s.CleanReplies = l.CleanReplies
+	s.ReplyPatterns = l.ReplyPatterns
+	s.CompareModel = l.Compare.Model
+	s.CompareEndpoint = l.Compare.Endpoint
+	s.CompareAPIKey = l.Compare.APIKey
+	s.CompareSystemPrompt = l.Compare.SystemPrompt
+
+	s.TranslateProvider = c.Translate.Provider
+	s.DeepLAPIKey = c.Translate.DeepLAPIKey
+	s.GoogleTranslateKey = c.Translate.GoogleAPIKey
+	s.TranslateSource = c.Translate.Source
+	s.TranslateTarget = c.Translate.Target
+
+	s.SinkFilePath = c.Sinks.FilePath
+	s.SinkFileTemplate = c.Sinks.FileTemplate
+	s.SinkPipePath = c.Sinks.PipePath
+	s.SinkPipeTemplate = c.Sinks.PipeTemplate
+	s.SinkPipeFormat = c.Sinks.PipeFormat
+	s.SinkWebhookURL = c.Sinks.WebhookURL
+	s.SinkWebhookTemplate = c.Sinks.WebhookTemplate
+
+	s.WeeklyDigest = c.Digest.Enabled
+	if c.Digest.Day >= 0 && c.Digest.Day <= 6 {
+		s.DigestDay = c.Digest.Day
+	}
+	s.DigestFolder = c.Digest.Folder
+
+	if c.Storage.RetainAudioDays >= 0 {
+		s.RetainAudioDays = c.Storage.RetainAudioDays
+	}
+	if c.Storage.RetainTranscriptMonths >= 0 {
+		s.RetainTranscriptMonths = c.Storage.RetainTranscriptMonths
+	}
+	if c.Storage.MaxMB >= 0 {
+		s.MaxStorageMB = c.Storage.MaxMB
+	}
+
+	s.UsageRates = usageRatesFromConfig(c.Usage)
+	s.ProxyURL = c.Network.ProxyURL
+	s.CACertFile = c.Network.CACertFile
+	s.ManagedConfigURL = c.Team.ConfigURL
+
+	if c.UI.Theme != "" {
+		s.Theme = c.UI.Theme
+	}
+	s.TranscriptFont = c.UI.TranscriptFont
+	if size := c.UI.FontSize; size >= minFontSize && size <= maxFontSize {
+		s.FontSize = size
+	}
+	if rate := c.UI.ReadAloudRate; rate >= minReadAloudRate && rate <= maxReadAloudRate {
+		s.ReadAloudRate = rate
+	}
+	s.Locale = c.UI.Locale
+	if _, ok := uiLanguages[c.UI.Language]; ok {
+		s.UILanguage = c.UI.Language
+	}
+	s.Announce = c.UI.Announce
+	s.SoundCues = c.UI.SoundCues
+	s.FlashCues = c.UI.FlashCues
+	s.Notifications = c.UI.Notifications
+	s.SeenHints = c.UI.SeenHints
+	s.LogLevel = c.UI.LogLevel
+
+	for _, action := range shortcutActions {
+		if binding, exists := c.Shortcuts[action.ID]; exists {
+			s.Shortcuts[action.ID] = binding
+		}
+	}
+	return s
+}
+
+func (s *Settings) toConfig() *config.Config {
+	c := &config.Config{
+		Version: config.Version,
+		Profile: s.Profile,
+		Audio: config.Audio{
+			CaptureSource:   s.CaptureSource,
+			Device:          s.InputDevice,
+			Channel:         s.InputChannel,
+			Record:          s.RecordAudio,
+			VADBackend:      s.VADBackend,
+			VADSensitivity:  s.VADSensitivity,
+			AutoStopSilence: s.AutoStopSilence,
+			SessionLimit:    s.SessionLimit,
+			ChunkMS:         s.ChunkMillis,
+		},
+		STT: config.STT{
+			APIKey:        s.AssemblyAPIKey,
+			AutoLanguage:  s.AutoLanguage,
+			Vocabulary:    s.CustomVocabulary,
+			TurnDetection: s.TurnDetection.toConfig(),
+		},
+		Transcript: config.Transcript{
+			TurnPlacement:   s.TurnPlacement,
+			TurnSeparator:   s.TurnSeparator,
+			ParagraphPause:  s.ParagraphPause,
+			SmartJoin:       s.SmartJoin,
+			ShowLatency:     s.ShowLatency,
+			ClearAfterCopy:  s.ClearAfterCopy,
+			WatchKeywords:   s.WatchKeywords,
+			StripPhrases:    s.StripPhrases,
+			SoundsLike:      s.SoundsLike,
+			TextSnippets:    s.TextSnippets,
+			TurnFilters:     s.TurnFilters,
+			RedactPII:       s.RedactPII,
+			RedactNames:     s.RedactNames,
+			FilterProfanity: s.FilterProfanity,
+			SpellLanguage:   s.SpellLanguage,
+			Numbers: config.Numbers{
+				Style:           s.NumberStyle,
+				CurrencySymbols: s.CurrencySymbols,
+				UnitSymbols:     s.UnitSymbols,
+				DateFormat:      s.DateFormat,
+				PhoneFormat:     s.PhoneFormat,
+			},
+		},
+		Meeting: config.Meeting{
+			Mixed:               s.MeetingMixed,
+			SpeakerMe:           s.SpeakerMe,
+			SpeakerOthers:       s.SpeakerOthers,
+			ConsentPrompt:       s.ConsentPrompt,
+			ConsentChecklist:    s.ConsentChecklist,
+			ConsentAnnounce:     s.ConsentAnnounce,
+			ConsentAnnouncement: s.ConsentAnnouncement,
+			Notes:               s.MeetingNotes,
+			NotesPipeline:       s.MeetingNotesPipeline,
+			CalendarURL:         s.CalendarURL,
+		},
+		Snippet: config.Snippet{Inject: s.SnippetInject, Format: s.SnippetFormat},
+		HandsFree: config.HandsFree{
+			Enabled:     s.HandsFree,
+			WakeWord:    s.WakeWord,
+			SleepPhrase: s.SleepPhrase,
+			PreRoll:     s.PreRoll,
+		},
+		Lecture: config.Lecture{Enabled: s.LectureMode, Interval: s.LectureInterval},
+		LLM: config.LLM{
+			APIKey:        s.GroqAPIKey,
+			Model:         s.GroqModel,
+			Endpoint:      s.GroqEndpoint,
+			SystemPrompt:  s.SystemPrompt,
+			Temperature:   s.LLMParams.Temperature,
+			TopP:          s.LLMParams.TopP,
+			MaxTokens:     s.LLMParams.MaxTokens,
+			MaxRetries:    s.LLMMaxRetries,
+			Timeout:       s.LLMTimeout,
+			CleanReplies:  s.CleanReplies,
+			ReplyPatterns: s.ReplyPatterns,
+			Compare: config.Compare{
+				Model:        s.CompareModel,
+				Endpoint:     s.CompareEndpoint,
+				APIKey:       s.CompareAPIKey,
+				SystemPrompt: s.CompareSystemPrompt,
+			},
+		},
+		Translate: config.Translate{
+			Provider:     s.TranslateProvider,
+			DeepLAPIKey:  s.DeepLAPIKey,
+			GoogleAPIKey: s.GoogleTranslateKey,
+			Source:       s.TranslateSource,
+			Target:       s.TranslateTarget,
+		},
+		Sinks: config.Sinks{
+			FilePath:        s.SinkFilePath,
+			FileTemplate:    s.SinkFileTemplate,
+			PipePath:        s.SinkPipePath,
+			PipeTemplate:    s.SinkPipeTemplate,
+			PipeFormat:      s.SinkPipeFormat,
+			WebhookURL:      s.SinkWebhookURL,
+			WebhookTemplate: s.SinkWebhookTemplate,
+		},
+		Digest: config.Digest{Enabled: s.WeeklyDigest, Day: s.DigestDay, Folder: s.DigestFolder},
+		Storage: config.Storage{
+			RetainAudioDays:        s.RetainAudioDays,
+			RetainTranscriptMonths: s.RetainTranscriptMonths,
+			MaxMB:                  s.MaxStorageMB,
+		},
+		Usage:   s.UsageRates.toConfig(),
+		Network: config.Network{ProxyURL: s.ProxyURL, CACertFile: s.CACertFile},
+		Team:    config.Team{ConfigURL: s.ManagedConfigURL},
+		UI: config.UI{
+			Theme:          s.Theme,
+			TranscriptFont: s.TranscriptFont,
+			FontSize:       s.FontSize,
+			ReadAloudRate:  s.ReadAloudRate,
+			Locale:         s.Locale,
+			Language:       s.UILanguage,
+			Announce:       s.Announce,
+			SoundCues:      s.SoundCues,
+			FlashCues:      s.FlashCues,
+			Notifications:  s.Notifications,
+			SeenHints:      s.SeenHints,
+			LogLevel:       s.LogLevel,
+		},
+		Shortcuts: make(map[string]string),
+	}
+	for id, binding := range s.Shortcuts {
+		c.Shortcuts[id] = binding
+	}
+	return c
+}
+
+// getConfigDir holds user files other than the config itself, such as
+// export templates.
+func (a *App) getConfigDir() string {
+	return config.Dir()
+}
+
+// openFolder creates dir if needed and opens it in the file manager.
+func (a *App) openFolder(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	u, err := url.Parse(storage.NewFileURI(dir).String())
+	if err != nil {
+		return err
+	}
+	return a.fyneApp.OpenURL(u)
+}
+
+// writeSettings saves s to store, leaving out unchanged team presets.
+func (a *App) writeSettings(store config.Store, s *Settings) error {
+	return store.Save(a.savedValues(store, s))
+}
+
+// savedValues is what writeSettings saves. Settings given at launch keep
+// their stored value unless they've been changed since.
+func (a *App) savedValues(store config.Store, s *Settings) config.Values {
+	values := a.localConfig(s)
+	if len(a.overrides) > 0 {
+		stored, _ := store.Load()
+		for path, override := range a.overrides {
+			if value, _ := values.Get(path); !reflect.DeepEqual(value, override) {
+				continue
+			}
+			if value, ok := stored.Get(path); ok {
+				values.Set(path, value)
+			} else {
+				values.Delete(path)
+			}
+		}
+	}
+	return values
+}
+
+func (a *App) loadConfig() {
+	a.loadManagedCache()
+	config, err := a.store.Load()
+	if err != nil {
+		slog.Info("using default settings", "reason", err)
+	}
+	a.config.Store(a.effectiveSettings(config))
+	a.applyLogLevel(a.settings().LogLevel)
+}
+
+func (a *App) saveConfig() {
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+
+	dialog.ShowInformation(tr("Config Saved"), tr("Settings have been saved"), a.window)
+}
+
+// Profiles are complete settings files kept in the config directory, so the
+// user can switch between setups (e.g. dictation and meetings) in one step.
+
+func (a *App) getProfilesDir() string {
+	return filepath.Join(a.getConfigDir(), "profiles")
+}
+
+func profilePath(name string) string {
+	return filepath.Join(config.Dir(), "profiles", name+".json")
+}
+
+func (a *App) profileStore(name string) config.Store {
+	return &config.FileStore{Path: profilePath(name)}
+}
+
+func (a *App) listProfiles() []string {
+	paths, _ := filepath.Glob(filepath.Join(a.getProfilesDir(), "*.json"))
+	var names []string
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// saveProfile stores s as a named profile and makes it the active profile.
+func (a *App) saveProfile(name string, s *Settings) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, `/\:`) {
+		return fmt.Errorf("Profile names can't be empty or contain / \\ or :")
+	}
+	if err := os.MkdirAll(a.getProfilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %v", err)
+	}
+
+	profile := *s
+	profile.Profile = name
+	if err := a.writeSettings(a.profileStore(name), &profile); err != nil {
+		return fmt.Errorf("failed to save profile: %v", err)
+	}
+	a.config.Store(&profile)
+	return a.writeSettings(a.store, &profile)
+}
+
+// switchProfile replaces the current settings with a saved profile. It's safe
+// while recording: the running session keeps its capture setup and the rest
+// applies from the next turn or request.
+func (a *App) switchProfile(name string) error {
+	config, err := a.profileStore(name).Load()
+	if err != nil {
+		return fmt.Errorf("failed to load profile %s: %v", name, err)
+	}
+	s := a.effectiveSettings(config)
+	s.Profile = name
+	s.SeenHints = a.settings().SeenHints
+	a.config.Store(s)
+	slog.Info("switched profile", "profile", name)
+	return a.writeSettings(a.store, s)
+}
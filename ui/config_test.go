@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"dict/config"
+)
+
+// memoryStore keeps the settings in memory.
+type memoryStore struct {
+	config config.Values
+}
+
+func (m *memoryStore) Load() (config.Values, error) {
+	return m.config, nil
+}
+
+func (m *memoryStore) Save(config config.Values) error {
+	m.config = config
+	return nil
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	temperature := 0.4
+	s := defaultSettings()
+	s.AssemblyAPIKey = "assembly-key"
+	s.CaptureSource = captureSourceMeeting
+	s.TurnSeparator = separatorBlankLine
+	s.ParagraphPause = 4
+	s.SmartJoin = true
+	s.VADBackend = vadEnergy
+	s.VADSensitivity = vadHigh
+	s.ChunkMillis = 100
+	s.InputChannel = 2
+	s.TurnDetection = turnPresets[2].Settings
+	s.LLMParams.Temperature = &temperature
+	s.LLMParams.MaxTokens = 512
+	s.CustomVocabulary = "Fyne\nmalgo"
+	s.Shortcuts["record"] = "Ctrl+Shift+R"
+	s.Announce = true
+	s.SoundCues = false
+
+	got := settingsFromConfig(s.toConfig())
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("settings changed when saved and loaded:\n got %+v\nwant %+v", got, s)
+	}
+}
+
+func TestSettingsFromConfigIgnoresInvalidValues(t *testing.T) {
+	want := defaultSettings()
+	c := want.toConfig()
+	c.Audio.VADSensitivity = "deafening"
+	c.STT.TurnDetection.Confidence = 2
+	temperature := 3.0
+	c.LLM.Temperature = &temperature
+	c.UI.FontSize = 1000
+	got := settingsFromConfig(c)
+	if got.VADSensitivity != want.VADSensitivity {
+		t.Errorf("VADSensitivity = %q, want the default %q", got.VADSensitivity, want.VADSensitivity)
+	}
+	if got.TurnDetection != want.TurnDetection {
+		t.Errorf("TurnDetection = %+v, want the default %+v", got.TurnDetection, want.TurnDetection)
+	}
+	if got.LLMParams.Temperature != nil {
+		t.Errorf("Temperature = %v, want unset", *got.LLMParams.Temperature)
+	}
+	if got.FontSize != want.FontSize {
+		t.Errorf("FontSize = %d, want the default %d", got.FontSize, want.FontSize)
+	}
+}
+
+func TestLoadAndSaveConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	store := &memoryStore{config: config.Values{
+		"version":    float64(config.Version),
+		"stt":        map[string]any{"api_key": "from-store"},
+		"transcript": map[string]any{"turn_separator": separatorSpace},
+	}}
+	a := &App{store: store}
+
+	a.loadConfig()
+	if s := a.settings(); s.AssemblyAPIKey != "from-store" || s.TurnSeparator != separatorSpace {
+		t.Fatalf("loaded %q and %q, want the stored settings", s.AssemblyAPIKey, s.TurnSeparator)
+	}
+
+	a.updateSettings(func(s *Settings) { s.GroqAPIKey = "groq-key" })
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		t.Fatal(err)
+	}
+	stt, _ := store.config["stt"].(map[string]any)
+	llm, _ := store.config["llm"].(map[string]any)
+	if stt["api_key"] != "from-store" || llm["api_key"] != "groq-key" {
+		t.Errorf("saved %v, want both keys", store.config)
+	}
+}
+
+func TestManagedSettingsApplyUntilChanged(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	a := &App{store: &memoryStore{}}
+	// Team configs written for version 1 are migrated too
+	m, err := parseManagedConfig([]byte(`{"settings": {"groq_model": "team-model", "smart_join": "true", "seen_hints": "all"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.managed.Store(m)
+	a.loadConfig()
+	if s := a.settings(); s.GroqModel != "team-model" || !s.SmartJoin || s.SeenHints != "" {
+		t.Fatalf("loaded model %q, smart join %v and hints %q, want the team's but not its hints", s.GroqModel, s.SmartJoin, s.SeenHints)
+	}
+
+	a.updateSettings(func(s *Settings) { s.SmartJoin = false })
+	local := a.localConfig(a.settings())
+	llm, _ := local["llm"].(map[string]any)
+	if _, ok := llm["model"]; ok {
+		t.Errorf("saved the team's model, so later changes to it wouldn't apply")
+	}
+	if got := a.effectiveSettings(local); got.GroqModel != "team-model" || got.SmartJoin {
+		t.Errorf("reloaded model %q and smart join %v, want the team's model and the local change", got.GroqModel, got.SmartJoin)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	a := &App{store: &config.FileStore{Path: filepath.Join(t.TempDir(), "settings.json")}}
+	a.loadConfig()
+
+	meetings := *a.settings()
+	meetings.CaptureSource = captureSourceMeeting
+	if err := a.saveProfile("Meetings", &meetings); err != nil {
+		t.Fatal(err)
+	}
+	dictation := *a.settings()
+	dictation.CaptureSource = captureSourceMicrophone
+	if err := a.saveProfile("Dictation", &dictation); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.listProfiles(); !reflect.DeepEqual(got, []string{"Dictation", "Meetings"}) {
+		t.Errorf("profiles = %q", got)
+	}
+
+	if err := a.switchProfile("Meetings"); err != nil {
+		t.Fatal(err)
+	}
+	if s := a.settings(); s.Profile != "Meetings" || s.CaptureSource != captureSourceMeeting {
+		t.Errorf("switched to %q with source %q", s.Profile, s.CaptureSource)
+	}
+	// The switch is saved, so it's the profile used on the next start
+	b := &App{store: a.store}
+	b.loadConfig()
+	if s := b.settings(); s.Profile != "Meetings" {
+		t.Errorf("restarted with profile %q, want Meetings", s.Profile)
+	}
+	if err := a.saveProfile("a/b", &dictation); err == nil {
+		t.Error("saved a profile with a slash in its name")
+	}
+}
+
+func TestLaunchOptionsArentSaved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	store := &memoryStore{config: config.Values{
+		"version": float64(config.Version),
+		"stt":     map[string]any{"api_key": "saved-key"},
+	}}
+	opts := Options{AssemblyAPIKey: "env-key", Device: "USB", Language: "de"}
+	a := &App{store: store, overrides: opts.overrides()}
+	a.loadConfig()
+	if s := a.settings(); s.AssemblyAPIKey != "env-key" || s.InputDevice != "USB" || !s.AutoLanguage {
+		t.Fatalf("loaded key %q, device %q and multilingual %v, want the options", s.AssemblyAPIKey, s.InputDevice, s.AutoLanguage)
+	}
+
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := store.config.Get("stt.api_key"); key != "saved-key" {
+		t.Errorf("saved key %v, want the stored one kept", key)
+	}
+	if device, ok := store.config.Get("audio.device"); ok {
+		t.Errorf("saved device %v, want none", device)
+	}
+
+	// Changing it in Settings saves the change
+	a.updateSettings(func(s *Settings) { s.AssemblyAPIKey = "typed-key" })
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := store.config.Get("stt.api_key"); key != "typed-key" {
+		t.Errorf("saved key %v, want the one typed in", key)
+	}
+}
+
+func TestOptionsCheck(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, opts := range []Options{{Language: "klingon"}, {Profile: "Missing"}} {
+		if err := opts.Check(); err == nil {
+			t.Errorf("Check(%+v) succeeded, want an error", opts)
+		}
+	}
+	if err := (Options{Language: languageAuto}).Check(); err != nil {
+		t.Errorf("Check(auto) = %v", err)
+	}
+}
+
+func TestImportSettingsKeepsSecrets(t *testing.T) {
+	from := &App{store: &memoryStore{}}
+	from.loadConfig()
+	from.updateSettings(func(s *Settings) {
+		s.AssemblyAPIKey = "from-key"
+		s.TurnSeparator = separatorSpace
+	})
+	data, err := from.exportSettings("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &memoryStore{}
+	to := &App{store: store}
+	to.loadConfig()
+	to.updateSettings(func(s *Settings) { s.AssemblyAPIKey = "to-key" })
+	e, err := config.ParseExport(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := to.importSettings(e, ""); err != nil {
+		t.Fatal(err)
+	}
+	if s := to.settings(); s.TurnSeparator != separatorSpace || s.AssemblyAPIKey != "to-key" {
+		t.Errorf("imported separator %q and key %q, want the export's separator and this machine's key", s.TurnSeparator, s.AssemblyAPIKey)
+	}
+	if key, _ := store.config.Get("stt.api_key"); key != "to-key" {
+		t.Errorf("saved key %v, want this machine's", key)
+	}
+}
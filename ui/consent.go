@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+const (
+	defaultConsentChecklist = "Everyone on the call knows it will be transcribed\n" +
+		"Everyone has agreed, or has had the chance to leave\n" +
+		"Recording is allowed where each participant is"
+	defaultConsentAnnouncement = "This meeting is being transcribed."
+	announcementTimeout        = 30 * time.Second
+)
+
+// consentItems returns the checklist shown before a meeting recording.
+func consentItems(cfg *Settings) []string {
+	text := cfg.ConsentChecklist
+	if strings.TrimSpace(text) == "" {
+		text = defaultConsentChecklist
+	}
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// needsConsent reports whether starting a recording should first go through
+// the consent checklist, which applies to meetings, where others are heard.
+func needsConsent(cfg *Settings) bool {
+	return cfg.ConsentPrompt && cfg.CaptureSource == captureSourceMeeting
+}
+
+// askConsent shows the consent checklist, and once every item is ticked and
+// confirmed, plays the announcement if enabled and calls start.
+func (a *App) askConsent(start func()) {
+	cfg := a.settings()
+	items := consentItems(cfg)
+	startBtn := widget.NewButton(tr("Start Recording"), nil)
+	startBtn.Importance = widget.HighImportance
+	startBtn.Disable()
+
+	checks := make([]*widget.Check, len(items))
+	onChecked := func(bool) {
+		for _, check := range checks {
+			if !check.Checked {
+				startBtn.Disable()
+				return
+			}
+		}
+		startBtn.Enable()
+	}
+	content := container.NewVBox(widget.NewLabel(tr("Before transcribing this meeting:")))
+	for i, item := range items {
+		checks[i] = widget.NewCheck(item, onChecked)
+		content.Add(checks[i])
+	}
+	if cfg.ConsentAnnounce {
+		note := widget.NewLabel(tr("The announcement will play first: \"%s\"", announcementText(cfg)))
+		note.Wrapping = fyne.TextWrapWord
+		note.Importance = widget.LowImportance
+		content.Add(note)
+	}
+
+	d := dialog.NewCustomWithoutButtons(tr("Recording Consent"), content, a.window)
+	startBtn.OnTapped = func() {
+		d.Hide()
+		slog.Info("recording consent confirmed", "items", len(items))
+		if cfg.ConsentAnnounce {
+			a.playAnnouncement(announcementText(cfg), start)
+			return
+		}
+		start()
+	}
+	d.SetButtons([]fyne.CanvasObject{widget.NewButton(tr("Cancel"), d.Hide), startBtn})
+	d.Resize(fyne.NewSize(460, 0))
+	d.Show()
+}
+
+func announcementText(cfg *Settings) string {
+	if text := strings.TrimSpace(cfg.ConsentAnnouncement); text != "" {
+		return text
+	}
+	return defaultConsentAnnouncement
+}
+
+// playAnnouncement speaks text through the output device, so others on the
+// call hear it, then calls start on the UI thread. Recording starts after
+// it so the announcement isn't transcribed as one of the speakers, and
+// doesn't start at all if it couldn't be played.
+func (a *App) playAnnouncement(text string, start func()) {
+	a.updateStatus(tr("Playing the recording announcement..."))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), announcementTimeout)
+		defer cancel()
+		pcm, rate, err := synthesizeSpeech(ctx, text, a.settings().ReadAloudRate)
+		if err == nil {
+			err = audio.Play(ctx, pcm, rate, nil)
+		}
+		fyne.Do(func() {
+			if err != nil {
+				slog.Error("announcement failed", "err", err)
+				a.updateStatus(tr("Not recording: the announcement couldn't be played"))
+				a.showError(err)
+				return
+			}
+			start()
+		})
+	}()
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Diff operations.
+const (
+	diffEqual = iota
+	diffDelete
+	diffInsert
+)
+
+// maxDiffCells bounds the word diff's table. Changes larger than this are
+// shown as a whole deletion and insertion.
+const maxDiffCells = 4_000_000
+
+// DiffChunk is a run of text that is unchanged, deleted or inserted.
+type DiffChunk struct {
+	Op   int
+	Text string
+}
+
+var diffTokenPattern = regexp.MustCompile(`\s+|[^\s]+`)
+
+// diffWords compares two texts word by word, keeping whitespace.
+func diffWords(before, after string) []DiffChunk {
+	a := diffTokenPattern.FindAllString(before, -1)
+	b := diffTokenPattern.FindAllString(after, -1)
+
+	// Most revisions change a small part of a long text, so trim the common
+	// start and end before comparing
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var chunks []DiffChunk
+	add := func(op int, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+			chunks[n-1].Text += text
+			return
+		}
+		chunks = append(chunks, DiffChunk{op, text})
+	}
+	add(diffEqual, strings.Join(a[:prefix], ""))
+	for _, c := range diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		add(c.Op, c.Text)
+	}
+	add(diffEqual, strings.Join(a[len(a)-suffix:], ""))
+	return chunks
+}
+
+// diffMiddle diffs tokens with a longest common subsequence table.
+func diffMiddle(a, b []string) []DiffChunk {
+	if len(a)*len(b) > maxDiffCells || len(a) == 0 || len(b) == 0 {
+		return []DiffChunk{{diffDelete, strings.Join(a, "")}, {diffInsert, strings.Join(b, "")}}
+	}
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	var chunks []DiffChunk
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			chunks = append(chunks, DiffChunk{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			chunks = append(chunks, DiffChunk{diffDelete, a[i]})
+			i++
+		default:
+			chunks = append(chunks, DiffChunk{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		chunks = append(chunks, DiffChunk{diffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		chunks = append(chunks, DiffChunk{diffInsert, b[j]})
+	}
+	return chunks
+}
+
+var (
+	diffDeleteStyle = widget.RichTextStyle{Inline: true, ColorName: theme.ColorNameError}
+	diffInsertStyle = widget.RichTextStyle{Inline: true, ColorName: theme.ColorNameSuccess, TextStyle: fyne.TextStyle{Bold: true}}
+)
+
+// diffSegments renders a diff with deletions in red and insertions in bold
+// green.
+func diffSegments(chunks []DiffChunk) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	for _, c := range chunks {
+		style := widget.RichTextStyleInline
+		switch c.Op {
+		case diffDelete:
+			style = diffDeleteStyle
+		case diffInsert:
+			style = diffInsertStyle
+		}
+		segments = append(segments, &widget.TextSegment{Style: style, Text: c.Text})
+	}
+	return segments
+}
+
+// diffSummary counts the words deleted and inserted.
+func diffSummary(chunks []DiffChunk) (deleted, inserted int) {
+	for _, c := range chunks {
+		switch c.Op {
+		case diffDelete:
+			deleted += countWords(c.Text)
+		case diffInsert:
+			inserted += countWords(c.Text)
+		}
+	}
+	return deleted, inserted
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// diffString marks deletions as [-text-] and insertions as {+text+}.
+func diffString(chunks []DiffChunk) string {
+	var b strings.Builder
+	for _, c := range chunks {
+		switch c.Op {
+		case diffDelete:
+			b.WriteString("[-" + c.Text + "-]")
+		case diffInsert:
+			b.WriteString("{+" + c.Text + "+}")
+		default:
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String()
+}
+
+func TestDiffWords(t *testing.T) {
+	for _, tt := range []struct {
+		before, after, want string
+		deleted, inserted   int
+	}{
+		{"same text", "same text", "same text", 0, 0},
+		{"", "new words", "{+new words+}", 0, 2},
+		{"old words", "", "[-old words-]", 2, 0},
+		{"The quick brown fox", "The slow brown fox", "The [-quick-]{+slow+} brown fox", 1, 1},
+		{"one two three", "one three", "one [-two -]three", 1, 0},
+		{"one three", "one two three", "one {+two +}three", 0, 1},
+		{"Hello world.\nBye.", "Hello world.\n\nBye now.", "Hello world.[-\nBye.-]{+\n\nBye now.+}", 1, 2},
+	} {
+		chunks := diffWords(tt.before, tt.after)
+		if got := diffString(chunks); got != tt.want {
+			t.Errorf("diffWords(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+		}
+		if deleted, inserted := diffSummary(chunks); deleted != tt.deleted || inserted != tt.inserted {
+			t.Errorf("diffSummary for %q to %q = %d, %d, want %d, %d", tt.before, tt.after, deleted, inserted, tt.deleted, tt.inserted)
+		}
+	}
+}
+
+func TestDiffWordsRebuildsBothTexts(t *testing.T) {
+	before := strings.Repeat("alpha beta gamma delta\n", 50)
+	after := strings.Replace(strings.Replace(before, "beta", "BETA", 3), "delta\nalpha", "delta alpha", 5)
+	var gotBefore, gotAfter strings.Builder
+	for _, c := range diffWords(before, after) {
+		if c.Op != diffInsert {
+			gotBefore.WriteString(c.Text)
+		}
+		if c.Op != diffDelete {
+			gotAfter.WriteString(c.Text)
+		}
+	}
+	if gotBefore.String() != before || gotAfter.String() != after {
+		t.Error("the diff doesn't rebuild the texts it compares")
+	}
+}
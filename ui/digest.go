@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	digestIDPrefix      = "digest-"
+	defaultDigestDay    = int(time.Monday)
+	digestCheckInterval = time.Hour
+	maxDigestInput      = 60000 // Characters of transcript sent to the LLM
+)
+
+// digestPipeline writes the weekly digest.
+var digestPipeline = Pipeline{
+	Name: "Weekly digest",
+	Steps: []PipelineStep{{
+		Name: "Weekly digest",
+		Prompt: `Write a digest of the week's dictation and meetings ({{title}}) from the sessions below, each headed with its date and title, in Markdown with these sections:
+## Themes
+The main topics of the week, each with a sentence or two.
+## Decisions
+Bullets of what was decided, with the session it came from.
+## Action Items
+Checkboxes ("- [ ] ") with the owner and any due date, where mentioned.
+Leave a section with "None." if nothing applies. Output only the digest.`,
+	}},
+}
+
+// digestWeek returns the week a digest written at now covers: the seven days
+// up to the most recent start of day on the digest weekday.
+func digestWeek(now time.Time, day time.Weekday) (time.Time, time.Time) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end = end.AddDate(0, 0, -((int(end.Weekday()) - int(day) + 7) % 7))
+	return end.AddDate(0, 0, -7), end
+}
+
+func digestID(end time.Time) string {
+	return digestIDPrefix + end.Format("2006-01-02")
+}
+
+func digestTitle(start, end time.Time) string {
+	return "Weekly digest: " + start.Format("2 Jan") + " – " + end.AddDate(0, 0, -1).Format("2 Jan 2006")
+}
+
+// digestInput joins the latest revision of each session in the week, oldest
+// first, shortening each one so the whole fits in maxDigestInput.
+func digestInput(sessions []*HistorySession, start, end time.Time) string {
+	var week []*HistorySession
+	for i := len(sessions) - 1; i >= 0; i-- {
+		h := sessions[i]
+		if !strings.HasPrefix(h.ID, digestIDPrefix) && !h.Started.Before(start) && h.Started.Before(end) {
+			week = append(week, h)
+		}
+	}
+	if len(week) == 0 {
+		return ""
+	}
+	limit := maxDigestInput / len(week)
+	var b strings.Builder
+	for _, h := range week {
+		title := h.Title
+		if title == "" {
+			title = "Untitled"
+		}
+		text := strings.TrimSpace(h.latest().Text)
+		if len(text) > limit {
+			text = strings.ToValidUTF8(text[:limit], "") + "…"
+		}
+		fmt.Fprintf(&b, "### %s — %s\n%s\n\n", h.Started.Format("Mon 2 Jan 15:04"), title, text)
+	}
+	return b.String()
+}
+
+// writeDigest compiles the sessions of the week ending at end into a digest,
+// saves it to history and, if a digest folder is set, writes it there as
+// Markdown. It returns the digest, or nil if there were no sessions.
+func (a *App) writeDigest(ctx context.Context, start, end time.Time) (*HistorySession, error) {
+	sessions, err := a.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	input := digestInput(sessions, start, end)
+	if input == "" {
+		return nil, nil
+	}
+
+	title := digestTitle(start, end)
+	vars := PromptContext{Now: time.Now(), Language: defaultLanguage, Title: title, MatchLanguage: a.settings().AutoLanguage}
+	results, err := a.runPipeline(ctx, digestPipeline, input, vars, func(int) {})
+	if err != nil {
+		return nil, err
+	}
+	text := "# " + title + "\n\n" + strings.TrimSpace(results[len(results)-1].Output) + "\n"
+
+	now := time.Now()
+	h := &HistorySession{
+		ID:        digestID(end),
+		Title:     title,
+		Started:   start,
+		Ended:     end,
+		Revisions: []Revision{{Created: now, Source: digestPipeline.Name, Text: text}},
+	}
+	if err := a.saveHistorySession(h); err != nil {
+		return nil, err
+	}
+	if folder := a.settings().DigestFolder; folder != "" {
+		path := filepath.Join(folder, digestPipeline.Name+" "+end.Format("2006-01-02")+".md")
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			return h, fmt.Errorf("failed to export digest: %v", err)
+		}
+		slog.Info("digest exported", "path", path)
+	}
+	return h, nil
+}
+
+// startDigestScheduler writes the weekly digest once it's due: on the
+// digest weekday, or the first time the app runs after it.
+func (a *App) startDigestScheduler() {
+	go func() {
+		check := func() {
+			cfg := a.settings()
+			if !cfg.WeeklyDigest || cfg.GroqAPIKey == "" {
+				return
+			}
+			start, end := digestWeek(time.Now(), time.Weekday(cfg.DigestDay))
+			if _, err := os.Stat(filepath.Join(a.getHistoryDir(), digestID(end)+".json")); err == nil {
+				return
+			}
+			slog.Info("writing weekly digest", "from", start, "to", end)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.LLMTimeout)*time.Second)
+			defer cancel()
+			h, err := a.writeDigest(ctx, start, end)
+			fyne.Do(func() {
+				switch {
+				case err != nil:
+					slog.Error("weekly digest failed", "err", err)
+					a.reportResult(tr("Weekly digest failed: %s", err))
+				case h != nil:
+					a.reportResult(tr("%s saved to history", h.Title))
+				}
+			})
+		}
+		check()
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			check()
+		}
+	}()
+}
+
+// writeDigestNow compiles the past seven days into a digest and opens it in
+// a new tab, unless recording.
+func (a *App) writeDigestNow() {
+	if a.settings().GroqAPIKey == "" {
+		a.updateStatus(tr("The weekly digest needs a Groq API key in Settings"))
+		return
+	}
+	end := time.Now()
+	start := end.AddDate(0, 0, -7)
+	a.updateStatus(tr("Writing the weekly digest..."))
+	ctx, done := a.startLLMTask()
+	go func() {
+		h, err := a.writeDigest(ctx, start, end)
+		fyne.Do(func() {
+			done()
+			if err != nil {
+				if err := a.llmError(err); err == nil {
+					a.updateStatus(tr("Weekly digest cancelled"))
+				} else {
+					slog.Error("weekly digest failed", "err", err)
+					a.updateStatus(tr("Weekly digest failed: %s", err))
+					a.showError(err)
+				}
+				return
+			}
+			if h == nil {
+				a.updateStatus(tr("No sessions in the past week to digest"))
+				return
+			}
+			a.reportResult(tr("%s saved to history", h.Title))
+			if !a.recording.Load() {
+				a.newTab()
+				a.textArea.SetText(h.latest().Text)
+				a.document = h
+				a.activeTab.item.Text = digestPipeline.Name
+				a.docTabs.Refresh()
+			}
+		})
+	}()
+}
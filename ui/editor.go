@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// How the pipe sink writes turns
+const (
+	pipeFormatTemplate = "template"
+	pipeFormatEditor   = "editor"
+)
+
+// editorProtocolVersion is sent with every editor message; it changes only
+// when existing fields change meaning.
+const editorProtocolVersion = 1
+
+// Undo hints for editors
+const (
+	undoBoundary = "boundary" // Start a new undo step for this utterance
+	undoJoin     = "join"     // Merge it into the previous utterance's step
+)
+
+// EditorMessage is one line of the editor protocol: a JSON object per
+// finalized turn, for the Emacs and Neovim companions in editors/ to insert
+// at point.
+type EditorMessage struct {
+	Version int    `json:"v"`
+	Type    string `json:"type"`
+	Session int    `json:"session"`
+	Turn    int    `json:"turn"`
+	Speaker string `json:"speaker,omitempty"`
+	Text    string `json:"text"`
+	Undo    string `json:"undo"`
+}
+
+// editorLine encodes a turn as an editor message. A turn that carries on the
+// previous turn's sentence is joined to its undo step, so undoing removes the
+// whole sentence.
+func editorLine(turn SinkTurn) (string, error) {
+	undo := undoBoundary
+	if turn.Continues {
+		undo = undoJoin
+	}
+	data, err := json.Marshal(EditorMessage{
+		Version: editorProtocolVersion,
+		Type:    "insert",
+		Session: turn.Session,
+		Turn:    turn.Turn,
+		Speaker: turn.Speaker,
+		Text:    turn.Text,
+		Undo:    undo,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// continuesSentence reports whether next carries on a sentence prev left
+// unfinished, from the same speaker in the same session.
+func continuesSentence(prev *SinkTurn, next SinkTurn) bool {
+	if prev == nil || prev.Session != next.Session || prev.Speaker != next.Speaker {
+		return false
+	}
+	text := strings.TrimSpace(prev.Text)
+	return text != "" && !strings.ContainsRune(".!?…", lastRune(text))
+}
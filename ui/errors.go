@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+	"dict/audio"
+)
+
+// ErrorAction is a button offered alongside an error.
+type ErrorAction struct {
+	Label string
+	Run   func(a *App)
+}
+
+// remediation suggests what to do about an error.
+func remediation(err error) (string, []ErrorAction) {
+	openSettings := ErrorAction{tr("Open Settings"), (*App).showSettingsModal}
+
+	var auth *apierr.AuthError
+	var quota *apierr.QuotaError
+	var device *audio.DeviceError
+	var network *apierr.NetworkError
+	switch {
+	case errors.As(err, &auth):
+		return tr("Check the %s API key in Settings.", auth.Service), []ErrorAction{openSettings}
+	case errors.As(err, &quota):
+		return tr("%s is rate limiting requests or your plan's quota is used up. Wait a moment, check your account, or switch to another key or profile.", quota.Service),
+			[]ErrorAction{openSettings, {tr("Show Usage"), (*App).showUsagePanel}}
+	case errors.As(err, &device):
+		return tr("Check the device is connected and not in use by another app, or choose another audio source."),
+			[]ErrorAction{{tr("Choose Another Source"), (*App).showSettingsModal}}
+	case errors.As(err, &network):
+		return tr("Couldn't reach %s. Check your connection and the proxy settings.", network.Service),
+			[]ErrorAction{{tr("Network Settings"), (*App).showSettingsModal}}
+	}
+	return "", nil
+}
+
+// showError shows an error with a hint and buttons to fix it, where known.
+// Call it on the UI thread.
+func (a *App) showError(err error) {
+	a.notify(tr("Error"), err.Error())
+	hint, actions := remediation(err)
+	if hint == "" {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	slog.Debug("showing error", "err", err, "actions", len(actions))
+
+	message := widget.NewLabel(err.Error())
+	message.Wrapping = fyne.TextWrapWord
+	hintLabel := widget.NewLabel(hint)
+	hintLabel.Wrapping = fyne.TextWrapWord
+	hintLabel.Importance = widget.LowImportance
+	content := container.NewVBox(message, hintLabel)
+
+	d := dialog.NewCustomWithoutButtons(tr("Error"), content, a.window)
+	var buttons []fyne.CanvasObject
+	for _, action := range actions {
+		action := action
+		btn := widget.NewButton(action.Label, func() {
+			d.Hide()
+			action.Run(a)
+		})
+		btn.Importance = widget.HighImportance
+		buttons = append(buttons, btn)
+	}
+	buttons = append(buttons, widget.NewButton(tr("Close"), d.Hide))
+	d.SetButtons(buttons)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+}
@@ -0,0 +1,325 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ExportDocument is the data handed to formatters, including user templates.
+type ExportDocument struct {
+	Title     string       `json:"title,omitempty"`
+	Attendees []string     `json:"attendees,omitempty"`
+	Date      time.Time    `json:"date"`
+	Locale    string       `json:"locale,omitempty"` // For formatting dates and numbers
+	Text      string       `json:"text"`
+	Turns     []ExportTurn `json:"turns"`
+
+	People []PersonSummary `json:"people,omitempty"` // Mentions of each attendee
+}
+
+// ExportTurn is a finalized turn, timed relative to the first exported turn.
+type ExportTurn struct {
+	Speaker string        `json:"speaker,omitempty"`
+	Text    string        `json:"text"`
+	Start   time.Duration `json:"start_ms"`
+	End     time.Duration `json:"end_ms"`
+	Time    time.Time     `json:"time"`
+}
+
+// MarshalJSON reports offsets in milliseconds rather than nanoseconds.
+func (t ExportTurn) MarshalJSON() ([]byte, error) {
+	type turn ExportTurn
+	out := turn(t)
+	out.Start /= time.Millisecond
+	out.End /= time.Millisecond
+	return json.Marshal(out)
+}
+
+// Formatter renders an ExportDocument for one export type.
+type Formatter struct {
+	Name      string
+	Extension string
+	Format    func(doc ExportDocument) ([]byte, error)
+}
+
+var builtinFormatters = []Formatter{
+	{Name: "Plain Text", Extension: ".txt", Format: formatPlain},
+	{Name: "Markdown", Extension: ".md", Format: formatMarkdown},
+	{Name: "SubRip Subtitles (SRT)", Extension: ".srt", Format: formatSRT},
+	{Name: "JSON", Extension: ".json", Format: formatJSON},
+}
+
+func formatPlain(doc ExportDocument) ([]byte, error) {
+	return []byte(doc.Text + "\n"), nil
+}
+
+func formatMarkdown(doc ExportDocument) ([]byte, error) {
+	var b strings.Builder
+	title := doc.Title
+	if title == "" {
+		title = "Transcript"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	loc := localeFor(doc.Locale)
+	fmt.Fprintf(&b, "_%s %s_", loc.longDate(doc.Date), loc.clock(doc.Date))
+	if len(doc.Attendees) > 0 {
+		fmt.Fprintf(&b, " · %s", strings.Join(doc.Attendees, ", "))
+	}
+	b.WriteString("\n\n")
+
+	for _, line := range strings.Split(doc.Text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		// Bold speaker labels
+		if speaker, text, ok := strings.Cut(line, ": "); ok && len(speaker) <= 30 && !strings.ContainsAny(speaker, ".!?") {
+			line = "**" + speaker + ":** " + text
+		}
+		b.WriteString(line + "\n\n")
+	}
+
+	if len(doc.People) > 0 {
+		b.WriteString("## People\n\n")
+		for _, person := range doc.People {
+			fmt.Fprintf(&b, "### %s\n\n", person.Name)
+			if len(person.Mentions) == 0 {
+				b.WriteString("Not mentioned.\n\n")
+				continue
+			}
+			var times []string
+			for _, turn := range person.Mentions {
+				times = append(times, clockTime(turn.Start))
+			}
+			fmt.Fprintf(&b, "Mentioned in: %s\n\n", strings.Join(times, ", "))
+			for _, item := range person.ActionItems {
+				fmt.Fprintf(&b, "- [ ] %s\n", item)
+			}
+			if len(person.ActionItems) > 0 {
+				b.WriteString("\n")
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+func formatSRT(doc ExportDocument) ([]byte, error) {
+	if len(doc.Turns) == 0 {
+		return nil, fmt.Errorf("no recorded turns to export as subtitles")
+	}
+
+	var b strings.Builder
+	for i, turn := range doc.Turns {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, srtTime(turn.Start), srtTime(turn.End), Turn{Speaker: turn.Speaker, Text: turn.Text}.display())
+	}
+	return []byte(b.String()), nil
+}
+
+func formatJSON(doc ExportDocument) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// srtTime formats an offset as HH:MM:SS,mmm.
+func srtTime(d time.Duration) string {
+	d = max(d, 0)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, d.Milliseconds()%1000)
+}
+
+// clockTime formats an offset as H:MM:SS.
+func clockTime(d time.Duration) string {
+	d = max(d, 0)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+var templateFuncs = template.FuncMap{
+	"srtTime":   srtTime,
+	"clockTime": clockTime,
+	"join":      strings.Join,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trim":      strings.TrimSpace,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"lines": func(text string) []string {
+		var lines []string
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	},
+}
+
+func (a *App) getTemplatesDir() string {
+	return filepath.Join(a.getConfigDir(), "templates")
+}
+
+// loadTemplateFormatters reads user export templates. A template named
+// "minutes.md.tmpl" appears as "minutes" and is saved with a .md extension.
+func loadTemplateFormatters(dir string) []Formatter {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+
+	var formatters []Formatter
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		ext := filepath.Ext(name)
+		if ext == "" {
+			ext = ".txt"
+		}
+		name = strings.TrimSuffix(name, ext)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("failed to read template", "path", path, "err", err)
+			continue
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(data))
+		if err != nil {
+			slog.Warn("failed to parse template", "path", path, "err", err)
+			continue
+		}
+
+		formatters = append(formatters, Formatter{
+			Name:      name,
+			Extension: ext,
+			Format: func(doc ExportDocument) ([]byte, error) {
+				tmpl, err := tmpl.Clone()
+				if err != nil {
+					return nil, fmt.Errorf("template %s: %v", name, err)
+				}
+				tmpl.Funcs(localeFor(doc.Locale).funcs())
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, doc); err != nil {
+					return nil, fmt.Errorf("template %s: %v", name, err)
+				}
+				return buf.Bytes(), nil
+			},
+		})
+	}
+	return formatters
+}
+
+// formatters returns the built-in formatters followed by the user's templates,
+// which are re-read so new templates show up without a restart.
+func (a *App) formatters() []Formatter {
+	return append(append([]Formatter{}, builtinFormatters...), loadTemplateFormatters(a.getTemplatesDir())...)
+}
+
+func (a *App) exportDocument() ExportDocument {
+	doc := ExportDocument{
+		Title:     a.sessionTitle,
+		Attendees: a.sessionAttendees,
+		Date:      time.Now(),
+		Locale:    a.settings().Locale,
+		Text:      a.textArea.Text,
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	doc.addTurns(a.turns)
+	return doc
+}
+
+// addTurns adds turns to doc, timed from the first, and dates it by when
+// the first was spoken.
+func (doc *ExportDocument) addTurns(turns []Turn) {
+	var first time.Time
+	for _, turn := range turns {
+		if turn.Text == "" {
+			continue
+		}
+		if first.IsZero() {
+			first = turn.Start
+			doc.Date = turn.Start
+		}
+		doc.Turns = append(doc.Turns, ExportTurn{
+			Speaker: turn.Speaker,
+			Text:    turn.Text,
+			Start:   turn.Start.Sub(first),
+			End:     turn.End.Sub(first),
+			Time:    turn.Start,
+		})
+	}
+	doc.People = summarizePeople(doc.Attendees, doc.Turns)
+}
+
+// formatMenuItems has an item per formatter, which export is called with.
+func (a *App) formatMenuItems(export func(f Formatter)) []*fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, f := range a.formatters() {
+		f := f
+		items = append(items, fyne.NewMenuItem(f.Name, func() { export(f) }))
+	}
+	return items
+}
+
+// showMenuBelow pops up a menu under a button.
+func (a *App) showMenuBelow(button fyne.CanvasObject, items []*fyne.MenuItem) {
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button)
+	pos = pos.Add(fyne.NewPos(0, button.Size().Height))
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), a.window.Canvas(), pos)
+}
+
+func (a *App) showExportMenu() {
+	items := a.formatMenuItems(a.exportAs)
+	items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(tr("Anki Flashcards (via LLM)"), a.exportAnki))
+	items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(tr("Open Templates Folder"), a.openTemplatesDir))
+	a.showMenuBelow(a.exportBtn, items)
+}
+
+func (a *App) exportAs(f Formatter) {
+	a.exportDocumentAs(f, a.exportDocument(), "transcript")
+}
+
+// exportDocumentAs formats doc and asks where to save it, suggesting name
+// as the file name.
+func (a *App) exportDocumentAs(f Formatter, doc ExportDocument, name string) {
+	data, err := f.Format(doc)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.saveExport(name+f.Extension, data, f.Name)
+}
+
+// saveExport asks where to save an export and writes data there.
+func (a *App) saveExport(fileName string, data []byte, format string) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write(data); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to write export: %v", err), a.window)
+			return
+		}
+		slog.Info("exported transcript", "format", format, "uri", writer.URI())
+		a.updateStatus(tr("Exported %s", writer.URI().Name()))
+	}, a.window)
+	save.SetFileName(fileName)
+	save.Show()
+}
+
+func (a *App) openTemplatesDir() {
+	if err := a.openFolder(a.getTemplatesDir()); err != nil {
+		dialog.ShowError(err, a.window)
+	}
+}
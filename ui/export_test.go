@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFormatMenuItemsExportTheirFormatter(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	a := &App{}
+	if err := os.MkdirAll(a.getTemplatesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(a.getTemplatesDir(), "minutes.md.tmpl"), []byte("{{.Text}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var labels, exported []string
+	for _, item := range a.formatMenuItems(func(f Formatter) { exported = append(exported, f.Name) }) {
+		labels = append(labels, item.Label)
+		item.Action()
+	}
+	if len(labels) != len(builtinFormatters)+1 || labels[len(labels)-1] != "minutes" {
+		t.Errorf("menu items = %v, want the built-in formats and minutes", labels)
+	}
+	if !slices.Equal(exported, labels) {
+		t.Errorf("menu items %v exported %v", labels, exported)
+	}
+}
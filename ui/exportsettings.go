@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/config"
+)
+
+// exportSettings returns the settings as saved, to import on another
+// machine. API keys and other secrets are left out unless a passphrase is
+// given to encrypt them with.
+func (a *App) exportSettings(passphrase string) ([]byte, error) {
+	e, err := config.NewExport(a.savedValues(a.store, a.settings()), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// importSettings replaces the settings with an export's and saves them.
+// Secrets the export doesn't include, or that aren't decrypted for lack of
+// a passphrase, keep their values on this machine.
+func (a *App) importSettings(e *config.Export, passphrase string) error {
+	v, err := e.Open(passphrase)
+	if err != nil {
+		return err
+	}
+	local := a.localConfig(a.settings())
+	for _, path := range config.Secrets {
+		if _, ok := v.Get(path); ok {
+			continue
+		}
+		if value, ok := local.Get(path); ok {
+			v.Set(path, value)
+		}
+	}
+	s := a.effectiveSettings(v)
+	a.config.Store(s)
+	slog.Info("imported settings", "secrets", e.Encrypted() && passphrase != "")
+	return a.writeSettings(a.store, s)
+}
+
+func (a *App) showExportSettings() {
+	includeSecrets := widget.NewCheck(tr("Include API keys, encrypted"), nil)
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder(tr("Needed to import them"))
+	passphraseEntry.Disable()
+	includeSecrets.OnChanged = func(on bool) {
+		if on {
+			passphraseEntry.Enable()
+		} else {
+			passphraseEntry.Disable()
+		}
+	}
+	dialog.ShowForm(tr("Export Settings"), tr("Export..."), tr("Cancel"), []*widget.FormItem{
+		widget.NewFormItem("", includeSecrets),
+		widget.NewFormItem(tr("Passphrase"), passphraseEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		passphrase := ""
+		if includeSecrets.Checked {
+			if passphraseEntry.Text == "" {
+				a.showError(fmt.Errorf("Enter a passphrase to encrypt the API keys with"))
+				return
+			}
+			passphrase = passphraseEntry.Text
+		}
+		data, err := a.exportSettings(passphrase)
+		if err != nil {
+			a.showError(fmt.Errorf("failed to export settings: %v", err))
+			return
+		}
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write(data); err != nil {
+				a.showError(fmt.Errorf("failed to export settings: %v", err))
+				return
+			}
+			a.updateStatus(tr("Exported settings to %s", writer.URI().Name()))
+		}, a.window)
+		save.SetFileName("voice-typing-settings.json")
+		save.Show()
+	}, a.window)
+}
+
+// showImportSettings asks for an export to import, and its passphrase if it
+// has encrypted secrets. done is called once the settings have changed.
+func (a *App) showImportSettings(done func()) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		name := reader.URI().Name()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			a.showError(fmt.Errorf("failed to read %s: %v", name, err))
+			return
+		}
+		e, err := config.ParseExport(data)
+		if err != nil {
+			a.showError(err)
+			return
+		}
+		apply := func(passphrase string) {
+			if err := a.importSettings(e, passphrase); err != nil {
+				a.showError(fmt.Errorf("failed to import settings: %v", err))
+				return
+			}
+			a.applyShortcuts()
+			a.applyAppearance()
+			a.updateStatus(tr("Imported settings from %s", name))
+			if done != nil {
+				done()
+			}
+		}
+		if !e.Encrypted() {
+			apply("")
+			return
+		}
+		passphraseEntry := widget.NewPasswordEntry()
+		passphraseEntry.SetPlaceHolder(tr("Leave empty to keep this machine's keys"))
+		dialog.ShowForm(tr("Import Settings"), tr("Import"), tr("Cancel"), []*widget.FormItem{
+			widget.NewFormItem(tr("Passphrase"), passphraseEntry),
+		}, func(ok bool) {
+			if ok {
+				apply(passphraseEntry.Text)
+			}
+		}, a.window)
+	}, a.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	open.Show()
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Filter verdicts for a finalized turn.
+const (
+	filterPass = "pass"
+	filterHold = "hold" // Kept back until the user releases it
+	filterDrop = "drop"
+)
+
+// secretPattern matches things that look like passwords, API keys or card
+// numbers; rules can refer to it as "secrets".
+const secretPattern = `(?i)\b(password|passcode|pin)\b\s*(is|:|=)|\b(sk|pk|ghp|xox[bp])[-_][A-Za-z0-9_-]{10,}|\b[A-Za-z0-9+/_-]{32,}\b|\b\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{4}\b`
+
+// FilterRule drops, holds or rewrites turns matching a pattern before they
+// reach the outputs (the file, pipe and webhook sinks, and insertion at the
+// cursor).
+type FilterRule struct {
+	action      string // filterDrop, filterHold or "replace"
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// parseFilterRules parses one rule per line:
+//
+//	drop <regex>
+//	hold <regex>
+//	replace <regex> => <replacement>
+//
+// Blank lines and lines starting with # are ignored.
+func parseFilterRules(text string) ([]FilterRule, error) {
+	var rules []FilterRule
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		action, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		rule := FilterRule{action: action}
+		if action == "replace" {
+			var ok bool
+			rest, rule.replacement, ok = strings.Cut(rest, "=>")
+			if !ok {
+				return nil, fmt.Errorf("filter rule %d: replace needs \"=> replacement\"", i+1)
+			}
+			rest = strings.TrimSpace(rest)
+			rule.replacement = strings.TrimSpace(rule.replacement)
+		} else if action != filterDrop && action != filterHold {
+			return nil, fmt.Errorf("filter rule %d: unknown action %q (use drop, hold or replace)", i+1, action)
+		}
+		if rest == "secrets" {
+			rest = secretPattern
+		}
+		if rest == "" {
+			return nil, fmt.Errorf("filter rule %d: missing pattern", i+1)
+		}
+		re, err := regexp.Compile(rest)
+		if err != nil {
+			return nil, fmt.Errorf("filter rule %d: %v", i+1, err)
+		}
+		rule.pattern = re
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// applyFilters runs the rules in order, returning the rewritten text and the
+// verdict. A drop rule wins over hold.
+func applyFilters(rules []FilterRule, text string) (string, string) {
+	verdict := filterPass
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(text) {
+			continue
+		}
+		switch rule.action {
+		case filterDrop:
+			return text, filterDrop
+		case filterHold:
+			verdict = filterHold
+		default:
+			text = rule.pattern.ReplaceAllString(text, rule.replacement)
+		}
+	}
+	return text, verdict
+}
+
+// HeldTurn is a turn held back by a filter for review.
+type HeldTurn struct {
+	Turn   Turn
+	Insert bool // Insert at the cursor when released
+}
+
+// filterTurn applies the turn filters to a finalized turn before it's sent
+// to the outputs. Held turns are queued for review. Caller must hold a.mu.
+func (a *App) filterTurn(turn Turn, insert bool) (Turn, string) {
+	rules, err := parseFilterRules(a.settings().TurnFilters)
+	if err != nil {
+		// Settings validation rejects bad rules, so this only happens with a
+		// hand-edited config. Hold everything rather than leak it.
+		slog.Error("turn filters invalid", "err", err)
+		rules = []FilterRule{{action: filterHold, pattern: regexp.MustCompile(``)}}
+	}
+	var verdict string
+	turn.Text, verdict = applyFilters(rules, turn.Text)
+	switch verdict {
+	case filterHold:
+		a.heldTurns = append(a.heldTurns, HeldTurn{Turn: turn, Insert: insert})
+		slog.Info("turn held by filter", "turn", turn.Order)
+	case filterDrop:
+		slog.Info("turn dropped by filter", "turn", turn.Order)
+	}
+	return turn, verdict
+}
+
+func (a *App) newHeldBanner() fyne.CanvasObject {
+	a.heldLbl = widget.NewLabel("")
+	a.heldLbl.Importance = widget.WarningImportance
+	reviewBtn := widget.NewButtonWithIcon(tr("Review"), theme.VisibilityIcon(), a.showHeldTurns)
+	a.heldBanner = container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), reviewBtn, a.heldLbl)
+	a.heldBanner.Hide()
+	return a.heldBanner
+}
+
+// updateHeldBanner shows how many turns are waiting for review.
+func (a *App) updateHeldBanner() {
+	a.mu.RLock()
+	held := len(a.heldTurns)
+	a.mu.RUnlock()
+	if held == 0 {
+		a.heldBanner.Hide()
+		return
+	}
+	a.heldLbl.SetText(tr("%d turns held by filters for review", held))
+	a.heldBanner.Show()
+}
+
+// releaseTurn sends a held turn on to the outputs.
+func (a *App) releaseTurn(held HeldTurn) {
+	a.mu.Lock()
+	a.sendToSinks(held.Turn)
+	a.mu.Unlock()
+	if held.Insert {
+		a.insertAtCaret(held.Turn.display())
+	}
+}
+
+func (a *App) showHeldTurns() {
+	a.mu.Lock()
+	held := a.heldTurns
+	a.heldTurns = nil
+	a.mu.Unlock()
+	a.updateHeldBanner()
+	if len(held) == 0 {
+		return
+	}
+
+	checks := make([]*widget.Check, len(held))
+	list := container.NewVBox()
+	for i, h := range held {
+		checks[i] = widget.NewCheck(h.Turn.display(), nil)
+		list.Add(checks[i])
+	}
+
+	var reviewDialog dialog.Dialog
+	releaseBtn := widget.NewButtonWithIcon(tr("Release Checked"), theme.ConfirmIcon(), func() {
+		for i, h := range held {
+			if checks[i].Checked {
+				a.releaseTurn(h)
+			}
+		}
+		reviewDialog.Hide()
+	})
+	content := container.NewBorder(
+		widget.NewLabel(tr("Checked turns are sent to the outputs; the rest are discarded.")),
+		releaseBtn, nil, nil,
+		container.NewVScroll(list),
+	)
+	reviewDialog = dialog.NewCustom(tr("Held Turns"), tr("Discard All"), content, a.window)
+	reviewDialog.Resize(fyne.NewSize(560, 400))
+	reviewDialog.Show()
+}
@@ -0,0 +1,48 @@
+package ui
+
+import "testing"
+
+func TestApplyFilters(t *testing.T) {
+	rules, err := parseFilterRules(`
+# Comments and blank lines are skipped
+
+replace (?i)\s*\bumm?\b =>
+replace colour => color
+hold (?i)confidential
+drop (?i)^off the record
+hold secrets
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		text, want, verdict string
+	}{
+		{"The colour is red", "The color is red", filterPass},
+		{"So umm the plan", "So the plan", filterPass},
+		{"This is confidential", "This is confidential", filterHold},
+		{"Off the record, it's confidential", "Off the record, it's confidential", filterDrop},
+		{"My password is hunter2", "My password is hunter2", filterHold},
+		{"Card 4111 1111 1111 1111", "Card 4111 1111 1111 1111", filterHold},
+		{"Key sk-abcdefghijklmnop", "Key sk-abcdefghijklmnop", filterHold},
+		{"Pin the notes", "Pin the notes", filterPass},
+	} {
+		text, verdict := applyFilters(rules, tt.text)
+		if text != tt.want || verdict != tt.verdict {
+			t.Errorf("applyFilters(%q) = %q, %s, want %q, %s", tt.text, text, verdict, tt.want, tt.verdict)
+		}
+	}
+}
+
+func TestParseFilterRulesErrors(t *testing.T) {
+	for _, text := range []string{
+		"mute something",
+		"drop",
+		"replace colour",
+		"hold (unclosed",
+	} {
+		if _, err := parseFilterRules(text); err == nil {
+			t.Errorf("parseFilterRules(%q) succeeded", text)
+		}
+	}
+}
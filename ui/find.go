@@ -0,0 +1,294 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const revisionReplaced = "Replace all"
+
+// FindBar searches the transcript and replaces matches.
+type FindBar struct {
+	box        *fyne.Container
+	replaceRow *fyne.Container
+	query      *PaletteEntry
+	with       *PaletteEntry
+	matchCase  *widget.Check
+	regex      *widget.Check
+	countLbl   *widget.Label
+	current    int // Index of the selected match
+}
+
+// findMatches returns the submatch byte ranges of every non-empty match of
+// query in text.
+func findMatches(text, query string, matchCase, useRegex bool) ([][]int, *regexp.Regexp, error) {
+	if query == "" {
+		return nil, nil, nil
+	}
+	pattern := query
+	if !useRegex {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if !matchCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+	var matches [][]int
+	for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] < m[1] {
+			matches = append(matches, m)
+		}
+	}
+	return matches, re, nil
+}
+
+// replacement is what a match is replaced with; regex replacements can
+// refer to groups as $1 or ${name}.
+func replacement(re *regexp.Regexp, text, with string, match []int, useRegex bool) string {
+	if !useRegex {
+		return with
+	}
+	return string(re.ExpandString(nil, with, text, match))
+}
+
+func (a *App) newFindBar() fyne.CanvasObject {
+	f := &FindBar{}
+	a.find = f
+	f.query = newPaletteEntry()
+	f.query.SetPlaceHolder(tr("Find"))
+	f.with = newPaletteEntry()
+	f.with.SetPlaceHolder(tr("Replace with"))
+	f.matchCase = widget.NewCheck(tr("Match case"), func(bool) { a.search(0) })
+	f.regex = widget.NewCheck(tr("Regex"), func(bool) { a.search(0) })
+	f.countLbl = widget.NewLabel("")
+
+	f.query.OnChanged = func(string) {
+		f.current = 0
+		a.search(0)
+	}
+	f.query.OnSubmitted = func(string) { a.findNext(1) }
+	f.query.onMove = a.findNext
+	f.query.onEsc = a.closeFind
+	f.with.OnSubmitted = func(string) { a.replaceCurrent() }
+	f.with.onMove = a.findNext
+	f.with.onEsc = a.closeFind
+
+	prevBtn := newTipButton("", theme.MoveUpIcon(), tr("Previous match"), func() { a.findNext(-1) })
+	nextBtn := newTipButton("", theme.MoveDownIcon(), tr("Next match"), func() { a.findNext(1) })
+	closeBtn := newTipButton("", theme.CancelIcon(), tr("Close the find bar"), a.closeFind)
+	replaceBtn := widget.NewButton(tr("Replace"), a.replaceCurrent)
+	replaceAllBtn := widget.NewButton(tr("Replace All"), a.replaceAll)
+
+	findRow := container.NewBorder(nil, nil, nil, container.NewHBox(f.countLbl, f.matchCase, f.regex, prevBtn, nextBtn, closeBtn), f.query)
+	f.replaceRow = container.NewBorder(nil, nil, nil, container.NewHBox(replaceBtn, replaceAllBtn), f.with)
+	f.box = container.NewVBox(findRow, f.replaceRow)
+	f.box.Hide()
+	return f.box
+}
+
+// showFind opens the bar, seeded with any selected text.
+func (a *App) showFind() {
+	a.openFind(false)
+}
+
+func (a *App) showReplace() {
+	a.openFind(true)
+}
+
+func (a *App) openFind(replace bool) {
+	f := a.find
+	if selected := a.textArea.SelectedText(); selected != "" && !strings.Contains(selected, "\n") {
+		f.query.SetText(selected)
+	}
+	if replace {
+		f.replaceRow.Show()
+	} else {
+		f.replaceRow.Hide()
+	}
+	f.box.Show()
+	a.window.Canvas().Focus(f.query)
+	a.search(0)
+}
+
+func (a *App) closeFind() {
+	a.find.box.Hide()
+	a.window.Canvas().Focus(a.textArea)
+}
+
+// search finds every match, then selects the one delta after the current.
+func (a *App) search(delta int) {
+	f := a.find
+	matches, _, err := findMatches(a.textArea.Text, f.query.Text, f.matchCase.Checked, f.regex.Checked)
+	if err != nil {
+		f.countLbl.SetText(tr("Invalid"))
+		return
+	}
+	if len(matches) == 0 {
+		f.countLbl.SetText(tr("No matches"))
+		if f.query.Text == "" {
+			f.countLbl.SetText("")
+		}
+		return
+	}
+	f.current = ((f.current+delta)%len(matches) + len(matches)) % len(matches)
+	f.countLbl.SetText(tr("%d of %d", f.current+1, len(matches)))
+	m := matches[f.current]
+	a.textArea.selectRange(m[0], m[1])
+}
+
+// findNext moves to the next match, or the previous one if delta is -1.
+func (a *App) findNext(delta int) {
+	if a.liveScroll.Visible() {
+		a.updateStatus(tr("Switch to Edit to search the transcript while recording"))
+		return
+	}
+	a.search(delta)
+}
+
+// replaceCurrent replaces the selected match and moves to the next.
+func (a *App) replaceCurrent() {
+	f := a.find
+	if a.liveScroll.Visible() {
+		a.updateStatus(tr("Switch to Edit to replace text while recording"))
+		return
+	}
+	text := a.textArea.Text
+	matches, re, err := findMatches(text, f.query.Text, f.matchCase.Checked, f.regex.Checked)
+	if err != nil || len(matches) == 0 {
+		return
+	}
+	m := matches[min(f.current, len(matches)-1)]
+	if text[m[0]:m[1]] != a.textArea.SelectedText() {
+		// Nothing selected yet, so show the match first
+		a.search(0)
+		return
+	}
+	a.textArea.SetText(text[:m[0]] + replacement(re, text, f.with.Text, m, f.regex.Checked) + text[m[1]:])
+	a.search(0)
+}
+
+// replaceAll replaces every match at once. It can be undone, and is kept
+// as a revision.
+func (a *App) replaceAll() {
+	f := a.find
+	if a.liveScroll.Visible() {
+		a.updateStatus(tr("Switch to Edit to replace text while recording"))
+		return
+	}
+	text := a.textArea.Text
+	matches, re, err := findMatches(text, f.query.Text, f.matchCase.Checked, f.regex.Checked)
+	if err != nil {
+		f.countLbl.SetText(tr("Invalid"))
+		return
+	}
+	if len(matches) == 0 {
+		return
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m[0]])
+		b.WriteString(replacement(re, text, f.with.Text, m, f.regex.Checked))
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+
+	a.previousText = text
+	a.textArea.SetText(b.String())
+	a.undoBtn.Enable()
+	a.addRevision(revisionReplaced)
+	f.countLbl.SetText("")
+	a.updateStatus(tr("Replaced %d matches", len(matches)))
+}
+
+// The Entry's caret is a displayed row and column, and wrapped lines take
+// several rows. Only its selection tells where a row starts, so these find
+// rows by selecting from the top of the text. Each key press rewraps the
+// text, so they search with as few as they can.
+
+// rowOffset returns the byte offset where a row starts; rows past the end
+// start at the end.
+func (e *TranscriptEntry) rowOffset(row int) int {
+	if row <= 0 {
+		return 0
+	}
+	e.pressKey(fyne.KeyPageUp, 1, false)
+	e.Entry.KeyDown(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	e.CursorRow = row
+	e.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyHome})
+	e.Entry.KeyUp(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	return len(e.SelectedText())
+}
+
+// caretAt returns the row and column of a byte offset.
+func (e *TranscriptEntry) caretAt(offset int) (row, column int) {
+	e.pressKey(fyne.KeyPageDown, 1, false)
+	lo, hi := 0, e.CursorRow
+	loStart, hiStart := 0, e.rowOffset(hi)
+	if hiStart <= offset {
+		lo, loStart = hi, hiStart
+	}
+	for i := 0; hi-lo > 1 && hiStart > offset; i++ {
+		// Rows are much the same length, so guess where offset falls, but
+		// bisect every other step in case they aren't
+		mid := lo + (hi-lo)/2
+		if i%2 == 0 {
+			mid = max(lo+1, min(hi-1, lo+(offset-loStart)*(hi-lo)/(hiStart-loStart)))
+		}
+		if start := e.rowOffset(mid); start <= offset {
+			lo, loStart = mid, start
+		} else {
+			hi, hiStart = mid, start
+		}
+	}
+	return lo, utf8.RuneCountInString(e.Text[loStart:offset])
+}
+
+// moveCaretTo puts the caret at a byte offset, with nothing selected.
+func (e *TranscriptEntry) moveCaretTo(offset int) {
+	e.setCaret(e.caretAt(min(offset, len(e.Text))))
+}
+
+func (e *TranscriptEntry) setCaret(row, column int) {
+	e.pressKey(fyne.KeyPageUp, 1, false)
+	e.CursorRow, e.CursorColumn = row, column
+	e.Refresh()
+}
+
+// caretOffset returns the caret's byte offset in the text.
+func (e *TranscriptEntry) caretOffset() int {
+	row, column := e.CursorRow, e.CursorColumn
+	offset := e.rowOffset(row)
+	for i := 0; i < column && offset < len(e.Text); i++ {
+		_, size := utf8.DecodeRuneInString(e.Text[offset:])
+		offset += size
+	}
+	e.setCaret(row, column)
+	return offset
+}
+
+// selectRange selects text between two byte offsets.
+func (e *TranscriptEntry) selectRange(start, end int) {
+	endRow, endColumn := e.caretAt(end)
+	row, column := endRow, endColumn-utf8.RuneCountInString(e.Text[start:end])
+	if column < 0 {
+		row, column = e.caretAt(start)
+	}
+	e.setCaret(row, column)
+	e.Entry.KeyDown(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	// Step back onto the end so the Entry counts it as selecting
+	e.CursorRow, e.CursorColumn = endRow, endColumn+1
+	e.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	e.Entry.KeyUp(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+}
@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/gorilla/websocket"
+
+	"dict/audio"
+	"dict/stt"
+)
+
+// mockAssembly stands in for AssemblyAI's streaming API. Each connection
+// replays one recorded session from testdata: Begin straight away, the turns
+// once audio arrives, and Termination when the client asks for it. A
+// {"type":"Disconnect"} line drops the connection instead, as a flaky
+// network would.
+type mockAssembly struct {
+	t        *testing.T
+	srv      *httptest.Server
+	sessions [][]string
+
+	mu      sync.Mutex
+	conns   int
+	queries []url.Values
+	keys    []string
+	audio   [][]byte // By connection
+	texts   []string
+}
+
+func newMockAssembly(t *testing.T, sessions ...string) *mockAssembly {
+	t.Helper()
+	m := &mockAssembly{t: t}
+	for _, name := range sessions {
+		m.sessions = append(m.sessions, readSession(t, name))
+	}
+	m.srv = httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(m.srv.Close)
+	return m
+}
+
+func readSession(t *testing.T, name string) []string {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+func (m *mockAssembly) serve(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	m.mu.Lock()
+	n := m.conns
+	m.conns++
+	m.queries = append(m.queries, r.URL.Query())
+	m.keys = append(m.keys, r.Header.Get("Authorization"))
+	m.audio = append(m.audio, nil)
+	m.mu.Unlock()
+	var script []string
+	if n < len(m.sessions) {
+		script = m.sessions[n]
+	}
+
+	heard := make(chan struct{})
+	terminate := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var heardOnce, terminateOnce sync.Once
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			m.mu.Lock()
+			if kind == websocket.BinaryMessage {
+				m.audio[n] = append(m.audio[n], data...)
+				heardOnce.Do(func() { close(heard) })
+			} else {
+				m.texts = append(m.texts, string(data))
+				if strings.Contains(string(data), `"Terminate"`) {
+					terminateOnce.Do(func() { close(terminate) })
+				}
+			}
+			m.mu.Unlock()
+		}
+	}()
+	wait := func(ch chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		case <-closed:
+			return false
+		}
+	}
+
+	for _, line := range script {
+		var msg struct{ Type string }
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			m.t.Errorf("bad line in session: %v", err)
+			return
+		}
+		switch msg.Type {
+		case "Begin":
+		case "Disconnect":
+			if wait(heard) {
+				conn.UnderlyingConn().Close()
+			}
+			return
+		case "Termination":
+			if !wait(terminate) {
+				return
+			}
+		default:
+			if !wait(heard) {
+				return
+			}
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+			return
+		}
+		if msg.Type == "Termination" {
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			break
+		}
+	}
+	<-closed
+}
+
+// streamer connects to the mock; it's used as App.newStreamer.
+func (m *mockAssembly) streamer(*Settings) (stt.Streamer, error) {
+	return &stt.AssemblyAI{URL: "ws" + strings.TrimPrefix(m.srv.URL, "http")}, nil
+}
+
+func (m *mockAssembly) connections() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.conns
+}
+
+// request returns the query and API key a connection was opened with.
+func (m *mockAssembly) request(conn int) (url.Values, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.queries[conn], m.keys[conn]
+}
+
+func (m *mockAssembly) receivedAudio(conn int) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]byte(nil), m.audio[conn]...)
+}
+
+func (m *mockAssembly) receivedTexts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.texts...)
+}
+
+// fakeCapture plays PCM to the app in 50 ms chunks, faster than real time,
+// then silence until it's closed, as a device would.
+type fakeCapture struct {
+	pcm  []byte
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newFakeCapture(pcm []byte) *fakeCapture {
+	return &fakeCapture{pcm: pcm, stop: make(chan struct{})}
+}
+
+func (c *fakeCapture) Start(source string, onPCM func(pcm []byte)) error {
+	const chunkBytes = audio.SampleRate * 2 / 20
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		silence := make([]byte, chunkBytes)
+		for offset := 0; ; offset += chunkBytes {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+			}
+			if offset < len(c.pcm) {
+				onPCM(c.pcm[offset:min(offset+chunkBytes, len(c.pcm))])
+			} else {
+				onPCM(silence)
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *fakeCapture) Close() {
+	close(c.stop)
+	c.wg.Wait()
+}
+
+// speech is a second of a tone, loud enough to count as speech.
+func speech() []byte {
+	var notes []audio.Note
+	for i := 0; i < 5; i++ {
+		notes = append(notes, audio.Note{Freq: 220, Duration: 200})
+	}
+	// Tones are rendered at ToneRate; resample to what's streamed
+	pcm := audio.Tones(notes)
+	return audio.NewResampler(audio.ToneRate, audio.SampleRate).Process(pcm)
+}
+
+// uiDriver runs fyne.Do calls in order on one goroutine, as the desktop
+// driver does on the main thread. The test driver runs them on the calling
+// goroutine, which races when they come from the app's background work.
+type uiDriver struct {
+	fyne.Driver
+	calls chan func()
+}
+
+func (d *uiDriver) DoFromGoroutine(fn func(), wait bool) {
+	if !wait {
+		d.calls <- fn
+		return
+	}
+	done := make(chan struct{})
+	d.calls <- func() {
+		defer close(done)
+		fn()
+	}
+	<-done
+}
+
+type uiApp struct {
+	fyne.App
+	driver *uiDriver
+}
+
+func (a *uiApp) Driver() fyne.Driver { return a.driver }
+
+// newUIApp returns a headless Fyne app with a UI goroutine.
+func newUIApp() fyne.App {
+	app := &uiApp{App: test.NewApp()}
+	app.driver = &uiDriver{Driver: app.App.Driver(), calls: make(chan func(), 1000)}
+	fyne.SetCurrentApp(app)
+	go func() {
+		for fn := range app.driver.calls {
+			fn()
+		}
+	}()
+	return app
+}
+
+// newTestApp starts the app headless, with its files in a temporary home,
+// streaming to server from a fake microphone. Use fyne.DoAndWait for
+// anything the user would do.
+func newTestApp(t *testing.T, server *mockAssembly) *App {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	fyneApp := newUIApp()
+	var a *App
+	fyne.DoAndWait(func() {
+		a = New(fyneApp, Options{})
+		a.newStreamer = server.streamer
+		a.newCapture = func() (audio.Capture, error) {
+			return newFakeCapture(speech()), nil
+		}
+		a.updateSettings(func(s *Settings) {
+			s.AssemblyAPIKey = "test-key"
+			s.SoundCues = false
+		})
+	})
+	return a
+}
+
+// waitFor polls until cond holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// turnTexts returns the text of each turn so far.
+func (a *App) turnTexts() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var texts []string
+	for _, turn := range a.turns {
+		texts = append(texts, turn.Text)
+	}
+	return texts
+}
+
+// stopAndWait stops recording and waits for the session to shut down.
+func (a *App) stopAndWait(t *testing.T) {
+	t.Helper()
+	var stopped chan struct{}
+	fyne.DoAndWait(func() {
+		a.stopRecording()
+		stopped = a.stopped
+	})
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for recording to stop")
+	}
+}
+
+// transcript returns the text area's text.
+func (a *App) transcript() string {
+	var text string
+	fyne.DoAndWait(func() { text = a.textArea.Text })
+	return text
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+	"dict/audio"
+	"dict/stt"
+)
+
+const (
+	pingInterval      = 5 * time.Second
+	pongTimeout       = 3 * pingInterval // No pong for this long counts as degraded
+	slowRoundTrip     = 500 * time.Millisecond
+	slowTranscription = 2 * time.Second // Audio sent but not yet transcribed
+	reconnectAttempts = 5
+)
+
+// Connection states, from best to worst.
+const (
+	healthConnected = iota
+	healthDegraded
+	healthReconnecting
+	healthDisconnected
+)
+
+// Health tracks a stream's connection to AssemblyAI. Round-trip time comes
+// from WebSocket pings; transcription delay compares the audio sent with the
+// end timestamp of the latest transcribed word.
+type Health struct {
+	mu       sync.Mutex
+	state    int
+	rtt      time.Duration
+	lastPong time.Time
+	delay    time.Duration
+
+	// Audio waiting to be sent, and audio dropped because the network
+	// couldn't keep up
+	backlog time.Duration
+	dropped time.Duration
+}
+
+func (h *Health) setState(state int) {
+	h.mu.Lock()
+	h.state = state
+	if state == healthConnected {
+		h.lastPong = time.Now()
+		h.delay = 0
+	}
+	h.mu.Unlock()
+}
+
+func (h *Health) pong(rtt time.Duration) {
+	h.mu.Lock()
+	h.rtt = rtt
+	h.lastPong = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *Health) setDelay(delay time.Duration) {
+	h.mu.Lock()
+	h.delay = max(delay, 0)
+	h.mu.Unlock()
+}
+
+func (h *Health) setBacklog(backlog, dropped time.Duration) {
+	h.mu.Lock()
+	h.backlog, h.dropped = backlog, dropped
+	h.mu.Unlock()
+}
+
+// HealthStatus is a stream's health at one moment.
+type HealthStatus struct {
+	state int
+	rtt   time.Duration
+	delay time.Duration
+	slow  string // What's degrading the connection: "network" or "service"
+
+	backlog time.Duration
+	dropped time.Duration
+}
+
+func (h *Health) status() HealthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := HealthStatus{state: h.state, rtt: h.rtt, delay: h.delay, backlog: h.backlog, dropped: h.dropped}
+	if s.state != healthConnected {
+		return s
+	}
+	switch {
+	case time.Since(h.lastPong) > pongTimeout || h.rtt > slowRoundTrip || h.backlog > slowBacklog:
+		s.state, s.slow = healthDegraded, "network"
+	case h.delay > slowTranscription:
+		s.state, s.slow = healthDegraded, "service"
+	}
+	return s
+}
+
+// watchConnection answers pongs on conn and pings it until it closes.
+func (a *App) watchConnection(st *Stream, conn stt.Conn) {
+	conn.OnPong(func(data string) {
+		if sent, err := strconv.ParseInt(data, 10, 64); err == nil {
+			rtt := time.Since(time.Unix(0, sent))
+			st.health.pong(rtt)
+			slog.Debug("pong", "stream", st.index, "rtt", rtt)
+		}
+	})
+
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			payload := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+			if err := conn.Ping(payload, time.Now().Add(pingInterval)); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// trackDelay records how far transcription lags behind the audio sent, using
+// the end of the last word in a turn message.
+func trackDelay(st *Stream, words []stt.Word) {
+	if len(words) == 0 {
+		return
+	}
+	sent := float64(st.sentBytes.Load()-st.connBytes) / (audio.SampleRate * 2)
+	transcribed := float64(words[len(words)-1].End) / 1000
+	st.health.setDelay(time.Duration((sent - transcribed) * float64(time.Second)))
+}
+
+// reconnectStream reopens a dropped connection with exponential backoff while
+// the session is still recording. Audio captured meanwhile is held, up to
+// maxPendingAudio, and sent once reconnected.
+func (a *App) reconnectStream(st *Stream) {
+	st.setConnection(nil)
+	st.health.setState(healthReconnecting)
+	a.mu.Lock()
+	delete(a.partialTexts, st.index)
+	a.mu.Unlock()
+	fyne.Do(a.updateHealth)
+
+	backoff := time.Second
+	var err error
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		time.Sleep(backoff)
+		if !a.recording.Load() || st.closing.Load() {
+			return
+		}
+		slog.Info("reconnecting websocket", "stream", st.index, "attempt", attempt)
+		err = a.connectStream(st)
+		if err == nil {
+			a.cue(cueReconnect)
+			fyne.Do(func() { a.updateStatus(tr("Recording... (reconnected)")) })
+			return
+		}
+		// Retrying won't fix a rejected key or a used-up quota
+		var auth *apierr.AuthError
+		var quota *apierr.QuotaError
+		if errors.As(err, &auth) || errors.As(err, &quota) {
+			break
+		}
+		backoff *= 2
+	}
+
+	slog.Error("giving up reconnecting", "stream", st.index, "err", err)
+	st.health.setState(healthDisconnected)
+	a.cue(cueError)
+	fyne.Do(func() {
+		a.updateStatus(tr("Connection lost — stop and start recording to retry"))
+		a.updateHealth()
+		a.showError(err)
+	})
+}
+
+func (a *App) newHealthLabel() fyne.CanvasObject {
+	a.healthLbl = widget.NewLabel("")
+	a.healthLbl.Hide()
+	return a.healthLbl
+}
+
+// updateHealth shows the worst connection state across the session's streams.
+func (a *App) updateHealth() {
+	if a.healthLbl == nil {
+		return
+	}
+	if !a.recording.Load() {
+		a.healthLbl.Hide()
+		return
+	}
+
+	a.mu.RLock()
+	var worst HealthStatus
+	for _, st := range a.streams {
+		s := st.health.status()
+		worst.rtt = max(worst.rtt, s.rtt)
+		worst.delay = max(worst.delay, s.delay)
+		worst.backlog = max(worst.backlog, s.backlog)
+		worst.dropped = max(worst.dropped, s.dropped)
+		if s.state > worst.state {
+			worst.state, worst.slow = s.state, s.slow
+		}
+	}
+	a.mu.RUnlock()
+
+	var text string
+	switch worst.state {
+	case healthConnected:
+		text = "● Connected"
+		a.healthLbl.Importance = widget.SuccessImportance
+	case healthDegraded:
+		text = "● Degraded (slow " + worst.slow + ")"
+		a.healthLbl.Importance = widget.WarningImportance
+	case healthReconnecting:
+		text = "● Reconnecting..."
+		a.healthLbl.Importance = widget.WarningImportance
+	default:
+		text = "● Disconnected"
+		a.healthLbl.Importance = widget.DangerImportance
+	}
+	if worst.state <= healthDegraded {
+		if worst.rtt > 0 {
+			text += fmt.Sprintf(" · %d ms", worst.rtt.Milliseconds())
+		}
+		if worst.delay > 0 {
+			text += fmt.Sprintf(" · %.1fs behind", worst.delay.Seconds())
+		}
+		if worst.backlog > slowBacklog {
+			text += fmt.Sprintf(" · %.1fs queued", worst.backlog.Seconds())
+		}
+	}
+	if worst.dropped > 0 {
+		text += fmt.Sprintf(" · %.1fs dropped", worst.dropped.Seconds())
+	}
+	a.healthLbl.SetText(text)
+	a.healthLbl.Show()
+}
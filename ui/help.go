@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const tooltipDelay = 600 * time.Millisecond
+
+// TipButton is a button that shows a tooltip after hovering for a moment.
+type TipButton struct {
+	widget.Button
+	Tip string
+
+	timer *time.Timer
+	popup *widget.PopUp
+}
+
+func newTipButton(label string, icon fyne.Resource, tip string, tapped func()) *TipButton {
+	b := &TipButton{Tip: tip}
+	b.Text = label
+	b.Icon = icon
+	b.OnTapped = tapped
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *TipButton) MouseIn(e *desktop.MouseEvent) {
+	b.Button.MouseIn(e)
+	if b.Tip == "" {
+		return
+	}
+	pos := e.AbsolutePosition.Add(fyne.NewPos(0, theme.Padding()*6))
+	b.timer = time.AfterFunc(tooltipDelay, func() {
+		fyne.Do(func() { b.showTip(pos) })
+	})
+}
+
+func (b *TipButton) MouseOut() {
+	b.Button.MouseOut()
+	b.hideTip()
+}
+
+func (b *TipButton) Tapped(e *fyne.PointEvent) {
+	b.hideTip()
+	b.Button.Tapped(e)
+}
+
+func (b *TipButton) showTip(pos fyne.Position) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(b)
+	if c == nil || b.timer == nil {
+		return
+	}
+	label := widget.NewLabel(b.Tip)
+	label.Wrapping = fyne.TextWrapWord
+	b.popup = widget.NewPopUp(container.NewGridWrap(fyne.NewSize(260, label.MinSize().Height), label), c)
+	b.popup.ShowAtPosition(pos)
+}
+
+func (b *TipButton) hideTip() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if b.popup != nil {
+		b.popup.Hide()
+		b.popup = nil
+	}
+}
+
+var helpPages = []struct {
+	title    string
+	markdown string
+}{
+	{trMark("Getting Started"), `# Getting Started
+
+1. Open **Settings** and paste your AssemblyAI API key, then **Save**.
+2. Press **Start Recording** (or Ctrl+R) and speak. Partial text appears in grey italics and settles as each turn finishes.
+3. Press **Stop Recording** when you're done. The text is yours to edit, copy or export.
+
+While recording, **Edit** lets you change the text safely: new turns are held back until you return to **Live**. If you prefer dictation-style input, set *New Turns* to *Insert at cursor* in Settings.
+
+**Process with LLM** rewrites the text with your system prompt (needs a Groq API key). **Pipeline** runs several prompts in a row, each on the previous one's output. **Undo** reverts the last rewrite.`},
+	{trMark("Shortcuts"), ""}, // Listed from the current bindings by shortcutsMarkdown
+	{trMark("Dictation Commands"), `# Dictation Commands
+
+- Say **"Heading: Budget"** (or *Section*, *Chapter*) on its own to add an outline heading.
+- Say **"Bookmark"** (optionally followed by a name) to mark a spot in the outline.
+- Add phrases under *Phrases to Remove* in Settings (e.g. "start listening") and they're stripped from the transcript, even when split across two turns.
+- Words under *Alert Keywords* raise a notification and are collected in the Alerts tab.
+
+## Command Mode
+
+Hold the dictation key (**F9** by default) and speak a command instead of dictating; it runs when you let go. Commands: *copy*, *clear*, *undo*, *process* (or *clean up*), *stop*, *edit* / *live*, *export* and *help*.
+
+Say the trigger of one of your *Text Snippets* (e.g. *insert signature*) to insert its stored text.
+
+With *Detect the spoken language* on, these commands switch to Spanish, French, German, Italian or Portuguese along with your speech, e.g. *copiar*, *effacer* or *rückgängig*.
+
+To edit hands-free: *go to end* / *start*, *go to end of line*, *move up two lines*, *move left three words*, *select last paragraph* (or sentence, or *select last five words*), *select this line*, *select all*, *deselect*, *delete that*, *new line* and *new paragraph*. With new turns inserted at the cursor, dictating over a selection replaces it.`},
+	{trMark("Providers"), `# Provider Setup
+
+**AssemblyAI** streams the transcription. Create a key at assemblyai.com and paste it under *AssemblyAI Settings*. Usage is billed per hour of audio; see the **Usage** panel for estimates.
+
+**Groq** (or any OpenAI-compatible endpoint) powers *Process with LLM* and lecture notes. Set the API key, model and endpoint under *Groq LLM Settings*.
+
+**Calendar**: an ICS URL or file lets the app offer to start transcribing when a meeting begins and tags the session with its title and attendees.
+
+**Live Output** appends each turn to a file, writes it to a named pipe or Unix socket, or POSTs it to a webhook, formatted with a Go template. **Export** templates live in the *templates* folder of the config directory.`},
+}
+
+// showHelp opens the help window, or focuses it if it's already open.
+func (a *App) showHelp() {
+	if a.helpWindow != nil {
+		a.helpWindow.RequestFocus()
+		return
+	}
+
+	tabs := container.NewAppTabs()
+	for _, page := range helpPages {
+		markdown := page.markdown
+		if page.title == "Shortcuts" {
+			markdown = a.shortcutsMarkdown()
+		}
+		text := widget.NewRichTextFromMarkdown(markdown)
+		text.Wrapping = fyne.TextWrapWord
+		tabs.Append(container.NewTabItem(tr(page.title), container.NewVScroll(text)))
+	}
+	tabs.SetTabLocation(container.TabLocationLeading)
+
+	a.helpWindow = a.fyneApp.NewWindow(tr("Voice Typing Help"))
+	a.helpWindow.SetContent(tabs)
+	a.helpWindow.Resize(fyne.NewSize(640, 460))
+	a.helpWindow.SetOnClosed(func() { a.helpWindow = nil })
+	a.helpWindow.Show()
+}
+
+// First-use hints, each shown once and remembered in the config.
+const (
+	hintWelcome   = "welcome"
+	hintFirstStop = "first-stop"
+	hintEditMode  = "edit-mode"
+)
+
+var hintMessages = map[string]string{
+	hintWelcome:   trMark("Welcome! Add your AssemblyAI API key in Settings, then press Start Recording (Ctrl+R). Hover over any button for a description, or press F1 for help."),
+	hintFirstStop: trMark("Your transcript is editable now. Copy it, Export it to a file, or Process it with an LLM to clean it up."),
+	hintEditMode:  trMark("New turns are held while you edit. Press Live to add them to the end of the text."),
+}
+
+// showHintOnce shows a first-use hint unless it has been shown before.
+func (a *App) showHintOnce(id string) {
+	seen := strings.Split(a.settings().SeenHints, ",")
+	if slices.Contains(seen, id) {
+		return
+	}
+	a.updateSettings(func(s *Settings) {
+		s.SeenHints = strings.Trim(s.SeenHints+","+id, ",")
+	})
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		a.updateStatus(tr("Failed to save settings: %s", err))
+	}
+
+	dialog.ShowInformation(tr("Tip"), tr(hintMessages[id]), a.window)
+}
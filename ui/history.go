@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const revisionTranscript = "Transcript"
+
+// HistorySession is a finished recording session saved to the history
+// folder, one JSON file each.
+type HistorySession struct {
+	ID        string        `json:"id"`
+	Title     string        `json:"title,omitempty"`
+	Attendees []string      `json:"attendees,omitempty"`
+	Started   time.Time     `json:"started"`
+	Ended     time.Time     `json:"ended"`
+	Turns     []HistoryTurn `json:"turns"`
+	Revisions []Revision    `json:"revisions"` // Oldest first; the first is the transcript
+}
+
+// HistoryTurn is a finalized turn as it was transcribed.
+type HistoryTurn struct {
+	Speaker string    `json:"speaker,omitempty"`
+	Text    string    `json:"text"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+
+	Audio      string        `json:"audio,omitempty"` // Session recording
+	AudioStart time.Duration `json:"audio_start,omitempty"`
+	AudioEnd   time.Duration `json:"audio_end,omitempty"`
+}
+
+// Revision is a version of a session's text. Reprocessing adds revisions
+// rather than overwriting the original.
+type Revision struct {
+	Created time.Time `json:"created"`
+	Source  string    `json:"source"` // revisionTranscript, or the prompt or pipeline that wrote it
+	Text    string    `json:"text"`
+}
+
+func (h *HistorySession) latest() Revision {
+	return h.Revisions[len(h.Revisions)-1]
+}
+
+// turns returns the session's turns as they were transcribed.
+func (h *HistorySession) turns() []Turn {
+	turns := make([]Turn, len(h.Turns))
+	for i, t := range h.Turns {
+		turns[i] = Turn{Order: i, Speaker: t.Speaker, Text: t.Text, Start: t.Start, End: t.End, Audio: t.Audio, AudioStart: t.AudioStart, AudioEnd: t.AudioEnd}
+	}
+	return turns
+}
+
+func (h *HistorySession) label() string {
+	title := h.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	return fmt.Sprintf("%s — %s (%d words, %d revisions)", h.Started.Format("2 Jan 2006 15:04"), title, countWords(h.latest().Text), len(h.Revisions))
+}
+
+// historyIDLayout names a session after when it started.
+const historyIDLayout = "20060102-150405"
+
+// validHistoryID reports whether id can name a session's file, so sessions
+// can't be written outside the history folder.
+func validHistoryID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`) && filepath.Base(id) == id
+}
+
+func (a *App) getHistoryDir() string {
+	return filepath.Join(a.getConfigDir(), "history")
+}
+
+// saveHistorySession writes a session via a temporary file, so a crash
+// mid-write can't corrupt it.
+func (a *App) saveHistorySession(h *HistorySession) error {
+	if !validHistoryID(h.ID) {
+		return fmt.Errorf("invalid session ID %q", h.ID)
+	}
+	dir := a.getHistoryDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create history folder: %v", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %v", err)
+	}
+	path := filepath.Join(dir, h.ID+".json")
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// loadHistory reads every saved session, newest first. Unreadable files are
+// skipped.
+func (a *App) loadHistory() ([]*HistorySession, error) {
+	paths, err := filepath.Glob(filepath.Join(a.getHistoryDir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var sessions []*HistorySession
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("failed to read history session", "path", path, "err", err)
+			continue
+		}
+		var h HistorySession
+		if err := json.Unmarshal(data, &h); err != nil || len(h.Revisions) == 0 {
+			slog.Warn("skipping invalid history session", "path", path, "err", err)
+			continue
+		}
+		sessions = append(sessions, &h)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Started.After(sessions[j].Started) })
+	return sessions, nil
+}
+
+// recordHistory saves a session's turns once recording stops.
+func (a *App) recordHistory(session int) {
+	a.mu.RLock()
+	h := &HistorySession{Title: a.sessionTitle, Attendees: a.sessionAttendees}
+	var turns []Turn
+	for _, turn := range a.turns {
+		if turn.Session != session || turn.Text == "" {
+			continue
+		}
+		if h.Started.IsZero() {
+			h.Started = turn.Start
+		}
+		h.Ended = turn.End
+		h.Turns = append(h.Turns, HistoryTurn{Speaker: turn.Speaker, Text: turn.Text, Start: turn.Start, End: turn.End, Audio: turn.Audio, AudioStart: turn.AudioStart, AudioEnd: turn.AudioEnd})
+		turns = append(turns, turn)
+	}
+	a.mu.RUnlock()
+	if len(h.Turns) == 0 {
+		return
+	}
+	h.ID = h.Started.Format(historyIDLayout)
+	h.Revisions = []Revision{{Created: time.Now(), Source: revisionTranscript, Text: strings.Join(joinTurns(a.settings(), "", nil, turns), "")}}
+	if err := a.saveHistorySession(h); err != nil {
+		slog.Error("failed to save session to history", "err", err)
+		return
+	}
+	a.document = h
+	slog.Info("session saved to history", "id", h.ID, "turns", len(h.Turns))
+}
+
+// showHistory lists saved sessions with a preview of the latest revision.
+// Sessions can be opened into the transcript, deleted, or reprocessed in
+// bulk.
+func (a *App) showHistory() {
+	sessions, err := a.loadHistory()
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+
+	checked := make(map[string]bool)
+	var current *HistorySession
+	preview := widget.NewLabel(tr("Select a session to preview it."))
+	preview.Wrapping = fyne.TextWrapWord
+
+	list := widget.NewList(
+		func() int { return len(sessions) },
+		func() fyne.CanvasObject { return widget.NewCheck("", nil) },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			h := sessions[id]
+			check := obj.(*widget.Check)
+			check.OnChanged = nil
+			check.Text = h.label()
+			check.SetChecked(checked[h.ID])
+			check.OnChanged = func(on bool) { checked[h.ID] = on }
+		},
+	)
+	showPreview := func() {
+		rev := current.latest()
+		preview.SetText(fmt.Sprintf("%s (%s)\n\n%s", rev.Source, rev.Created.Format("2 Jan 2006 15:04"), rev.Text))
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		current = sessions[id]
+		showPreview()
+	}
+
+	var d dialog.Dialog
+	openBtn := widget.NewButtonWithIcon(tr("Open"), theme.FolderOpenIcon(), func() {
+		if current == nil {
+			return
+		}
+		a.previousText = a.textArea.Text
+		a.textArea.SetText(current.latest().Text)
+		a.undoBtn.Enable()
+		a.document = current
+		if current.Title != "" {
+			a.activeTab.item.Text = current.Title
+			a.docTabs.Refresh()
+		}
+		d.Hide()
+		a.updateStatus(tr("Opened session from %s", current.Started.Format("2 Jan 2006 15:04")))
+	})
+	turnsBtn := widget.NewButtonWithIcon(tr("Turns"), theme.ListIcon(), func() {
+		if current == nil {
+			return
+		}
+		doc := ExportDocument{Title: current.Title, Attendees: current.Attendees, Locale: a.settings().Locale}
+		a.showTurnPicker(current.turns(), doc, nil)
+	})
+	deleteBtn := widget.NewButtonWithIcon(tr("Delete"), theme.DeleteIcon(), func() {
+		if current == nil {
+			return
+		}
+		h := current
+		dialog.ShowConfirm(tr("Delete Session"), tr("Delete the session from %s and all its revisions?", h.Started.Format("2 Jan 2006 15:04")), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := os.Remove(filepath.Join(a.getHistoryDir(), h.ID+".json")); err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+			for i, s := range sessions {
+				if s == h {
+					sessions = append(sessions[:i], sessions[i+1:]...)
+					break
+				}
+			}
+			current = nil
+			list.UnselectAll()
+			list.Refresh()
+			preview.SetText("")
+		}, a.window)
+	})
+	reprocessBtn := widget.NewButtonWithIcon(tr("Reprocess Checked..."), theme.MediaFastForwardIcon(), func() {
+		var selected []*HistorySession
+		for _, h := range sessions {
+			if checked[h.ID] {
+				selected = append(selected, h)
+			}
+		}
+		if len(selected) == 0 {
+			dialog.ShowInformation(tr("Reprocess"), tr("Check the sessions to reprocess first."), a.window)
+			return
+		}
+		a.showBulkReprocess(selected, list.Refresh)
+	})
+	revisionsBtn := widget.NewButtonWithIcon(tr("Revisions"), theme.HistoryIcon(), func() {
+		if current == nil {
+			return
+		}
+		a.showRevisionBrowser(current, func() {
+			list.Refresh()
+			showPreview()
+		})
+	})
+	folderBtn := widget.NewButtonWithIcon(tr("Open Folder"), theme.FolderIcon(), func() {
+		if err := a.openFolder(a.getHistoryDir()); err != nil {
+			dialog.ShowError(err, a.window)
+		}
+	})
+
+	if len(sessions) == 0 {
+		preview.SetText(tr("Sessions are saved here when you stop recording."))
+	}
+	storageBtn := widget.NewButtonWithIcon(tr("Storage"), theme.StorageIcon(), a.showStorage)
+	backupBtn := widget.NewButtonWithIcon(tr("Backup"), theme.UploadIcon(), func() {
+		a.showBackup(func() {
+			if reloaded, err := a.loadHistory(); err == nil {
+				sessions = reloaded
+				current = nil
+				list.UnselectAll()
+				list.Refresh()
+				preview.SetText("")
+			}
+		})
+	})
+	buttons := container.NewHBox(openBtn, revisionsBtn, turnsBtn, deleteBtn, reprocessBtn, folderBtn, storageBtn, backupBtn)
+	split := container.NewVSplit(list, container.NewVScroll(preview))
+	d = dialog.NewCustom(tr("History"), tr("Close"), container.NewBorder(nil, buttons, nil, nil, split), a.window)
+	d.Resize(fyne.NewSize(720, 560))
+	d.Show()
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	holdDelay    = 400 * time.Millisecond  // Longer presses are holds
+	commandGrace = 1500 * time.Millisecond // Wait after release for the last words of a command
+)
+
+// TranscriptEntry is the transcript text area. It passes key presses to
+// onKeyDown/onKeyUp and shortcuts to onShortcut first, so the dictation key
+// and actions work while it has focus.
+type TranscriptEntry struct {
+	widget.Entry
+	onKeyDown  func(*fyne.KeyEvent) bool // Returns true if the key was handled
+	onKeyUp    func(*fyne.KeyEvent) bool
+	onShortcut func(fyne.Shortcut) bool
+}
+
+func newTranscriptEntry() *TranscriptEntry {
+	e := &TranscriptEntry{}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrapWord
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// AcceptsTab leaves Tab to move focus, as it does everywhere else.
+func (e *TranscriptEntry) AcceptsTab() bool {
+	return false
+}
+
+func (e *TranscriptEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+func (e *TranscriptEntry) KeyDown(key *fyne.KeyEvent) {
+	if e.onKeyDown != nil && e.onKeyDown(key) {
+		return
+	}
+	e.Entry.KeyDown(key)
+}
+
+func (e *TranscriptEntry) KeyUp(key *fyne.KeyEvent) {
+	if e.onKeyUp != nil && e.onKeyUp(key) {
+		return
+	}
+	e.Entry.KeyUp(key)
+}
+
+// Hotkey implements the dictation key: a tap toggles recording, and holding
+// it switches to command mode, where speech runs voice commands instead of
+// being transcribed, until shortly after the key is released.
+type Hotkey struct {
+	holdTimer     *time.Timer
+	holding       bool
+	startedByHold bool // Recording was started by the hold and stops after it
+}
+
+func (a *App) setupDictationKey() {
+	a.textArea.onKeyDown = a.shortcutKeyDown
+	a.textArea.onKeyUp = a.dictationKeyUp
+	if c, ok := a.window.Canvas().(desktop.Canvas); ok {
+		c.SetOnKeyDown(func(key *fyne.KeyEvent) { a.shortcutKeyDown(key) })
+		c.SetOnKeyUp(func(key *fyne.KeyEvent) { a.dictationKeyUp(key) })
+	}
+}
+
+// dictationKey is the function key bound to dictation, if any.
+func (a *App) dictationKey() fyne.KeyName {
+	return a.settings().binding(dictationAction).Key
+}
+
+func (a *App) dictationKeyDown(key *fyne.KeyEvent) bool {
+	if key.Name != a.dictationKey() || key.Name == "" {
+		return false
+	}
+	if a.hotkey.holdTimer != nil {
+		return true
+	}
+	a.hotkey.holdTimer = time.AfterFunc(holdDelay, func() {
+		fyne.Do(a.startCommandMode)
+	})
+	return true
+}
+
+func (a *App) dictationKeyUp(key *fyne.KeyEvent) bool {
+	if key.Name != a.dictationKey() || key.Name == "" {
+		return false
+	}
+	if a.hotkey.holdTimer == nil {
+		return true
+	}
+	tapped := a.hotkey.holdTimer.Stop()
+	a.hotkey.holdTimer = nil
+	if tapped {
+		a.toggleRecording()
+	} else {
+		a.endCommandMode()
+	}
+	return true
+}
+
+func (a *App) startCommandMode() {
+	if a.hotkey.holdTimer == nil {
+		return
+	}
+	a.hotkey.holding = true
+	a.hotkey.startedByHold = !a.recording.Load()
+	if a.hotkey.startedByHold {
+		a.startRecording()
+	}
+	a.mu.Lock()
+	a.commandMode = true
+	a.mu.Unlock()
+	slog.Debug("command mode started")
+	a.updateStatus(tr("Command mode — speak a command"))
+}
+
+// endCommandMode leaves command mode after a grace period, since the end of
+// the command is still being transcribed when the key is released.
+func (a *App) endCommandMode() {
+	if !a.hotkey.holding {
+		return
+	}
+	a.hotkey.holding = false
+	stopAfter := a.hotkey.startedByHold
+	time.AfterFunc(commandGrace, func() {
+		fyne.Do(func() {
+			if a.hotkey.holding {
+				return // Held again during the grace period
+			}
+			a.mu.Lock()
+			a.commandMode = false
+			a.mu.Unlock()
+			slog.Debug("command mode ended")
+			if stopAfter {
+				a.stopRecording()
+			} else if a.recording.Load() {
+				a.updateStatus(tr("Recording..."))
+			}
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// uiLanguageSystem follows the system's language.
+const uiLanguageSystem = ""
+
+// uiLanguages are the languages the UI is shown in, by code, with their
+// names in that language. English is the source text and has no file.
+var uiLanguages = map[string]string{
+	"en": "English",
+	"es": "Español",
+	"de": "Deutsch",
+	"fr": "Français",
+}
+
+var uiLanguageOrder = []string{"en", "es", "de", "fr"}
+
+// Locale files translate UI strings from English, keyed by the English text
+// as passed to tr, verbs included.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// translations are the current UI language's strings; nil for English.
+var translations atomic.Pointer[map[string]string]
+
+// tr translates a UI string, then formats it as fmt.Sprintf does. Strings
+// the language doesn't have yet are shown in English.
+func tr(format string, args ...any) string {
+	if t := translations.Load(); t != nil {
+		if s := (*t)[format]; s != "" {
+			format = s
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// trMark marks a string to translate where it's defined, such as a label
+// in a table, for tr to translate where it's shown.
+func trMark(s string) string {
+	return s
+}
+
+// setUILanguage switches the UI language, by uiLanguages code or
+// uiLanguageSystem. Only what's built afterwards is translated, so the
+// main window needs a restart.
+func setUILanguage(code string) {
+	if code == uiLanguageSystem {
+		code, _, _ = strings.Cut(lang.SystemLocale().LanguageString(), "-")
+	}
+	if _, ok := uiLanguages[code]; !ok || code == "en" {
+		translations.Store(nil)
+		return
+	}
+	t, err := loadTranslations(code)
+	if err != nil {
+		slog.Warn("failed to load translations", "language", code, "err", err)
+		translations.Store(nil)
+		return
+	}
+	translations.Store(&t)
+}
+
+func loadTranslations(code string) (map[string]string, error) {
+	data, err := localeFiles.ReadFile("locales/" + code + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var t map[string]string
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid locales/%s.json: %v", code, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// uiStrings returns the strings the package passes to tr and trMark.
+func uiStrings(t *testing.T) map[string]bool {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || (fn.Name != "tr" && fn.Name != "trMark") {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("%s: %v", fset.Position(lit.Pos()), err)
+				}
+				keys[s] = true
+			}
+			return true
+		})
+	}
+	return keys
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestLocalesTranslateEveryString(t *testing.T) {
+	keys := uiStrings(t)
+	for code := range uiLanguages {
+		if code == "en" {
+			continue
+		}
+		translations, err := loadTranslations(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key := range keys {
+			s, ok := translations[key]
+			if !ok {
+				t.Errorf("%s: missing %q", code, key)
+				continue
+			}
+			if want, got := formatVerb.FindAllString(key, -1), formatVerb.FindAllString(s, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", code, s, got, want)
+			}
+		}
+		for key := range translations {
+			if !keys[key] {
+				t.Errorf("%s: %q is no longer used", code, key)
+			}
+		}
+	}
+}
+
+func TestTrFallsBackToEnglish(t *testing.T) {
+	defer translations.Store(nil)
+	setUILanguage("de")
+	if got := tr("Settings"); got != "Einstellungen" {
+		t.Errorf("tr(Settings) = %q in German", got)
+	}
+	if got := tr("Not translated %d", 3); got != "Not translated 3" {
+		t.Errorf("untranslated string = %q", got)
+	}
+	setUILanguage("en")
+	if got := tr("Settings"); got != "Settings" {
+		t.Errorf("tr(Settings) = %q in English", got)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Turn placement modes
+const (
+	turnPlacementAppend = "append"
+	turnPlacementCursor = "cursor"
+)
+
+// textClipboard feeds text to an Entry's paste handling, which inserts at the
+// caret (replacing any selection) and keeps the Entry's undo history intact.
+type textClipboard string
+
+func (c textClipboard) Content() string     { return string(c) }
+func (c textClipboard) SetContent(_ string) {}
+
+// insertAtCaret inserts a finalized turn where the user left the caret.
+func (a *App) insertAtCaret(text string) {
+	if text == "" {
+		return
+	}
+	// A caret at the start of a row follows a line break or a word wrap, so
+	// only mid-row insertions need separating from the previous word.
+	if a.textArea.CursorColumn > 0 {
+		text = " " + text
+	}
+	a.textArea.TypedShortcut(&fyne.ShortcutPaste{Clipboard: textClipboard(text)})
+}
+
+func (a *App) newPartialLabel() fyne.CanvasObject {
+	a.partialLbl = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	a.partialLbl.Wrapping = fyne.TextWrapWord
+	a.partialLbl.Importance = widget.LowImportance
+	a.partialLbl.Hide()
+	return a.partialLbl
+}
+
+func (a *App) setPartialLabel(text string) {
+	a.partialLbl.SetText(text)
+	if text == "" {
+		a.partialLbl.Hide()
+	} else {
+		a.partialLbl.Show()
+	}
+}
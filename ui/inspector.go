@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const inspectorSize = 500 // Recent messages kept for the inspector
+
+// Fields whose values are replaced before a message is shown.
+var secretFields = []string{"key", "token", "secret", "authorization", "password"}
+
+// ProtocolMessage is one message sent or received on a streaming WebSocket.
+type ProtocolMessage struct {
+	Time     time.Time
+	Stream   int
+	Outgoing bool
+	Type     string
+	Raw      string // Redacted JSON
+}
+
+func (m ProtocolMessage) String() string {
+	arrow := "←"
+	if m.Outgoing {
+		arrow = "→"
+	}
+	return fmt.Sprintf("%s %s #%d %s", m.Time.Format("15:04:05.000"), arrow, m.Stream, m.Raw)
+}
+
+// Inspector keeps the most recent protocol messages for the inspector panel.
+type Inspector struct {
+	mu       sync.Mutex
+	messages []ProtocolMessage
+	onChange func()
+}
+
+// record adds a raw JSON message. Audio frames aren't recorded.
+func (in *Inspector) record(stream int, outgoing bool, raw []byte) {
+	msg := ProtocolMessage{Time: time.Now(), Stream: stream, Outgoing: outgoing}
+	msg.Type, msg.Raw = redactMessage(raw)
+
+	in.mu.Lock()
+	in.messages = append(in.messages, msg)
+	if len(in.messages) > inspectorSize {
+		in.messages = append([]ProtocolMessage(nil), in.messages[len(in.messages)-inspectorSize:]...)
+	}
+	onChange := in.onChange
+	in.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+}
+
+func (in *Inspector) snapshot() []ProtocolMessage {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return append([]ProtocolMessage(nil), in.messages...)
+}
+
+func (in *Inspector) clear() {
+	in.mu.Lock()
+	in.messages = nil
+	in.mu.Unlock()
+}
+
+func (in *Inspector) setOnChange(f func()) {
+	in.mu.Lock()
+	in.onChange = f
+	in.mu.Unlock()
+}
+
+// redactMessage returns the message type and the message with secret fields
+// replaced. Messages that aren't JSON objects are returned as they are.
+func redactMessage(raw []byte) (string, string) {
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", string(raw)
+	}
+	redactFields(fields)
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", string(raw)
+	}
+	msgType, _ := fields["type"].(string)
+	return msgType, string(data)
+}
+
+func redactFields(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, value := range v {
+			lower := strings.ToLower(name)
+			if slices.ContainsFunc(secretFields, func(s string) bool { return strings.Contains(lower, s) }) {
+				v[name] = "[redacted]"
+			} else {
+				redactFields(value)
+			}
+		}
+	case []any:
+		for _, value := range v {
+			redactFields(value)
+		}
+	}
+}
+
+// showInspector opens the protocol inspector, which follows new messages
+// while open.
+func (a *App) showInspector() {
+	if a.inspectorWindow != nil {
+		a.inspectorWindow.RequestFocus()
+		return
+	}
+
+	messageText := newTextArea()
+	messageText.Wrapping = fyne.TextWrapOff
+	messageText.TextStyle = fyne.TextStyle{Monospace: true}
+	scroll := container.NewScroll(messageText)
+
+	const allTypes = "All types"
+	typeSelect := widget.NewSelect([]string{allTypes}, nil)
+	typeSelect.SetSelected(allTypes)
+	paused := widget.NewCheck(tr("Pause"), nil)
+
+	refresh := func() {
+		if paused.Checked {
+			return
+		}
+		types := []string{allTypes}
+		var shown []string
+		for _, msg := range a.inspector.snapshot() {
+			if msg.Type != "" && !slices.Contains(types, msg.Type) {
+				types = append(types, msg.Type)
+			}
+			if typeSelect.Selected == allTypes || msg.Type == typeSelect.Selected {
+				shown = append(shown, msg.String())
+			}
+		}
+		typeSelect.SetOptions(types)
+		messageText.SetText(strings.Join(shown, "\n"))
+		scroll.ScrollToBottom()
+	}
+	typeSelect.OnChanged = func(string) { refresh() }
+	paused.OnChanged = func(bool) { refresh() }
+
+	copyBtn := widget.NewButtonWithIcon(tr("Copy"), theme.ContentCopyIcon(), func() {
+		a.window.Clipboard().SetContent(messageText.Text)
+	})
+	clearBtn := widget.NewButtonWithIcon(tr("Clear"), theme.DeleteIcon(), func() {
+		a.inspector.clear()
+		refresh()
+	})
+	saveBtn := widget.NewButtonWithIcon(tr("Save Log..."), theme.DocumentSaveIcon(), a.saveMessageLog)
+
+	toolbar := container.NewBorder(nil, nil, container.NewHBox(widget.NewLabel(tr("Type:")), typeSelect, paused), container.NewHBox(copyBtn, clearBtn, saveBtn))
+
+	a.inspectorWindow = a.fyneApp.NewWindow(tr("Protocol Inspector"))
+	a.inspectorWindow.SetContent(container.NewBorder(toolbar, nil, nil, nil, scroll))
+	a.inspectorWindow.Resize(fyne.NewSize(900, 500))
+	a.inspectorWindow.SetOnClosed(func() {
+		a.inspector.setOnChange(nil)
+		a.inspectorWindow = nil
+	})
+	var pending atomic.Bool
+	a.inspector.setOnChange(func() {
+		if pending.CompareAndSwap(false, true) {
+			time.AfterFunc(250*time.Millisecond, func() {
+				pending.Store(false)
+				fyne.Do(refresh)
+			})
+		}
+	})
+	refresh()
+	a.inspectorWindow.Show()
+}
+
+// messageLog returns the messages received on the first stream, one per line,
+// for replaying.
+func messageLog(messages []ProtocolMessage) []byte {
+	var log []byte
+	for _, msg := range messages {
+		if !msg.Outgoing && msg.Stream == 0 {
+			log = append(log, msg.Raw+"\n"...)
+		}
+	}
+	return log
+}
+
+// saveMessageLog saves the messages received so far as a log that can be
+// replayed in place of AssemblyAI.
+func (a *App) saveMessageLog() {
+	log := messageLog(a.inspector.snapshot())
+	if len(log) == 0 {
+		a.updateStatus(tr("No messages received to save"))
+		return
+	}
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write(log); err != nil {
+			a.showError(fmt.Errorf("failed to save message log: %v", err))
+			return
+		}
+		a.updateStatus(tr("Saved message log to %s", writer.URI().Name()))
+	}, a.window)
+	save.SetFileName("voice-typing-session-" + time.Now().Format("2006-01-02-150405") + ".jsonl")
+	save.Show()
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"dict/config"
+)
+
+// Commands a later launch forwards to the running instance
+const (
+	instanceShow   = "show"
+	instanceToggle = "toggle"
+)
+
+// ErrRunning is returned by ClaimInstance when another instance is running.
+// It has been sent the launch's command.
+var ErrRunning = errors.New("voice typing is already running")
+
+// Instance makes this process the one app running on the config directory,
+// so two windows don't fight over the microphone. Later launches find it by
+// its lock file and forward their command over its socket.
+type Instance struct {
+	lock     *os.File
+	listener net.Listener
+}
+
+func instancePaths() (lock, socket string) {
+	dir := config.Dir()
+	return filepath.Join(dir, "instance.lock"), filepath.Join(dir, "instance.sock")
+}
+
+// ClaimInstance makes this process the running instance. If another one is
+// running, it forwards the options' command to it and returns ErrRunning.
+func ClaimInstance(opts Options) (*Instance, error) {
+	lockPath, socketPath := instancePaths()
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0700); err != nil {
+		return nil, err
+	}
+	lock, err := lockFile(lockPath)
+	if err != nil {
+		if err := sendInstanceCommand(socketPath, opts.instanceCommand()); err != nil {
+			return nil, fmt.Errorf("voice typing is already running but didn't respond: %v", err)
+		}
+		return nil, ErrRunning
+	}
+
+	// A socket left behind by an instance that crashed
+	os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		slog.Warn("later launches can't reach this instance", "err", err)
+	}
+	return &Instance{lock: lock, listener: listener}, nil
+}
+
+// Close releases the instance for the next launch.
+func (inst *Instance) Close() {
+	if inst.listener != nil {
+		inst.listener.Close()
+	}
+	inst.lock.Close()
+}
+
+// serve runs handle for each command forwarded by a later launch, replying
+// with its error, until the instance is closed.
+func (inst *Instance) serve(handle func(command string) error) {
+	if inst.listener == nil {
+		return
+	}
+	for {
+		conn, err := inst.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("stopped listening for later launches", "err", err)
+			}
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			command, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			command = strings.TrimSpace(command)
+			slog.Info("command from another launch", "command", command)
+			reply := "ok"
+			if err := handle(command); err != nil {
+				reply = err.Error()
+			}
+			fmt.Fprintln(conn, reply)
+		}()
+	}
+}
+
+// sendInstanceCommand forwards command to the running instance. It retries
+// for a few seconds, as the instance may still be starting up.
+func sendInstanceCommand(socketPath, command string) error {
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		conn, err := net.DialTimeout("unix", socketPath, time.Second)
+		if err != nil {
+			if time.Now().After(deadline) {
+				return err
+			}
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if _, err := fmt.Fprintln(conn, command); err != nil {
+			return err
+		}
+		reply, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if reply = strings.TrimSpace(reply); reply != "ok" {
+			return errors.New(reply)
+		}
+		return nil
+	}
+}
+
+// Serve carries out the commands later launches forward to inst.
+func (a *App) Serve(inst *Instance) {
+	go inst.serve(a.runInstanceCommand)
+}
+
+func (a *App) runInstanceCommand(command string) error {
+	switch command {
+	case instanceShow:
+		fyne.Do(func() {
+			a.window.Show()
+			a.window.RequestFocus()
+		})
+	case instanceToggle:
+		// Without focusing the window, so it works as a hotkey while
+		// dictating into another app
+		fyne.Do(a.toggleRecording)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLaterLaunchForwardsCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	inst, err := ClaimInstance(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := make(chan string, 1)
+	go inst.serve(func(command string) error {
+		commands <- command
+		return nil
+	})
+
+	if _, err := ClaimInstance(Options{Toggle: true}); !errors.Is(err, ErrRunning) {
+		t.Fatalf("second launch: err = %v, want ErrRunning", err)
+	}
+	if command := <-commands; command != instanceToggle {
+		t.Errorf("forwarded %q, want %q", command, instanceToggle)
+	}
+
+	// Once the first instance exits, the next launch takes over
+	inst.Close()
+	next, err := ClaimInstance(Options{})
+	if err != nil {
+		t.Fatalf("launch after exit: %v", err)
+	}
+	next.Close()
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+	"dict/llm"
+	"dict/stt"
+)
+
+const keyCheckTimeout = 15 * time.Second
+
+// keyCheckResult describes the outcome of testing an API key.
+func keyCheckResult(err error) string {
+	var auth *apierr.AuthError
+	var quota *apierr.QuotaError
+	var network *apierr.NetworkError
+	switch {
+	case err == nil:
+		return tr("Key works")
+	case errors.As(err, &auth):
+		return tr("Key rejected: check it was copied in full")
+	case errors.As(err, &quota):
+		return tr("Key works, but its quota is used up or it's rate limited")
+	case errors.As(err, &network):
+		return tr("Couldn't reach %s: check your connection and proxy", network.Service)
+	}
+	return tr("Failed: %s", err)
+}
+
+// newKeyTest adds a "Test" button to an API key field. check is run off the
+// UI thread with the key as entered, so it can be tested before saving.
+func (a *App) newKeyTest(service string, entry *widget.Entry, check func(ctx context.Context, key string) error) fyne.CanvasObject {
+	status := widget.NewLabel("")
+	status.Truncation = fyne.TextTruncateEllipsis
+	status.Hide()
+	var testBtn *widget.Button
+	testBtn = widget.NewButton(tr("Test"), func() {
+		key := strings.TrimSpace(entry.Text)
+		status.Show()
+		if key == "" {
+			status.SetText(tr("Enter a key first"))
+			return
+		}
+		testBtn.Disable()
+		status.SetText(tr("Testing..."))
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), keyCheckTimeout)
+			defer cancel()
+			err := check(ctx, key)
+			if err != nil {
+				slog.Warn("API key test failed", "service", service, "err", err)
+			} else {
+				slog.Info("API key test passed", "service", service)
+			}
+			fyne.Do(func() {
+				testBtn.Enable()
+				status.SetText(keyCheckResult(err))
+			})
+		}()
+	})
+	return container.NewBorder(nil, status, nil, testBtn, entry)
+}
+
+func (a *App) checkAssemblyKey(ctx context.Context, key string) error {
+	client, err := a.httpClient(keyCheckTimeout)
+	if err != nil {
+		return err
+	}
+	return (&stt.AssemblyAI{}).CheckKey(ctx, client, key)
+}
+
+// checkLLMKey lists the endpoint's models, which needs a valid key but
+// costs nothing.
+func (a *App) checkLLMKey(endpoint, key string) error {
+	client, err := a.httpClient(keyCheckTimeout)
+	if err != nil {
+		return err
+	}
+	_, err = (&llm.HTTPClient{Endpoint: endpoint, APIKey: key, HTTP: client}).Models()
+	return err
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// parseKeywords splits a comma or newline separated keyword list.
+func parseKeywords(text string) []string {
+	var keywords []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if keyword := strings.TrimSpace(field); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+func keywordPattern(keyword string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+}
+
+// matchKeywords returns the keywords that occur in text as whole words.
+func matchKeywords(text string, keywords []string) []string {
+	var matched []string
+	for _, keyword := range keywords {
+		if keywordPattern(keyword).MatchString(text) {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
+
+// checkKeywordAlerts returns the watch keywords mentioned in a finalized turn.
+// Each turn alerts at most once, even when a formatted version follows.
+// Caller must hold a.mu.
+func (a *App) checkKeywordAlerts(turn Turn) []string {
+	keywords := parseKeywords(a.settings().WatchKeywords)
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	key := [3]int{turn.Session, turn.Stream, turn.Order}
+	if a.alertedTurns[key] {
+		return nil
+	}
+	matched := matchKeywords(turn.Text, keywords)
+	if len(matched) > 0 {
+		if a.alertedTurns == nil {
+			a.alertedTurns = make(map[[3]int]bool)
+		}
+		a.alertedTurns[key] = true
+	}
+	return matched
+}
+
+func (a *App) raiseKeywordAlert(turn Turn, matched []string) {
+	slog.Info("keyword alert", "keywords", matched, "turn", turn.Order)
+
+	a.fyneApp.SendNotification(fyne.NewNotification(
+		"Keyword mentioned: "+strings.Join(matched, ", "),
+		turn.display(),
+	))
+	a.updateStatus(tr("Keyword mentioned: %s", strings.Join(matched, ", ")))
+
+	// Bold the keywords in the alert list
+	text := turn.display()
+	for _, keyword := range matched {
+		text = keywordPattern(keyword).ReplaceAllString(text, "**$0**")
+	}
+	entry := fmt.Sprintf("- %s — %s", time.Now().Format("15:04:05"), text)
+
+	if a.alertsTab == nil {
+		a.alertsText = widget.NewRichTextFromMarkdown("")
+		a.alertsText.Wrapping = fyne.TextWrapWord
+		a.alertsTab = container.NewTabItemWithIcon(tr("Alerts"), theme.WarningIcon(), container.NewScroll(a.alertsText))
+		a.tabs.Append(a.alertsTab)
+	}
+	a.alertsMarkdown += entry + "\n"
+	a.alertsText.ParseMarkdown(a.alertsMarkdown)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Languages the multilingual streaming model detects.
+var speechLanguages = map[string]string{
+	"en": "English", "es": "Spanish", "fr": "French", "de": "German", "it": "Italian", "pt": "Portuguese",
+}
+
+// Detections less confident than this don't switch the language, so a
+// borrowed word or a short "okay" doesn't flip the commands mid-sentence.
+const languageSwitchConfidence = 0.7
+
+// commandPhrases are the spoken commands in each language other than
+// English, keyed by the command's first English phrase.
+var commandPhrases = map[string]map[string][]string{
+	"es": {
+		"copy": {"copiar", "copia eso", "copiar todo"}, "clear": {"borrar", "borrar todo", "limpiar"},
+		"undo": {"deshacer", "deshaz eso"}, "process": {"procesar", "corregir", "arreglar texto"},
+		"stop": {"parar", "detener", "deja de escuchar"}, "edit": {"editar", "modo edición", "en vivo", "modo en vivo"},
+		"export": {"exportar"}, "help": {"ayuda", "mostrar ayuda"},
+	},
+	"fr": {
+		"copy": {"copier", "copie ça", "copier tout"}, "clear": {"effacer", "effacer tout", "tout effacer"},
+		"undo": {"annuler", "annule ça"}, "process": {"traiter", "corriger", "nettoyer"},
+		"stop": {"arrêter", "arrête", "arrête l'écoute"}, "edit": {"modifier", "mode édition", "direct", "mode direct"},
+		"export": {"exporter"}, "help": {"aide", "afficher l'aide"},
+	},
+	"de": {
+		"copy": {"kopieren", "kopiere das", "alles kopieren"}, "clear": {"löschen", "alles löschen", "leeren"},
+		"undo": {"rückgängig", "rückgängig machen"}, "process": {"verarbeiten", "aufräumen", "korrigieren"},
+		"stop": {"stopp", "aufnahme beenden", "hör auf"}, "edit": {"bearbeiten", "bearbeitungsmodus", "live", "livemodus"},
+		"export": {"exportieren"}, "help": {"hilfe", "hilfe anzeigen"},
+	},
+	"it": {
+		"copy": {"copia", "copia tutto"}, "clear": {"cancella", "cancella tutto", "pulisci"},
+		"undo": {"annulla"}, "process": {"elabora", "correggi", "sistema"},
+		"stop": {"ferma", "fermati", "smetti di ascoltare"}, "edit": {"modifica", "modalità modifica", "dal vivo"},
+		"export": {"esporta"}, "help": {"aiuto", "mostra aiuto"},
+	},
+	"pt": {
+		"copy": {"copiar", "copia isso", "copiar tudo"}, "clear": {"limpar", "limpar tudo", "apagar tudo"},
+		"undo": {"desfazer", "desfaz isso"}, "process": {"processar", "corrigir", "arrumar"},
+		"stop": {"parar", "pare", "para de ouvir"}, "edit": {"editar", "modo edição", "ao vivo"},
+		"export": {"exportar"}, "help": {"ajuda", "mostrar ajuda"},
+	},
+}
+
+// spokenPhrases returns the phrases for a command in the given language,
+// falling back to English for languages without their own.
+func (c *VoiceCommand) spokenPhrases(language string) []string {
+	if phrases, ok := commandPhrases[language][c.phrases[0]]; ok {
+		return phrases
+	}
+	return c.phrases
+}
+
+var (
+	frenchSpacePattern = regexp.MustCompile(`([\pL\pN»)])\s*([?!;:]+)(\s|$)`)
+	sentenceSplit      = regexp.MustCompile(`[^.!?¿¡]*[.!?]+`)
+)
+
+// localizePunctuation applies a language's punctuation conventions to a
+// formatted turn: a narrow no-break space before French ?!;: and opening
+// marks on Spanish questions and exclamations.
+func localizePunctuation(language, text string) string {
+	switch language {
+	case "fr":
+		return frenchSpacePattern.ReplaceAllString(text, "$1\u202f$2$3")
+	case "es":
+		return sentenceSplit.ReplaceAllStringFunc(text, func(sentence string) string {
+			trimmed := strings.TrimLeftFunc(sentence, unicode.IsSpace)
+			lead := sentence[:len(sentence)-len(trimmed)]
+			switch {
+			case strings.HasSuffix(trimmed, "?") && !strings.HasPrefix(trimmed, "¿"):
+				return lead + "¿" + trimmed
+			case strings.HasSuffix(trimmed, "!") && !strings.HasPrefix(trimmed, "¡"):
+				return lead + "¡" + trimmed
+			}
+			return sentence
+		})
+	}
+	return text
+}
+
+// detectLanguage switches the session language when the recognizer is
+// confident the speaker changed it, returning true if it did. Confidence is
+// zero when the recognizer doesn't report one. Caller must hold a.mu.
+func (a *App) detectLanguage(code string, confidence float64) bool {
+	if code == "" || code == a.language || (confidence > 0 && confidence < languageSwitchConfidence) {
+		return false
+	}
+	slog.Info("language switched", "from", a.language, "to", code, "confidence", confidence)
+	a.language = code
+	return true
+}
+
+// sessionLanguage returns the detected language, if any.
+func (a *App) sessionLanguage() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.language
+}
+
+// languageName returns the English name of a language code.
+func languageName(code string) string {
+	if name, ok := speechLanguages[code]; ok {
+		return name
+	}
+	return code
+}
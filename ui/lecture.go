@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+const lecturePrompt = `You are taking structured notes during a lecture. Summarize the following portion of the lecture transcript as concise Markdown notes: key points as bullets, definitions, formulas and examples. Continue on from the previous section without repeating it. Output only the notes.`
+
+const defaultLectureInterval = 10
+
+// Lecture tracks incremental note generation while a lecture is recorded.
+type Lecture struct {
+	summarized   int // Number of turns already covered by a section
+	part         int
+	sectionStart time.Time
+	lastSection  string
+	stop         chan struct{}
+}
+
+func (a *App) startLecture() {
+	cfg := a.settings()
+	if cfg.GroqAPIKey == "" {
+		slog.Warn("lecture mode enabled but no Groq API key configured")
+		fyne.Do(func() {
+			a.updateStatus(tr("Lecture mode needs a Groq API key in Settings"))
+		})
+		return
+	}
+
+	a.mu.Lock()
+	lec := &Lecture{
+		summarized:   len(a.turns),
+		sectionStart: time.Now(),
+		stop:         make(chan struct{}),
+	}
+	a.lecture = lec
+	a.mu.Unlock()
+
+	interval := time.Duration(max(cfg.LectureInterval, 1)) * time.Minute
+	slog.Info("lecture mode started", "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lec.stop:
+				return
+			case <-ticker.C:
+				a.summarizeLectureSection(lec)
+			}
+		}
+	}()
+}
+
+// stopLecture ends the periodic summaries and writes a final section for
+// whatever was said since the last one. Caller must hold a.mu.
+func (a *App) stopLecture() {
+	lec := a.lecture
+	if lec == nil {
+		return
+	}
+	a.lecture = nil
+	close(lec.stop)
+	go a.summarizeLectureSection(lec)
+}
+
+func (a *App) summarizeLectureSection(lec *Lecture) {
+	a.mu.Lock()
+	if lec.summarized > len(a.turns) {
+		// The transcript was cleared mid-lecture
+		lec.summarized = 0
+	}
+	if lec.summarized == len(a.turns) {
+		a.mu.Unlock()
+		return
+	}
+	var lines []string
+	for _, turn := range a.turns[lec.summarized:] {
+		lines = append(lines, turn.display())
+	}
+	lec.summarized = len(a.turns)
+	lec.part++
+	part := lec.part
+	start := lec.sectionStart
+	lec.sectionStart = time.Now()
+	previous := lec.lastSection
+	a.mu.Unlock()
+
+	input := strings.Join(lines, "\n")
+	if previous != "" {
+		input = "Previous section notes:\n" + previous + "\n\nNew transcript:\n" + input
+	}
+
+	fyne.Do(func() {
+		a.updateStatus(tr("Summarizing lecture part %d...", part))
+	})
+	ctx, cancel := a.llmContext(context.Background())
+	defer cancel()
+	notes, err := a.callGroqAPI(ctx, lecturePrompt, input)
+	if err != nil {
+		slog.Error("lecture summary failed", "err", err)
+		fyne.Do(func() {
+			a.updateStatus(tr("Lecture summary failed: %s", err))
+		})
+		return
+	}
+	notes = a.cleanLLMReply(notes, input)
+
+	a.mu.Lock()
+	lec.lastSection = notes
+	a.mu.Unlock()
+
+	section := fmt.Sprintf("## Part %d (%s–%s)\n\n%s\n", part,
+		start.Format("15:04"), time.Now().Format("15:04"), strings.TrimSpace(notes))
+	fyne.Do(func() {
+		a.appendLectureNotes(section)
+		a.updateStatus(tr("Lecture part %d summarized", part))
+	})
+}
+
+func (a *App) appendLectureNotes(section string) {
+	if a.notesTab == nil {
+		a.notesArea = newTextArea()
+		a.notesArea.Wrapping = fyne.TextWrapWord
+		a.notesTab = container.NewTabItemWithIcon(tr("Lecture Notes"), theme.DocumentIcon(), container.NewScroll(a.notesArea))
+		a.tabs.Append(a.notesTab)
+	}
+
+	text := a.notesArea.Text
+	if text != "" {
+		text += "\n"
+	}
+	a.notesArea.SetText(text + section)
+}
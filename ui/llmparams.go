@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+
+	"dict/config"
+	"dict/llm"
+)
+
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func parseOptionalFloat(name, text string, limit float64) (*float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || v < 0 || v > limit {
+		return nil, fmt.Errorf("%s must be a number from 0 to %g", name, limit)
+	}
+	return &v, nil
+}
+
+// llmParamsFromConfig reads the sampling parameters, leaving out any that
+// are out of range.
+func llmParamsFromConfig(c config.LLM) llm.Params {
+	var p llm.Params
+	if c.Temperature != nil && *c.Temperature >= 0 && *c.Temperature <= 2 {
+		p.Temperature = c.Temperature
+	}
+	if c.TopP != nil && *c.TopP >= 0 && *c.TopP <= 1 {
+		p.TopP = c.TopP
+	}
+	if c.MaxTokens > 0 {
+		p.MaxTokens = c.MaxTokens
+	}
+	return p
+}
+
+// newLLMParamsForm builds entries for the sampling parameters. read
+// validates and returns them; set fills the form, e.g. from a preset.
+func newLLMParamsForm(p llm.Params) (form *widget.Form, read func() (llm.Params, error), set func(llm.Params)) {
+	temperatureEntry := widget.NewEntry()
+	temperatureEntry.SetPlaceHolder(tr("default (0–2)"))
+	maxTokensEntry := widget.NewEntry()
+	maxTokensEntry.SetPlaceHolder(tr("default"))
+	topPEntry := widget.NewEntry()
+	topPEntry.SetPlaceHolder(tr("default (0–1)"))
+
+	set = func(p llm.Params) {
+		temperatureEntry.SetText(formatOptionalFloat(p.Temperature))
+		topPEntry.SetText(formatOptionalFloat(p.TopP))
+		maxTokensEntry.SetText("")
+		if p.MaxTokens > 0 {
+			maxTokensEntry.SetText(strconv.Itoa(p.MaxTokens))
+		}
+	}
+	set(p)
+
+	read = func() (llm.Params, error) {
+		var p llm.Params
+		var err error
+		if p.Temperature, err = parseOptionalFloat("Temperature", temperatureEntry.Text, 2); err != nil {
+			return p, err
+		}
+		if p.TopP, err = parseOptionalFloat("Top P", topPEntry.Text, 1); err != nil {
+			return p, err
+		}
+		if text := strings.TrimSpace(maxTokensEntry.Text); text != "" {
+			if p.MaxTokens, err = strconv.Atoi(text); err != nil || p.MaxTokens <= 0 {
+				return p, fmt.Errorf("Max tokens must be a positive whole number")
+			}
+		}
+		return p, nil
+	}
+
+	form = widget.NewForm(
+		widget.NewFormItem(tr("Temperature"), temperatureEntry),
+		widget.NewFormItem(tr("Max tokens"), maxTokensEntry),
+		widget.NewFormItem(tr("Top P"), topPEntry),
+	)
+	form.Items[0].HintText = "Low values (e.g. 0) give consistent grammar fixes"
+	form.Refresh()
+	return form, read, set
+}
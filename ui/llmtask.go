@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"dict/apierr"
+)
+
+const (
+	defaultLLMTimeout = 60 // Seconds
+	maxLLMTimeout     = 600
+)
+
+// llmContext returns a context bounded by the configured LLM timeout.
+func (a *App) llmContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, time.Duration(a.settings().LLMTimeout)*time.Second)
+}
+
+// startLLMTask starts an LLM call from the UI: it returns a context that the
+// Cancel button aborts, and a done function to call on the UI thread when
+// the call finishes. Call it on the UI thread.
+func (a *App) startLLMTask() (context.Context, func()) {
+	ctx, cancel := a.llmContext(context.Background())
+	return ctx, a.trackLLMTask(cancel)
+}
+
+// startBatchLLMTask is startLLMTask without the timeout, for jobs of many
+// calls that each get their own from llmContext.
+func (a *App) startBatchLLMTask() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return ctx, a.trackLLMTask(cancel)
+}
+
+func (a *App) trackLLMTask(cancel context.CancelFunc) func() {
+	if a.llmTasks == nil {
+		a.llmTasks = make(map[int]context.CancelFunc)
+	}
+	a.nextLLMTask++
+	id := a.nextLLMTask
+	a.llmTasks[id] = cancel
+	a.cancelBtn.Show()
+
+	return func() {
+		cancel()
+		delete(a.llmTasks, id)
+		if len(a.llmTasks) == 0 {
+			a.cancelBtn.Hide()
+		}
+	}
+}
+
+// cancelLLMTasks aborts every LLM call started from the UI.
+func (a *App) cancelLLMTasks() {
+	slog.Info("cancelling LLM requests", "count", len(a.llmTasks))
+	for _, cancel := range a.llmTasks {
+		cancel()
+	}
+}
+
+// llmError describes a failed LLM call, or returns nil if the user cancelled
+// it.
+func (a *App) llmError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return &apierr.NetworkError{Service: "the LLM endpoint", Err: fmt.Errorf("LLM request timed out after %ds", a.settings().LLMTimeout)}
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"dict/apierr"
+)
+
+func TestLLMTasks(t *testing.T) {
+	test.NewTempApp(t)
+	a := &App{}
+	cfg := defaultSettings()
+	cfg.LLMTimeout = 30
+	a.config.Store(cfg)
+	a.cancelBtn = newTipButton("Cancel", nil, "", a.cancelLLMTasks)
+	a.cancelBtn.Hide()
+
+	first, done1 := a.startLLMTask()
+	second, done2 := a.startLLMTask()
+	deadline, ok := first.Deadline()
+	if left := time.Until(deadline); !ok || left > 30*time.Second || left < 29*time.Second {
+		t.Errorf("deadline in %v, want 30s", left)
+	}
+	if !a.cancelBtn.Visible() {
+		t.Error("Cancel hidden while requests run")
+	}
+
+	done1()
+	if first.Err() == nil {
+		t.Error("finished request's context not cancelled")
+	}
+	if second.Err() != nil || !a.cancelBtn.Visible() {
+		t.Error("finishing one request affected the other")
+	}
+
+	a.cancelLLMTasks()
+	if !errors.Is(second.Err(), context.Canceled) {
+		t.Errorf("after Cancel, context error = %v, want %v", second.Err(), context.Canceled)
+	}
+	done2()
+	if a.cancelBtn.Visible() {
+		t.Error("Cancel still shown with no requests running")
+	}
+}
+
+func TestLLMError(t *testing.T) {
+	a := &App{}
+	cfg := defaultSettings()
+	cfg.LLMTimeout = 30
+	a.config.Store(cfg)
+
+	if err := a.llmError(fmt.Errorf("request failed: %w", context.Canceled)); err != nil {
+		t.Errorf("cancelled request gave error %v, want none", err)
+	}
+	var network *apierr.NetworkError
+	err := a.llmError(fmt.Errorf("request failed: %w", context.DeadlineExceeded))
+	if !errors.As(err, &network) || err.Error() != "LLM request timed out after 30s" {
+		t.Errorf("timed out request gave %T %v, want a network error saying it timed out after 30s", err, err)
+	}
+	other := errors.New("API returned status 500")
+	if err := a.llmError(other); err != other {
+		t.Errorf("llmError(%v) = %v, want it unchanged", other, err)
+	}
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// localeNames are the month and day names and date layouts for a language.
+// Layouts are Go time layouts; their month and day names are swapped for
+// the language's.
+type localeNames struct {
+	months    [12]string
+	days      [7]string // From Sunday, like time.Weekday
+	shortDate string
+	date      string
+	longDate  string
+	clock     string
+}
+
+var englishNames = localeNames{
+	months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	days:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	shortDate: "02/01/2006",
+	date:      "2 January 2006",
+	longDate:  "Monday, 2 January 2006",
+	clock:     "15:04",
+}
+
+// localeTable has the languages exports can be written in, by base
+// language, plus regions that write dates differently.
+var localeTable = map[string]localeNames{
+	"en": englishNames,
+	"en-US": {
+		months:    englishNames.months,
+		days:      englishNames.days,
+		shortDate: "1/2/2006",
+		date:      "January 2, 2006",
+		longDate:  "Monday, January 2, 2006",
+		clock:     "3:04 PM",
+	},
+	"de": {
+		months:    [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		days:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDate: "02.01.2006",
+		date:      "2. January 2006",
+		longDate:  "Monday, 2. January 2006",
+		clock:     "15:04",
+	},
+	"fr": {
+		months:    [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		days:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDate: "02/01/2006",
+		date:      "2 January 2006",
+		longDate:  "Monday 2 January 2006",
+		clock:     "15:04",
+	},
+	"es": {
+		months:    [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		days:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDate: "02/01/2006",
+		date:      "2 de January de 2006",
+		longDate:  "Monday, 2 de January de 2006",
+		clock:     "15:04",
+	},
+	"it": {
+		months:    [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		days:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDate: "02/01/2006",
+		date:      "2 January 2006",
+		longDate:  "Monday 2 January 2006",
+		clock:     "15:04",
+	},
+	"pt": {
+		months:    [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		days:      [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDate: "02/01/2006",
+		date:      "2 de January de 2006",
+		longDate:  "Monday, 2 de January de 2006",
+		clock:     "15:04",
+	},
+	"nl": {
+		months:    [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		days:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDate: "02-01-2006",
+		date:      "2 January 2006",
+		longDate:  "Monday 2 January 2006",
+		clock:     "15:04",
+	},
+}
+
+// Locale formats dates and numbers in exports and templates.
+type Locale struct {
+	tag     language.Tag
+	names   localeNames
+	printer *message.Printer
+}
+
+// parseLocale reads a BCP 47 tag such as "de-DE" or "en-US"; empty is
+// English with day-month dates, as exports have always been written.
+func parseLocale(tag string) (Locale, error) {
+	if strings.TrimSpace(tag) == "" {
+		return Locale{tag: language.English, names: englishNames, printer: message.NewPrinter(language.English)}, nil
+	}
+	t, err := language.Parse(strings.TrimSpace(tag))
+	if err != nil {
+		return Locale{}, fmt.Errorf("unknown locale %q: use a tag like en-US or de-DE", tag)
+	}
+	base, _ := t.Base()
+	region, _ := t.Region()
+	names, ok := localeTable[base.String()+"-"+region.String()]
+	if !ok {
+		names, ok = localeTable[base.String()]
+	}
+	if !ok {
+		// Numbers still follow the locale; names fall back to English
+		names = englishNames
+	}
+	return Locale{tag: t, names: names, printer: message.NewPrinter(t)}, nil
+}
+
+// localeFor is parseLocale for settings that were already validated.
+func localeFor(tag string) Locale {
+	l, err := parseLocale(tag)
+	if err != nil {
+		l, _ = parseLocale("")
+	}
+	return l
+}
+
+// format formats t with a Go layout, in the locale's month and day names.
+func (l Locale) format(t time.Time, layout string) string {
+	var b strings.Builder
+	for layout != "" {
+		// Find the next name in the layout; the rest formats as usual
+		next, name, length := len(layout), "", 0
+		for _, token := range []string{"January", "Monday", "Jan", "Mon"} {
+			if i := strings.Index(layout, token); i >= 0 && (i < next || i == next && len(token) > length) {
+				next, length = i, len(token)
+				switch token {
+				case "January":
+					name = l.names.months[t.Month()-1]
+				case "Jan":
+					name = abbreviate(l.names.months[t.Month()-1])
+				case "Monday":
+					name = l.names.days[t.Weekday()]
+				case "Mon":
+					name = abbreviate(l.names.days[t.Weekday()])
+				}
+			}
+		}
+		if next > 0 {
+			b.WriteString(t.Format(layout[:next]))
+		}
+		b.WriteString(name)
+		layout = layout[next+length:]
+	}
+	return b.String()
+}
+
+func abbreviate(name string) string {
+	runes := []rune(name)
+	return string(runes[:min(len(runes), 3)])
+}
+
+func (l Locale) shortDate(t time.Time) string { return l.format(t, l.names.shortDate) }
+func (l Locale) date(t time.Time) string      { return l.format(t, l.names.date) }
+func (l Locale) longDate(t time.Time) string  { return l.format(t, l.names.longDate) }
+func (l Locale) clock(t time.Time) string     { return l.format(t, l.names.clock) }
+
+// decimal formats a number with the locale's separators and a fixed number
+// of decimal places.
+func (l Locale) decimal(v any, places int) string {
+	switch v.(type) {
+	case float32, float64:
+		return l.printer.Sprintf("%.*f", places, v)
+	}
+	return l.printer.Sprintf("%d", v)
+}
+
+// funcs are the template functions that depend on the locale. templateFuncs
+// has them for English, so templates parse; a template is executed with the
+// ones for the configured locale.
+func (l Locale) funcs() template.FuncMap {
+	return template.FuncMap{
+		"shortDate":  l.shortDate,
+		"date":       l.date,
+		"longDate":   l.longDate,
+		"clock":      l.clock,
+		"formatTime": func(t time.Time, layout string) string { return l.format(t, layout) },
+		"number":     func(v any) string { return l.printer.Sprint(v) },
+		"decimal":    l.decimal,
+	}
+}
+
+func init() {
+	for name, f := range localeFor("").funcs() {
+		templateFuncs[name] = f
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleFormatting(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC) // A Tuesday
+	for _, tt := range []struct {
+		tag                              string
+		shortDate, date, longDate, clock string
+		number, decimal                  string
+	}{
+		{"", "05/03/2024", "5 March 2024", "Tuesday, 5 March 2024", "14:07", "1,234,567", "1,234.50"},
+		{"en-US", "3/5/2024", "March 5, 2024", "Tuesday, March 5, 2024", "2:07 PM", "1,234,567", "1,234.50"},
+		{"en-GB", "05/03/2024", "5 March 2024", "Tuesday, 5 March 2024", "14:07", "1,234,567", "1,234.50"},
+		{"de-DE", "05.03.2024", "5. März 2024", "Dienstag, 5. März 2024", "14:07", "1.234.567", "1.234,50"},
+		{"fr", "05/03/2024", "5 mars 2024", "mardi 5 mars 2024", "14:07", "1\u00a0234\u00a0567", "1\u00a0234,50"},
+		{"es-ES", "05/03/2024", "5 de marzo de 2024", "martes, 5 de marzo de 2024", "14:07", "1.234.567", "1.234,50"},
+		// Unknown languages keep English names
+		{"sv", "05/03/2024", "5 March 2024", "Tuesday, 5 March 2024", "14:07", "1\u00a0234\u00a0567", "1\u00a0234,50"},
+	} {
+		l, err := parseLocale(tt.tag)
+		if err != nil {
+			t.Fatalf("parseLocale(%q): %v", tt.tag, err)
+		}
+		for _, got := range []struct{ what, got, want string }{
+			{"shortDate", l.shortDate(at), tt.shortDate},
+			{"date", l.date(at), tt.date},
+			{"longDate", l.longDate(at), tt.longDate},
+			{"clock", l.clock(at), tt.clock},
+			{"number", l.printer.Sprint(1234567), tt.number},
+			{"decimal", l.decimal(1234.5, 2), tt.decimal},
+		} {
+			if got.got != got.want {
+				t.Errorf("%q %s = %q, want %q", tt.tag, got.what, got.got, got.want)
+			}
+		}
+	}
+}
+
+func TestLocaleAbbreviations(t *testing.T) {
+	l := localeFor("de")
+	at := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	if got, want := l.format(at, "Mon 2 Jan"), "Die 5 Mär"; got != want {
+		t.Errorf("format = %q, want %q", got, want)
+	}
+}
+
+func TestParseLocaleErrors(t *testing.T) {
+	if _, err := parseLocale("not a locale!"); err == nil {
+		t.Error("parseLocale accepted an invalid tag")
+	}
+	if l := localeFor("not a locale!"); l.names.date != englishNames.date {
+		t.Error("localeFor didn't fall back to English for an invalid tag")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package ui
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile opens path and takes an exclusive lock on it, held until the file
+// is closed or the process exits. It fails if another process holds it.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile opens path without sharing it, so it stays locked until the file
+// is closed or the process exits. It fails if another process has it open.
+func lockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	logFileName     = "voice-typing.log"
+	logMaxSize      = 5 << 20 // Bytes before the log file is rotated
+	logMaxBackups   = 3
+	logRingSize     = 1000 // Recent lines kept for the Logs panel
+	defaultLogLevel = "info"
+)
+
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// RotatingFile is an io.Writer that starts a new file once the current one
+// reaches maxSize, keeping maxBackups old files (name.1 is the newest).
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %v", err)
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat log file: %v", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	r.file.Close()
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %v", err)
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size+int64(len(p)) > r.maxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// LogRing keeps the most recent log lines for the Logs panel.
+type LogRing struct {
+	mu       sync.Mutex
+	lines    []string
+	onChange func()
+}
+
+func (r *LogRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines = append(r.lines, line)
+	}
+	if len(r.lines) > logRingSize {
+		r.lines = append([]string(nil), r.lines[len(r.lines)-logRingSize:]...)
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+	return len(p), nil
+}
+
+func (r *LogRing) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+func (r *LogRing) setOnChange(f func()) {
+	r.mu.Lock()
+	r.onChange = f
+	r.mu.Unlock()
+}
+
+func (a *App) getLogPath() string {
+	return filepath.Join(a.getConfigDir(), "logs", logFileName)
+}
+
+// setupLogging sends slog (and the standard log package) to stderr, a
+// rotating file in the config directory and the in-app Logs panel.
+func (a *App) setupLogging() {
+	a.logRing = &LogRing{}
+	writers := []io.Writer{os.Stderr, a.logRing}
+
+	file, err := newRotatingFile(a.getLogPath(), logMaxSize, logMaxBackups)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Logging to file disabled: %v\n", err)
+	} else {
+		writers = append(writers, file)
+	}
+
+	a.logLevel.Set(slog.LevelInfo)
+	handler := slog.NewTextHandler(io.MultiWriter(writers...), &slog.HandlerOptions{Level: &a.logLevel})
+	slog.SetDefault(slog.New(handler))
+}
+
+// applyLogLevel sets the minimum level logged, e.g. "debug".
+func (a *App) applyLogLevel(name string) {
+	level, ok := logLevels[name]
+	if !ok {
+		level = slog.LevelInfo
+	}
+	a.logLevel.Set(level)
+}
+
+// showLogs opens the Logs panel, which follows new log lines while open.
+func (a *App) showLogs() {
+	if a.logsWindow != nil {
+		a.logsWindow.RequestFocus()
+		return
+	}
+
+	logText := newTextArea()
+	logText.Wrapping = fyne.TextWrapOff
+	logText.TextStyle = fyne.TextStyle{Monospace: true}
+	scroll := container.NewScroll(logText)
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder(tr("Filter (e.g. websocket, level=WARN)"))
+
+	refresh := func() {
+		var shown []string
+		for _, line := range a.logRing.snapshot() {
+			if filterEntry.Text == "" || strings.Contains(strings.ToLower(line), strings.ToLower(filterEntry.Text)) {
+				shown = append(shown, line)
+			}
+		}
+		logText.SetText(strings.Join(shown, "\n"))
+		scroll.ScrollToBottom()
+	}
+	filterEntry.OnChanged = func(string) { refresh() }
+
+	levelSelect := widget.NewSelect([]string{"debug", "info", "warn", "error"}, nil)
+	levelSelect.SetSelected(a.settings().LogLevel)
+	levelSelect.OnChanged = func(level string) {
+		a.applyLogLevel(level)
+		a.updateSettings(func(s *Settings) { s.LogLevel = level })
+		if err := a.writeSettings(a.store, a.settings()); err != nil {
+			dialog.ShowError(err, a.logsWindow)
+		}
+	}
+
+	copyBtn := widget.NewButtonWithIcon(tr("Copy"), theme.ContentCopyIcon(), func() {
+		a.window.Clipboard().SetContent(logText.Text)
+	})
+	folderBtn := widget.NewButtonWithIcon(tr("Open Log Folder"), theme.FolderOpenIcon(), func() {
+		if err := a.openFolder(filepath.Dir(a.getLogPath())); err != nil {
+			dialog.ShowError(err, a.logsWindow)
+		}
+	})
+
+	inspectorBtn := widget.NewButtonWithIcon(tr("Protocol Inspector"), theme.ComputerIcon(), a.showInspector)
+	replayBtn := widget.NewButtonWithIcon(tr("Replay..."), theme.MediaReplayIcon(), a.showReplay)
+
+	toolbar := container.NewBorder(nil, nil, container.NewHBox(widget.NewLabel(tr("Level:")), levelSelect), container.NewHBox(copyBtn, folderBtn, inspectorBtn, replayBtn), filterEntry)
+
+	a.logsWindow = a.fyneApp.NewWindow(tr("Logs"))
+	a.logsWindow.SetContent(container.NewBorder(toolbar, nil, nil, nil, scroll))
+	a.logsWindow.Resize(fyne.NewSize(800, 500))
+	a.logsWindow.SetOnClosed(func() {
+		a.logRing.setOnChange(nil)
+		a.logsWindow = nil
+	})
+	// Coalesce bursts of log lines into one refresh
+	var pending atomic.Bool
+	a.logRing.setOnChange(func() {
+		if pending.CompareAndSwap(false, true) {
+			time.AfterFunc(250*time.Millisecond, func() {
+				pending.Store(false)
+				fyne.Do(refresh)
+			})
+		}
+	})
+	refresh()
+	a.logsWindow.Show()
+}
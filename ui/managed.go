@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"dict/config"
+	"dict/llm"
+)
+
+const (
+	managedRefreshInterval = time.Hour
+	managedMaxSize         = 1 << 20
+)
+
+// ManagedConfig is a team's shared configuration, fetched from a read-only
+// URL. Its settings use the same format as the config file, migrated if
+// older, and apply unless the user has changed them locally.
+type ManagedConfig struct {
+	Settings   config.Values         `json:"settings"`
+	Prompts    map[string]string     `json:"prompts"`       // System prompt presets by name
+	Params     map[string]llm.Params `json:"prompt_params"` // Sampling parameters for presets
+	Raw        []string              `json:"raw_presets"`   // Presets whose replies aren't cleaned
+	Vocabulary []string              `json:"vocabulary"`    // Terms to boost in transcription
+}
+
+// Settings a managed config can't set.
+var localOnlySettings = []string{"team.config_url", "profile", "ui.seen_hints"}
+
+func fetchManagedConfig(client *http.Client, source string) (*ManagedConfig, []byte, error) {
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch team presets: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch team presets: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, managedMaxSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read team presets: %v", err)
+	}
+	m, err := parseManagedConfig(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, data, nil
+}
+
+func parseManagedConfig(data []byte) (*ManagedConfig, error) {
+	var m ManagedConfig
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse team presets: %v", err)
+	}
+	m.Settings, _ = config.Migrate(m.Settings)
+	for _, path := range localOnlySettings {
+		m.Settings.Delete(path)
+	}
+	return &m, nil
+}
+
+func (a *App) getManagedCachePath() string {
+	return filepath.Join(a.getConfigDir(), "managed.json")
+}
+
+// managedConfig returns the last fetched team configuration, or an empty one.
+func (a *App) managedConfig() *ManagedConfig {
+	if m := a.managed.Load(); m != nil {
+		return m
+	}
+	return &ManagedConfig{}
+}
+
+// loadManagedCache restores the last fetched team configuration, so it
+// applies even when offline.
+func (a *App) loadManagedCache() {
+	data, err := os.ReadFile(a.getManagedCachePath())
+	if err != nil {
+		return
+	}
+	m, err := parseManagedConfig(data)
+	if err != nil {
+		slog.Warn("ignoring cached team presets", "err", err)
+		return
+	}
+	a.managed.Store(m)
+}
+
+// effectiveSettings builds settings from a local config, with managed values
+// filling in anything the local config doesn't set, and the settings given at
+// launch over both.
+func (a *App) effectiveSettings(local config.Values) *Settings {
+	m := a.managedConfig()
+	c := defaultSettings().toConfig()
+	overrides := make(config.Values)
+	for path, value := range a.overrides {
+		overrides.Set(path, value)
+	}
+	for _, values := range []config.Values{m.Settings, local, overrides} {
+		if err := values.Decode(c); err != nil {
+			slog.Warn("ignoring invalid settings", "err", err)
+		}
+	}
+	s := settingsFromConfig(c)
+	s.PromptPresets = m.Prompts
+	s.PresetParams = m.Params
+	s.RawPresets = m.Raw
+	s.Vocabulary = mergeVocabulary(m.Vocabulary, customTerms(s.CustomVocabulary))
+	return s
+}
+
+// localConfig is the config to save for s: managed values the user hasn't
+// changed are left out, so later updates from the team still apply.
+func (a *App) localConfig(s *Settings) config.Values {
+	return s.toConfig().Values().Without(a.managedConfig().Settings)
+}
+
+func (a *App) refreshManagedConfig() error {
+	source := a.settings().ManagedConfigURL
+	if source == "" {
+		return nil
+	}
+	client, err := a.httpClient(30 * time.Second)
+	if err != nil {
+		return err
+	}
+	m, data, err := fetchManagedConfig(client, source)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.getConfigDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(a.getManagedCachePath(), data, 0600); err != nil {
+		slog.Warn("failed to cache team presets", "err", err)
+	}
+
+	// Reapply the local config over the new managed values
+	local, err := a.store.Load()
+	if err != nil {
+		local = config.Values{}
+	}
+	a.managed.Store(m)
+	a.config.Store(a.effectiveSettings(local))
+	slog.Info("team presets refreshed", "settings", len(m.Settings), "prompts", len(m.Prompts), "vocabulary", len(m.Vocabulary))
+	return nil
+}
+
+// startManagedConfigWatcher fetches the team configuration now and hourly.
+func (a *App) startManagedConfigWatcher() {
+	go func() {
+		for {
+			if err := a.refreshManagedConfig(); err != nil {
+				slog.Warn("team presets refresh failed", "err", err)
+				fyne.Do(func() {
+					a.updateStatus(tr("Using cached team presets: %s", err))
+				})
+			}
+			time.Sleep(managedRefreshInterval)
+		}
+	}()
+}
+
+// promptPresetNames lists the managed system prompt presets.
+func (s *Settings) promptPresetNames() []string {
+	var names []string
+	for name := range s.PromptPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
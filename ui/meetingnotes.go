@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+)
+
+const builtinMeetingPipeline = "Meeting notes (built-in)"
+
+// meetingNotesPipeline is used when no pipeline of the user's is chosen.
+var meetingNotesPipeline = Pipeline{
+	Name: builtinMeetingPipeline,
+	Steps: []PipelineStep{{
+		Name: "Meeting notes",
+		Prompt: `Write notes for the meeting "{{title}}" on {{date}} (attendees: {{attendees}}) from the transcript below, in Markdown with these sections:
+## Summary
+A short paragraph on what was discussed.
+## Decisions
+Bullets of what was agreed.
+## Action Items
+Checkboxes ("- [ ] ") with the owner and any due date, where mentioned.
+Leave a section with "None." if nothing applies. Output only the notes.`,
+	}},
+}
+
+// meetingPipeline finds the pipeline configured for meeting notes, falling
+// back to the built-in one.
+func (a *App) meetingPipeline() Pipeline {
+	name := a.settings().MeetingNotesPipeline
+	if name == "" || name == builtinMeetingPipeline {
+		return meetingNotesPipeline
+	}
+	pipelines, err := a.loadPipelines()
+	if err != nil {
+		slog.Warn("using built-in meeting notes pipeline", "err", err)
+	}
+	for _, p := range pipelines {
+		if p.Name == name {
+			return p
+		}
+	}
+	slog.Warn("meeting notes pipeline not found, using built-in", "pipeline", name)
+	return meetingNotesPipeline
+}
+
+// summarizeMeeting runs the meeting notes pipeline over the session's turns
+// once recording stops, and shows the result in the Meeting Notes tab. Call
+// it on the UI thread.
+func (a *App) summarizeMeeting(session int) {
+	a.mu.RLock()
+	var lines []string
+	var started time.Time
+	for _, turn := range a.turns {
+		if turn.Session == session && turn.Text != "" {
+			if started.IsZero() {
+				started = turn.Start
+			}
+			lines = append(lines, turn.display())
+		}
+	}
+	a.mu.RUnlock()
+	if len(lines) == 0 {
+		return
+	}
+	if a.settings().GroqAPIKey == "" {
+		a.updateStatus(tr("Meeting notes need a Groq API key in Settings"))
+		return
+	}
+
+	p := a.meetingPipeline()
+	transcript := strings.Join(lines, "\n")
+	vars := a.promptContext(transcript)
+	if vars.Title == "" {
+		vars.Title = "Meeting"
+	}
+	heading := fmt.Sprintf("# %s — %s\n\n", vars.Title, started.Format("2 Jan 2006 15:04"))
+
+	slog.Info("summarizing meeting", "pipeline", p.Name, "turns", len(lines))
+	ctx, done := a.startLLMTask()
+	go func() {
+		results, err := a.runPipeline(ctx, p, transcript, vars, func(step int) {
+			fyne.Do(func() {
+				a.updateStatus(tr("Writing meeting notes: step %d of %d (%s)...", step+1, len(p.Steps), p.Steps[step].Name))
+			})
+		})
+		fyne.Do(func() {
+			done()
+			if err := a.llmError(err); err != nil {
+				slog.Error("meeting notes failed", "err", err)
+				a.updateStatus(tr("Meeting notes failed: %s", err))
+				a.showError(err)
+				return
+			}
+			if len(results) < len(p.Steps) {
+				a.updateStatus(tr("Meeting notes cancelled"))
+				return
+			}
+			a.appendMeetingNotes(heading + strings.TrimSpace(results[len(results)-1].text()) + "\n")
+			a.reportResult(tr("Meeting notes ready"))
+		})
+	}()
+}
+
+// appendMeetingNotes adds a meeting's notes to the Meeting Notes tab, next to
+// the verbatim transcript, and switches to it.
+func (a *App) appendMeetingNotes(notes string) {
+	if a.meetingTab == nil {
+		a.meetingArea = newTextArea()
+		a.meetingArea.Wrapping = fyne.TextWrapWord
+		a.meetingTab = container.NewTabItemWithIcon(tr("Meeting Notes"), theme.DocumentIcon(), container.NewScroll(a.meetingArea))
+		a.tabs.Append(a.meetingTab)
+	}
+
+	text := a.meetingArea.Text
+	if text != "" {
+		text += "\n"
+	}
+	a.meetingArea.SetText(text + notes)
+	a.tabs.Select(a.meetingTab)
+}
+
+// meetingPipelineNames lists the pipelines that can write meeting notes.
+func (a *App) meetingPipelineNames() []string {
+	names := []string{builtinMeetingPipeline}
+	pipelines, _ := a.loadPipelines()
+	for _, p := range pipelines {
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	miniRefresh = 100 * time.Millisecond
+	silenceDB   = -60 // Levels at or below this show as an empty meter
+)
+
+var errUnsupportedOnTop = errors.New("always on top isn't supported on this platform")
+
+// pcmDB returns the RMS loudness of 16-bit PCM in dB relative to full
+// scale, -Inf for digital silence.
+func pcmDB(pcm []byte) float64 {
+	if len(pcm) < 2 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for i := 0; i+1 < len(pcm); i += 2 {
+		sample := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) / math.MaxInt16
+		sum += sample * sample
+	}
+	return 10 * math.Log10(sum/float64(len(pcm)/2))
+}
+
+// pcmLevel returns the loudness of 16-bit PCM from 0 (silence) to 1 (full
+// scale), on a decibel scale so speech moves the meter.
+func pcmLevel(pcm []byte) float64 {
+	return min(max((pcmDB(pcm)-silenceDB)/-silenceDB, 0), 1)
+}
+
+// setAudioLevel records the level of the audio just sent, for the meter.
+func (a *App) setAudioLevel(pcm []byte) {
+	a.audioLevel.Store(math.Float64bits(pcmLevel(pcm)))
+}
+
+// setAlwaysOnTop keeps the window above others where the platform allows.
+func (a *App) setAlwaysOnTop(above bool) error {
+	native, ok := a.window.(driver.NativeWindow)
+	if !ok {
+		return errUnsupportedOnTop
+	}
+	var err error
+	native.RunNative(func(context any) {
+		err = setNativeOnTop(context, above)
+	})
+	return err
+}
+
+// lastLine returns the text in progress, or the last finalized turn.
+func (a *App) lastLine() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if partial := strings.TrimSpace(a.partialText()); partial != "" {
+		return partial
+	}
+	for i := len(a.turns) - 1; i >= 0; i-- {
+		if a.turns[i].Text != "" {
+			return a.turns[i].display()
+		}
+	}
+	return ""
+}
+
+// newMiniView builds the compact strip: record button, level meter and the
+// last line of transcript.
+func (a *App) newMiniView() fyne.CanvasObject {
+	a.miniRecordBtn = newTipButton("", theme.MediaRecordIcon(), tr("Start or stop recording"), a.toggleRecording)
+	a.miniLevel = widget.NewProgressBar()
+	a.miniLevel.TextFormatter = func() string { return "" }
+	a.miniLine = widget.NewLabel("")
+	a.miniLine.Truncation = fyne.TextTruncateEllipsis
+	snippetBtn := newTipButton("", theme.ContentPasteIcon(), tr("Dictate an instant snippet to copy or type"), a.startSnippet)
+	expandBtn := newTipButton("", theme.ViewFullScreenIcon(), tr("Leave mini mode"), a.toggleMiniMode)
+
+	level := container.NewGridWrap(fyne.NewSize(60, a.miniLevel.MinSize().Height), a.miniLevel)
+	return container.NewBorder(nil, nil, container.NewHBox(a.miniRecordBtn, snippetBtn, level), expandBtn, a.miniLine)
+}
+
+// toggleMiniMode collapses the window to the mini strip, kept on top of
+// other windows, or restores the full window.
+func (a *App) toggleMiniMode() {
+	if a.miniStop != nil {
+		close(a.miniStop)
+		a.miniStop = nil
+		a.window.SetContent(a.fullView)
+		a.window.Resize(a.fullSize)
+		if err := a.setAlwaysOnTop(false); err != nil && !errors.Is(err, errUnsupportedOnTop) {
+			slog.Warn("failed to clear always on top", "err", err)
+		}
+		return
+	}
+
+	a.fullView = a.window.Content()
+	a.fullSize = a.window.Canvas().Size()
+	if a.miniView == nil {
+		a.miniView = a.newMiniView()
+	}
+	a.window.SetContent(a.miniView)
+	a.window.Resize(fyne.NewSize(420, a.miniView.MinSize().Height))
+	if err := a.setAlwaysOnTop(true); err != nil {
+		slog.Warn("mini mode can't stay on top", "err", err)
+		a.miniLine.SetText(tr("Use your window manager to keep this window on top"))
+	}
+
+	stop := make(chan struct{})
+	a.miniStop = stop
+	go func() {
+		ticker := time.NewTicker(miniRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(a.updateMiniView)
+			}
+		}
+	}()
+}
+
+func (a *App) updateMiniView() {
+	if a.miniStop == nil {
+		return
+	}
+	level := 0.0
+	icon := theme.MediaRecordIcon()
+	if a.recording.Load() {
+		level = math.Float64frombits(a.audioLevel.Load())
+		icon = theme.MediaStopIcon()
+	}
+	a.miniLevel.SetValue(level)
+	if a.miniRecordBtn.Icon != icon {
+		a.miniRecordBtn.SetIcon(icon)
+	}
+	if line := a.lastLine(); line != "" && line != a.miniLine.Text {
+		a.miniLine.SetText(line)
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/llm"
+)
+
+// filterModels returns the models containing query, ignoring case.
+func filterModels(models []string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return models
+	}
+	var matches []string
+	for _, m := range models {
+		if strings.Contains(strings.ToLower(m), query) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// newModelPicker is the model field of the settings dialog: a text entry
+// whose dropdown lists the models fetched from the endpoint, narrowed to
+// those matching what's typed. The endpoint and key are read when "Fetch
+// Models" is pressed, so unsaved changes to them are used.
+func (a *App) newModelPicker(model string, endpoint, apiKey *widget.Entry) (*widget.SelectEntry, fyne.CanvasObject) {
+	picker := widget.NewSelectEntry(a.models)
+	picker.SetPlaceHolder(tr("e.g., meta-llama/llama-4-maverick-17b-128e-instruct"))
+	picker.SetText(model)
+	picker.OnChanged = func(text string) {
+		picker.SetOptions(filterModels(a.models, text))
+	}
+
+	status := widget.NewLabel("")
+	var fetchBtn *widget.Button
+	fetchBtn = widget.NewButton(tr("Fetch Models"), func() {
+		fetchBtn.Disable()
+		status.SetText(tr("Fetching..."))
+		endpoint, apiKey := endpoint.Text, apiKey.Text
+		go func() {
+			client, err := a.httpClient(30 * time.Second)
+			var models []string
+			if err == nil {
+				models, err = (&llm.HTTPClient{Endpoint: endpoint, APIKey: apiKey, HTTP: client}).Models()
+			}
+			fyne.Do(func() {
+				fetchBtn.Enable()
+				if err != nil {
+					slog.Warn("failed to fetch models", "endpoint", endpoint, "err", err)
+					status.SetText(tr("Failed: %s", err))
+					return
+				}
+				slog.Info("fetched models", "endpoint", endpoint, "count", len(models))
+				a.models = models
+				picker.SetOptions(filterModels(models, ""))
+				status.SetText(tr("%d models — type to filter", len(models)))
+			})
+		}()
+	})
+	status.Truncation = fyne.TextTruncateEllipsis
+
+	return picker, container.NewBorder(nil, status, nil, fetchBtn, picker)
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// NavigationCommand is a voice command that moves the caret or changes the
+// selection in the transcript. Its pattern matches the normalized command.
+type NavigationCommand struct {
+	pattern *regexp.Regexp
+	run     func(a *App, match []string)
+}
+
+const countPattern = `(a|an|one|two|three|four|five|six|seven|eight|nine|ten|\d+)`
+
+var navigationCommands = []NavigationCommand{
+	{regexp.MustCompile(`^go to (the )?(end|bottom)$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyPageDown, 1, false) }},
+	{regexp.MustCompile(`^go to (the )?(start|beginning|top)$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyPageUp, 1, false) }},
+	{regexp.MustCompile(`^go to (the )?end of (the )?line$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyEnd, 1, false) }},
+	{regexp.MustCompile(`^go to (the )?(start|beginning) of (the )?line$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyHome, 1, false) }},
+	{regexp.MustCompile(`^move (up|down) ` + countPattern + `? ?lines?$`), func(a *App, m []string) {
+		key := fyne.KeyUp
+		if m[1] == "down" {
+			key = fyne.KeyDown
+		}
+		a.textArea.pressKey(key, parseCount(m[2]), false)
+	}},
+	{regexp.MustCompile(`^move (left|right|back|forward) ` + countPattern + `? ?(words?|characters?|letters?)$`), func(a *App, m []string) {
+		left := m[1] == "left" || m[1] == "back"
+		if strings.HasPrefix(m[3], "word") {
+			a.textArea.moveWords(parseCount(m[2]), left)
+			return
+		}
+		key := fyne.KeyRight
+		if left {
+			key = fyne.KeyLeft
+		}
+		a.textArea.pressKey(key, parseCount(m[2]), false)
+	}},
+	{regexp.MustCompile(`^select all$`), func(a *App, _ []string) { a.textArea.TypedShortcut(&fyne.ShortcutSelectAll{}) }},
+	{regexp.MustCompile(`^select (the )?(this|current) line$`), func(a *App, _ []string) {
+		a.textArea.pressKey(fyne.KeyHome, 1, false)
+		a.textArea.pressKey(fyne.KeyEnd, 1, true)
+	}},
+	{regexp.MustCompile(`^select (the )?last ` + countPattern + `? ?(words?|sentences?|paragraphs?|lines?)$`), func(a *App, m []string) {
+		a.textArea.selectLast(m[3], parseCount(m[2]))
+	}},
+	{regexp.MustCompile(`^(unselect|deselect|clear selection)$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyRight, 1, false) }},
+	{regexp.MustCompile(`^(delete|remove) (that|selection|the selection)$`), func(a *App, _ []string) {
+		if a.textArea.SelectedText() == "" {
+			a.updateStatus(tr("Nothing selected"))
+			return
+		}
+		a.textArea.pressKey(fyne.KeyBackspace, 1, false)
+	}},
+	{regexp.MustCompile(`^new line$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyReturn, 1, false) }},
+	{regexp.MustCompile(`^new paragraph$`), func(a *App, _ []string) { a.textArea.pressKey(fyne.KeyReturn, 2, false) }},
+}
+
+var spokenNumbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// parseCount reads a spoken count, which defaults to one when left out.
+func parseCount(word string) int {
+	if n, ok := spokenNumbers[word]; ok {
+		return n
+	}
+	if n, err := strconv.Atoi(word); err == nil && n > 0 {
+		return min(n, 1000)
+	}
+	return 1
+}
+
+// findNavigationCommand matches a spoken command against the navigation
+// patterns, returning the command and its submatches.
+func findNavigationCommand(text string) (*NavigationCommand, []string) {
+	spoken := normalizeCommand(text)
+	for i, cmd := range navigationCommands {
+		if m := cmd.pattern.FindStringSubmatch(spoken); m != nil {
+			return &navigationCommands[i], m
+		}
+	}
+	return nil, nil
+}
+
+// pressKey types a key into the entry times times, holding shift to extend
+// the selection if shift is set.
+func (e *TranscriptEntry) pressKey(key fyne.KeyName, times int, shift bool) {
+	if shift {
+		e.Entry.KeyDown(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+		defer e.Entry.KeyUp(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	}
+	for i := 0; i < times; i++ {
+		e.Entry.TypedKey(&fyne.KeyEvent{Name: key})
+	}
+}
+
+// moveWords moves the caret by whole words, like Ctrl+Left/Right.
+func (e *TranscriptEntry) moveWords(times int, left bool) {
+	key := fyne.KeyRight
+	if left {
+		key = fyne.KeyLeft
+	}
+	shortcut := &desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault}
+	for i := 0; i < times; i++ {
+		e.TypedShortcut(shortcut)
+	}
+}
+
+// selectLast selects the last count words, sentences or paragraphs (lines)
+// of the transcript, leaving out trailing whitespace.
+func (e *TranscriptEntry) selectLast(unit string, count int) {
+	text := strings.TrimRightFunc(e.Text, unicode.IsSpace)
+	trailing := utf8.RuneCountInString(e.Text) - utf8.RuneCountInString(text)
+	start := lastUnitsStart(text, strings.TrimSuffix(unit, "s"), count)
+	e.pressKey(fyne.KeyPageDown, 1, false)
+	e.pressKey(fyne.KeyLeft, trailing, false)
+	e.pressKey(fyne.KeyLeft, utf8.RuneCountInString(text[start:]), true)
+}
+
+var sentenceEndPattern = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+
+// lastUnitsStart returns the byte offset where the last count units of text
+// begin.
+func lastUnitsStart(text, unit string, count int) int {
+	var starts []int
+	switch unit {
+	case "word":
+		for i, r := range text {
+			if !unicode.IsSpace(r) && (i == 0 || unicode.IsSpace(lastRune(text[:i]))) {
+				starts = append(starts, i)
+			}
+		}
+	case "sentence":
+		starts = append(starts, 0)
+		for _, loc := range sentenceEndPattern.FindAllStringIndex(text, -1) {
+			starts = append(starts, loc[1])
+		}
+	default: // Paragraphs and lines: each turn is on its own line
+		starts = append(starts, 0)
+		for i, r := range text {
+			if r == '\n' && i+1 < len(text) && text[i+1] != '\n' {
+				starts = append(starts, i+1)
+			}
+		}
+	}
+	if len(starts) == 0 {
+		return 0
+	}
+	return starts[max(len(starts)-count, 0)]
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// runNavigationCommand runs a navigation command if text is one, and
+// reports whether it was.
+func (a *App) runNavigationCommand(text string) bool {
+	cmd, match := findNavigationCommand(text)
+	if cmd == nil {
+		return false
+	}
+	slog.Info("navigation command", "command", match[0])
+	if a.textArea.Visible() {
+		a.window.Canvas().Focus(a.textArea)
+	}
+	cmd.run(a, match)
+	a.updateStatus(tr("Command: %s", match[0]))
+	return true
+}
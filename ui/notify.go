@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+)
+
+var errUnsupportedNotifyAction = errors.New("notification click actions aren't supported on this platform")
+
+// notify shows a desktop notification while the window is in the
+// background, so results and errors aren't missed while the user works in
+// another app. Clicking it brings the window back where the platform lets
+// a notification have an action; elsewhere the system decides.
+func (a *App) notify(title, content string) {
+	if !a.background.Load() || !a.settings().Notifications {
+		return
+	}
+	err := sendNotification(tr("Voice Typing"), title, content, func() {
+		fyne.Do(func() {
+			a.window.Show()
+			a.window.RequestFocus()
+		})
+	})
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, errUnsupportedNotifyAction) {
+		slog.Warn("failed to send notification with an action", "err", err)
+	}
+	a.fyneApp.SendNotification(fyne.NewNotification(title, content))
+}
+
+// reportResult shows the outcome of background work such as an LLM call in
+// the status bar, and notifies the user if the window is in the background.
+func (a *App) reportResult(status string) {
+	a.updateStatus(status)
+	a.notify(status, "")
+}
+
+// trackForeground notes when the app's windows lose and regain focus, so
+// notifications are only sent while the user is elsewhere.
+func (a *App) trackForeground() {
+	lifecycle := a.fyneApp.Lifecycle()
+	lifecycle.SetOnExitedForeground(func() { a.background.Store(true) })
+	lifecycle.SetOnEnteredForeground(func() { a.background.Store(false) })
+}
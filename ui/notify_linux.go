@@ -0,0 +1,91 @@
+//go:build linux
+
+package ui
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notifyService   = "org.freedesktop.Notifications"
+	notifyPath      = "/org/freedesktop/Notifications"
+	notifyInterface = "org.freedesktop.Notifications"
+)
+
+// notifierState sends notifications over the session bus, which unlike
+// Fyne's lets them have a default action, and runs each one's onClick when
+// it's clicked.
+type notifierState struct {
+	once   sync.Once
+	conn   *dbus.Conn
+	err    error
+	mu     sync.Mutex
+	clicks map[uint32]func()
+}
+
+var notifier notifierState
+
+func sendNotification(app, title, content string, onClick func()) error {
+	n := &notifier
+	n.once.Do(func() {
+		conn, err := dbus.ConnectSessionBus()
+		if err != nil {
+			n.err = fmt.Errorf("failed to connect to the session bus: %v", err)
+			return
+		}
+		for _, member := range []string{"ActionInvoked", "NotificationClosed"} {
+			if err := conn.AddMatchSignal(dbus.WithMatchInterface(notifyInterface), dbus.WithMatchMember(member)); err != nil {
+				conn.Close()
+				n.err = fmt.Errorf("failed to watch notifications: %v", err)
+				return
+			}
+		}
+		n.conn = conn
+		n.clicks = make(map[uint32]func())
+		signals := make(chan *dbus.Signal, 16)
+		conn.Signal(signals)
+		go n.watch(signals)
+	})
+	if n.err != nil {
+		return n.err
+	}
+
+	var id uint32
+	actions := []string{"default", tr("Show")}
+	err := n.conn.Object(notifyService, notifyPath).Call(notifyInterface+".Notify", 0,
+		app, uint32(0), "", title, content, actions, map[string]dbus.Variant{}, int32(-1)).Store(&id)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %v", err)
+	}
+	n.mu.Lock()
+	n.clicks[id] = onClick
+	n.mu.Unlock()
+	return nil
+}
+
+// watch runs the click action of notifications clicked, and forgets those
+// closed.
+func (n *notifierState) watch(signals <-chan *dbus.Signal) {
+	for signal := range signals {
+		if len(signal.Body) == 0 {
+			continue
+		}
+		id, ok := signal.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		n.mu.Lock()
+		onClick := n.clicks[id]
+		if signal.Name == notifyInterface+".NotificationClosed" {
+			delete(n.clicks, id)
+			onClick = nil
+		}
+		n.mu.Unlock()
+		if onClick != nil {
+			onClick()
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package ui
+
+func sendNotification(app, title, content string, onClick func()) error {
+	return errUnsupportedNotifyAction
+}
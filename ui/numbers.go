@@ -0,0 +1,591 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Number styles for finalized turns
+const (
+	numbersAsSpoken   = ""
+	numbersDigits     = "digits" // "twenty five" becomes "25"
+	numbersSmallWords = "words"  // Digits from 10 up, one to nine spelled out
+)
+
+var numberStyleLabels = map[string]string{
+	numbersAsSpoken:   trMark("As transcribed"),
+	numbersDigits:     trMark("Digits (twenty five → 25)"),
+	numbersSmallWords: trMark("Spell out one to nine, digits from 10"),
+}
+
+var numberStyleOrder = []string{numbersAsSpoken, numbersDigits, numbersSmallWords}
+
+// dateFormats are the layouts dates with a year can be rewritten in.
+var dateFormats = []string{"2006-01-02", "January 2, 2006", "2 January 2006", "01/02/2006", "02/01/2006"}
+
+// phoneFormats are templates for ten-digit phone numbers, each digit
+// standing for the next digit of the number.
+var phoneFormats = []string{"555-123-4567", "(555) 123-4567", "555.123.4567", "555 123 4567"}
+
+func dateFormatLabel(layout string) string {
+	return time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC).Format(layout)
+}
+
+var cardinalWords = map[string]int64{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+	"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+	"seventeen": 17, "eighteen": 18, "nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+	"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+var ordinalWords = map[string]int64{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9,
+	"tenth": 10, "eleventh": 11, "twelfth": 12, "thirteenth": 13, "fourteenth": 14, "fifteenth": 15,
+	"sixteenth": 16, "seventeenth": 17, "eighteenth": 18, "nineteenth": 19, "twentieth": 20, "thirtieth": 30,
+	"fortieth": 40, "fiftieth": 50, "sixtieth": 60, "seventieth": 70, "eightieth": 80, "ninetieth": 90,
+}
+
+var scaleWords = map[string]int64{"hundred": 100, "thousand": 1e3, "million": 1e6, "billion": 1e9}
+
+var monthNames = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+var currencySymbols = map[string]string{
+	"dollar": "$", "dollars": "$", "bucks": "$", "euro": "€", "euros": "€",
+	"pound": "£", "pounds": "£", "pounds sterling": "£", "quid": "£", "yen": "¥",
+}
+
+// unitSymbols abbreviate the units after a number. Symbols starting with %
+// or ° are written without a space.
+var unitSymbols = []struct {
+	names  []string
+	symbol string
+}{
+	{[]string{"percent", "per cent"}, "%"},
+	{[]string{"degrees celsius", "degrees centigrade"}, "°C"},
+	{[]string{"degrees fahrenheit"}, "°F"},
+	{[]string{"degrees", "degree"}, "°"},
+	{[]string{"kilometers per hour", "kilometres per hour"}, "km/h"},
+	{[]string{"miles per hour"}, "mph"},
+	{[]string{"kilowatt hours", "kilowatt hour"}, "kWh"},
+	{[]string{"kilometers", "kilometer", "kilometres", "kilometre"}, "km"},
+	{[]string{"centimeters", "centimeter", "centimetres", "centimetre"}, "cm"},
+	{[]string{"millimeters", "millimeter", "millimetres", "millimetre"}, "mm"},
+	{[]string{"meters", "meter", "metres", "metre"}, "m"},
+	{[]string{"kilograms", "kilogram", "kilos", "kilo"}, "kg"},
+	{[]string{"milligrams", "milligram"}, "mg"},
+	{[]string{"grams", "gram"}, "g"},
+	{[]string{"milliliters", "milliliter", "millilitres", "millilitre"}, "mL"},
+	{[]string{"liters", "liter", "litres", "litre"}, "L"},
+	{[]string{"miles", "mile"}, "mi"},
+	{[]string{"feet", "foot"}, "ft"},
+	{[]string{"inches", "inch"}, "in"},
+	{[]string{"ounces", "ounce"}, "oz"},
+	{[]string{"kilobytes", "kilobyte"}, "KB"},
+	{[]string{"megabytes", "megabyte"}, "MB"},
+	{[]string{"gigabytes", "gigabyte"}, "GB"},
+	{[]string{"terabytes", "terabyte"}, "TB"},
+	{[]string{"milliseconds", "millisecond"}, "ms"},
+	{[]string{"kilohertz"}, "kHz"},
+	{[]string{"megahertz"}, "MHz"},
+	{[]string{"gigahertz"}, "GHz"},
+	{[]string{"hertz"}, "Hz"},
+	{[]string{"kilowatts", "kilowatt"}, "kW"},
+	{[]string{"watts", "watt"}, "W"},
+	{[]string{"volts", "volt"}, "V"},
+}
+
+var unitNames = func() map[string]string {
+	names := make(map[string]string)
+	for _, unit := range unitSymbols {
+		for _, name := range unit.names {
+			names[name] = unit.symbol
+		}
+	}
+	return names
+}()
+
+// alternation matches any of words, longest first, with any whitespace
+// between the words of a phrase.
+func alternation(words []string) string {
+	sorted := append([]string(nil), words...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for i, w := range sorted {
+		sorted[i] = strings.ReplaceAll(regexp.QuoteMeta(w), " ", `\s+`)
+	}
+	return strings.Join(sorted, "|")
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+var (
+	// A run of number words. "second" only counts after a tens word, since
+	// on its own it's usually time or order.
+	numberWordsPattern = func() *regexp.Regexp {
+		var words []string
+		for w := range cardinalWords {
+			words = append(words, w)
+		}
+		for w := range ordinalWords {
+			if w != "second" {
+				words = append(words, w)
+			}
+		}
+		words = append(words, mapKeys(scaleWords)...)
+		word := `(?:(?:twenty|thirty|forty|fifty|sixty|seventy|eighty|ninety)[\s-]+second|an?\s+(?:` + alternation(mapKeys(scaleWords)) + `)|` + alternation(words) + `)`
+		return regexp.MustCompile(`(?i)\b` + word + `(?:(?:[\s-]+(?:and|point)[\s-]+|[\s-]+)` + word + `)*\b`)
+	}()
+
+	months          = alternation(monthNames)
+	monthBefore     = regexp.MustCompile(`(?i)\b(?:` + months + `)\s+(?:the\s+)?$`)
+	monthAfter      = regexp.MustCompile(`(?i)^(?:st|nd|rd|th)?\s+(?:of\s+)?(?:` + months + `)\b`)
+	currencyAfter   = regexp.MustCompile(`(?i)^\s+(?:` + alternation(mapKeys(currencySymbols)) + `)\b`)
+	unitAfter       = regexp.MustCompile(`(?i)^\s+(?:` + alternation(mapKeys(unitNames)) + `)\b`)
+	clockAfter      = regexp.MustCompile(`(?i)^(?:\s*(?:[ap]\.?\s?m\b|o['’]clock\b)|:\d)`)
+	unitSymbolAfter = regexp.MustCompile(`^\s*(?:km|cm|mm|kg|mg|mL|mi|ft|oz|KB|MB|GB|TB|ms|Hz|kHz|MHz|GHz|kW|kWh|mph|m|g|L|W|V)\b`)
+
+	amountPattern   = `(\d[\d,]*(?:\.\d+)?)`
+	currencyPattern = regexp.MustCompile(`(?i)\b` + amountPattern + `\s+(` + alternation(mapKeys(currencySymbols)) + `)\b(?:\s+(?:and\s+)?(\d{1,2})\s+(?:cents?|pence)\b)?`)
+	unitPattern     = regexp.MustCompile(`(?i)\b` + amountPattern + `\s+(` + alternation(mapKeys(unitNames)) + `)\b`)
+
+	monthDayYearPattern = regexp.MustCompile(`(?i)\b(` + months + `)\s+(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+	dayMonthYearPattern = regexp.MustCompile(`(?i)\b(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?(` + months + `),?\s+(\d{4})\b`)
+
+	phoneCandidatePattern = regexp.MustCompile(`\+?\(?\d(?:[\s.()-]{0,3}\d)+`)
+	digitGroupPattern     = regexp.MustCompile(`\d+`)
+	digitTokenPattern     = regexp.MustCompile(`\d+(?:[.,:/-]\d+)*`)
+)
+
+// NumberFormat is how numbers in finalized turns are rewritten.
+type NumberFormat struct {
+	style    string // numbersAsSpoken, numbersDigits or numbersSmallWords
+	currency bool
+	units    bool
+	date     string // Layout from dateFormats, or empty
+	phone    string // Template from phoneFormats, or empty
+}
+
+func numberFormat(cfg *Settings) NumberFormat {
+	return NumberFormat{cfg.NumberStyle, cfg.CurrencySymbols, cfg.UnitSymbols, cfg.DateFormat, cfg.PhoneFormat}
+}
+
+func (f NumberFormat) enabled() bool {
+	return f != NumberFormat{}
+}
+
+// apply rewrites number words as digits, then formats currency, units,
+// dates and phone numbers, then spells out small numbers if the style asks
+// for it.
+func (f NumberFormat) apply(text string) string {
+	text = f.convertNumberWords(text)
+	if f.currency {
+		text = currencyPattern.ReplaceAllStringFunc(text, formatCurrency)
+	}
+	if f.units {
+		text = unitPattern.ReplaceAllStringFunc(text, formatUnit)
+	}
+	if f.date != "" {
+		text = monthDayYearPattern.ReplaceAllStringFunc(text, func(m string) string {
+			sub := monthDayYearPattern.FindStringSubmatch(m)
+			return formatDate(m, sub[1], sub[2], sub[3], f.date)
+		})
+		text = dayMonthYearPattern.ReplaceAllStringFunc(text, func(m string) string {
+			sub := dayMonthYearPattern.FindStringSubmatch(m)
+			return formatDate(m, sub[2], sub[1], sub[3], f.date)
+		})
+	}
+	if f.phone != "" {
+		text = phoneCandidatePattern.ReplaceAllStringFunc(text, func(m string) string { return formatPhone(m, f.phone) })
+	}
+	if f.style == numbersSmallWords {
+		text = spellSmallNumbers(text)
+	}
+	return text
+}
+
+// spokenNumber is a number read from words, or a word between numbers that
+// isn't part of one, such as the "and" in "one and two".
+type spokenNumber struct {
+	value    int64
+	decimals string // Digits after "point"
+	ordinal  bool
+	small    bool   // Under a hundred without a scale word, e.g. the halves of "nineteen eighty"
+	year     bool   // Read as a year, so written without a thousands separator
+	scale    string // Scale word kept after a decimal, as in "2.5 million"
+	literal  string
+}
+
+// parseNumberWords reads a run of number words. Words that can't continue
+// the current number start a new one, so "five six" is two numbers, and
+// pairs like "twenty twenty four" are read as years. "a" or "an" before a
+// scale word counts as one.
+func parseNumberWords(run string) []spokenNumber {
+	words := strings.FieldsFunc(strings.ToLower(run), func(r rune) bool { return unicode.IsSpace(r) || r == '-' })
+	var out []spokenNumber
+	var total, current int64
+	started := false
+	last := "" // "unit", "teen", "tens", "hundred" or "scale"
+	flush := func(ordinal bool) {
+		if started {
+			out = append(out, spokenNumber{
+				value:   total + current,
+				ordinal: ordinal,
+				small:   total == 0 && current < 100 && last != "hundred",
+				year:    total == 2000 && current > 0 && current < 100, // "two thousand twenty four"
+			})
+		}
+		total, current, started, last = 0, 0, false, ""
+	}
+	isDigit := func(w string) bool { v, ok := cardinalWords[w]; return ok && v < 10 }
+
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case w == "and" && (last == "hundred" || last == "scale"):
+			continue
+		case (w == "a" || w == "an") && i+1 < len(words) && scaleWords[words[i+1]] > 0:
+			flush(false)
+			continue
+		case w == "point" && started && i+1 < len(words) && isDigit(words[i+1]):
+			var digits strings.Builder
+			for i+1 < len(words) && isDigit(words[i+1]) {
+				i++
+				digits.WriteString(strconv.FormatInt(cardinalWords[words[i]], 10))
+			}
+			flush(false)
+			n := &out[len(out)-1]
+			n.decimals, n.small, n.year = digits.String(), false, false
+			// "two point five million" stays as 2.5 million
+			if i+1 < len(words) && words[i+1] != "hundred" && scaleWords[words[i+1]] > 0 {
+				i++
+				n.scale = words[i]
+			}
+			continue
+		case w == "hundred":
+			if current == 0 {
+				current = 1
+			}
+			current *= 100
+			started, last = true, "hundred"
+			continue
+		case scaleWords[w] > 0:
+			if current == 0 {
+				current = 1
+			}
+			total += current * scaleWords[w]
+			current = 0
+			started, last = true, "scale"
+			continue
+		}
+
+		value, ordinal := cardinalWords[w], false
+		if v, ok := ordinalWords[w]; ok {
+			value, ordinal = v, true
+		} else if _, ok := cardinalWords[w]; !ok {
+			flush(false)
+			out = append(out, spokenNumber{literal: w})
+			continue
+		}
+		kind := "tens"
+		switch {
+		case value < 10:
+			kind = "unit"
+		case value < 20:
+			kind = "teen"
+		}
+		if started && last != "hundred" && last != "scale" && !(last == "tens" && kind == "unit") {
+			flush(false)
+		}
+		current += value
+		started, last = true, kind
+		if ordinal {
+			flush(true)
+		}
+	}
+	flush(false)
+
+	// "nineteen eighty four" and "twenty twenty" are years
+	for i := 0; i+1 < len(out); i++ {
+		a, b := out[i], out[i+1]
+		if a.small && b.small && !a.ordinal && !b.ordinal && (a.value == 19 || a.value == 20) && b.value >= 10 {
+			out[i].value = a.value*100 + b.value
+			out[i].small, out[i].ordinal, out[i].year = false, b.ordinal, true
+			out = append(out[:i+1], out[i+2:]...)
+		}
+	}
+	return out
+}
+
+func ordinalSuffix(n int64) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return "th"
+	case n%10 == 1:
+		return "st"
+	case n%10 == 2:
+		return "nd"
+	case n%10 == 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// formatDigits writes a number in digits, grouping thousands unless it was
+// read as a year.
+func formatDigits(n spokenNumber) string {
+	s := strconv.FormatInt(n.value, 10)
+	if n.value >= 1000 && !n.year {
+		var b strings.Builder
+		for i, c := range s {
+			if i > 0 && (len(s)-i)%3 == 0 {
+				b.WriteByte(',')
+			}
+			b.WriteRune(c)
+		}
+		s = b.String()
+	}
+	if n.decimals != "" {
+		s += "." + n.decimals
+	}
+	if n.scale != "" {
+		s += " " + n.scale
+	}
+	if n.ordinal {
+		s += ordinalSuffix(n.value)
+	}
+	return s
+}
+
+// numberWord spells out a number under ten.
+func numberWord(n spokenNumber) string {
+	for w, v := range ordinalWords {
+		if n.ordinal && v == n.value {
+			return w
+		}
+	}
+	for w, v := range cardinalWords {
+		if !n.ordinal && v == n.value {
+			return w
+		}
+	}
+	return formatDigits(n)
+}
+
+// convertNumberWords rewrites runs of number words as digits. With numbers
+// left as spoken, runs are still converted when a currency, unit, date or
+// phone format needs them as digits.
+func (f NumberFormat) convertNumberWords(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range numberWordsPattern.FindAllStringIndex(text, -1) {
+		run, before, after := text[m[0]:m[1]], text[:m[0]], text[m[1]:]
+		b.WriteString(text[last:m[0]])
+		last = m[1]
+		// Part of a hyphenated word like "two-thirds" or "one-on-one"
+		if strings.HasSuffix(before, "-") || strings.HasPrefix(after, "-") {
+			b.WriteString(run)
+			continue
+		}
+		b.WriteString(f.renderNumberWords(run, before, after))
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func (f NumberFormat) renderNumberWords(run, before, after string) string {
+	parts := parseNumberWords(run)
+	digits := 0
+	for _, p := range parts {
+		if p.literal == "" && p.value < 10 && p.decimals == "" && !p.ordinal {
+			digits++
+		}
+	}
+	force := (f.currency && currencyAfter.MatchString(after)) ||
+		(f.units && unitAfter.MatchString(after)) ||
+		(f.date != "" && (monthBefore.MatchString(before) || monthAfter.MatchString(after))) ||
+		(f.phone != "" && digits >= 10 && digits == len(parts))
+	if !force {
+		if f.style == numbersAsSpoken {
+			return run
+		}
+		// A lone "one" is usually a pronoun, and "first" to "ninth" read
+		// better as words
+		if len(parts) == 1 && !strings.ContainsAny(run, " -\t") {
+			if p := parts[0]; p.decimals == "" && ((!p.ordinal && p.value == 1) || (p.ordinal && p.value < 10)) {
+				return run
+			}
+		}
+	}
+
+	// "five pm" and "three o'clock" are times, written in digits
+	clock := clockAfter.MatchString(after)
+	words := make([]string, len(parts))
+	for i, p := range parts {
+		switch {
+		case p.literal != "":
+			words[i] = p.literal
+		case !force && f.style == numbersSmallWords && p.value < 10 && p.decimals == "" && !(clock && i == len(parts)-1):
+			words[i] = numberWord(p)
+		default:
+			words[i] = formatDigits(p)
+		}
+	}
+	out := strings.Join(words, " ")
+	if r, _ := utf8.DecodeRuneInString(run); unicode.IsUpper(r) {
+		out = capitalizeFirst(out)
+	}
+	return out
+}
+
+func capitalizeFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// formatCurrency turns "25 dollars" into "$25", and "5 dollars and 50
+// cents" into "$5.50".
+func formatCurrency(m string) string {
+	sub := currencyPattern.FindStringSubmatch(m)
+	amount, name, cents := sub[1], strings.Join(strings.Fields(strings.ToLower(sub[2])), " "), sub[3]
+	if cents != "" && !strings.Contains(amount, ".") {
+		if len(cents) == 1 {
+			cents = "0" + cents
+		}
+		amount += "." + cents
+	} else if cents != "" {
+		return m
+	}
+	return currencySymbols[name] + amount
+}
+
+// formatUnit turns "5 kilometers" into "5 km" and "20 percent" into "20%".
+func formatUnit(m string) string {
+	sub := unitPattern.FindStringSubmatch(m)
+	symbol := unitNames[strings.Join(strings.Fields(strings.ToLower(sub[2])), " ")]
+	if strings.HasPrefix(symbol, "%") || strings.HasPrefix(symbol, "°") {
+		return sub[1] + symbol
+	}
+	return sub[1] + " " + symbol
+}
+
+// formatDate rewrites a date with a valid month, day and year in layout,
+// leaving anything else alone.
+func formatDate(m, month, day, year, layout string) string {
+	d, _ := strconv.Atoi(day)
+	y, _ := strconv.Atoi(year)
+	for i, name := range monthNames {
+		if strings.EqualFold(name, month) {
+			date := time.Date(y, time.Month(i+1), d, 0, 0, 0, 0, time.UTC)
+			if date.Day() != d {
+				return m
+			}
+			return date.Format(layout)
+		}
+	}
+	return m
+}
+
+// formatPhone fills template with a ten-digit number, or an eleven-digit
+// one starting with the country code 1. Other runs of digits, such as
+// decimals or lists of numbers, are left alone.
+func formatPhone(m, template string) string {
+	groups := digitGroupPattern.FindAllString(m, -1)
+	singles := true
+	for _, g := range groups {
+		singles = singles && len(g) == 1
+	}
+	if !singles && !phoneGroups(groups) {
+		return m
+	}
+	digits := strings.Join(groups, "")
+	prefix := ""
+	switch {
+	case len(digits) == 11 && digits[0] == '1':
+		prefix, digits = "+1 ", digits[1:]
+	case len(digits) != 10:
+		return m
+	}
+	if strings.HasPrefix(m, "+") && prefix == "" {
+		return m
+	}
+	var b strings.Builder
+	i := 0
+	for _, c := range template {
+		if unicode.IsDigit(c) {
+			b.WriteByte(digits[i])
+			i++
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	return prefix + b.String()
+}
+
+// phoneGroups reports whether groups of digits are shaped like a phone
+// number: 555 123 4567, optionally after a country code of 1, or run
+// together.
+func phoneGroups(groups []string) bool {
+	sizes := make([]string, len(groups))
+	for i, g := range groups {
+		sizes[i] = strconv.Itoa(len(g))
+	}
+	switch strings.Join(sizes, ",") {
+	case "10", "11", "3,3,4", "1,3,3,4":
+		return true
+	}
+	return false
+}
+
+// spellSmallNumbers writes lone digits as words, e.g. "5 people" as "five
+// people", leaving those in amounts, measurements, times such as "5 pm" and
+// "3 o'clock", and the like.
+func spellSmallNumbers(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range digitTokenPattern.FindAllStringIndex(text, -1) {
+		token, before, after := text[m[0]:m[1]], text[:m[0]], text[m[1]:]
+		if len(token) != 1 || !spellable(before, after) {
+			continue
+		}
+		word := numberWord(spokenNumber{value: int64(token[0] - '0')})
+		if trimmed := strings.TrimRightFunc(before, unicode.IsSpace); trimmed == "" || strings.ContainsAny(trimmed[len(trimmed)-1:], ".!?") {
+			word = capitalizeFirst(word)
+		}
+		b.WriteString(text[last:m[0]])
+		b.WriteString(word)
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func spellable(before, after string) bool {
+	if r, _ := utf8.DecodeLastRuneInString(before); r != utf8.RuneError && (isWordRune(r) || strings.ContainsRune("$£€¥#+-(/", r)) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(after); r != utf8.RuneError && (isWordRune(r) || strings.ContainsRune("%°)/", r)) {
+		return false
+	}
+	return !unitSymbolAfter.MatchString(after) && !clockAfter.MatchString(after)
+}
+
+// formatNumbers applies the session's number formatting to a final turn.
+// It only reads English number words, so turns in other languages are left
+// alone. Caller must hold a.mu.
+func (a *App) formatNumbers(text string) string {
+	f := numberFormat(a.sessionCfg)
+	if !f.enabled() || (a.language != "" && !strings.HasPrefix(a.language, "en")) {
+		return text
+	}
+	return f.apply(text)
+}
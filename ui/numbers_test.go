@@ -0,0 +1,60 @@
+package ui
+
+import "testing"
+
+func TestNumberFormat(t *testing.T) {
+	digits := NumberFormat{style: numbersDigits}
+	words := NumberFormat{style: numbersSmallWords}
+	for _, tt := range []struct {
+		format NumberFormat
+		text   string
+		want   string
+	}{
+		{digits, "twenty five people came", "25 people came"},
+		{digits, "three hundred and twelve", "312"},
+		{digits, "five thousand", "5,000"},
+		{digits, "twelve thousand five hundred", "12,500"},
+		{digits, "two million", "2,000,000"},
+		{digits, "two point five million", "2.5 million"},
+		{digits, "a million reasons", "1,000,000 reasons"},
+		{digits, "a hundred and ten", "110"},
+		{digits, "A thousand times", "1,000 times"},
+		{digits, "three point one four", "3.14"},
+		{digits, "the twenty first floor", "the 21st floor"},
+		{digits, "the first time", "the first time"},
+		{digits, "one of them", "one of them"},
+		{digits, "five six", "5 6"},
+		{digits, "a two-thirds majority", "a two-thirds majority"},
+		{digits, "in nineteen eighty four", "in 1984"},
+		{digits, "in twenty twenty four", "in 2024"},
+		{digits, "in two thousand twenty four", "in 2024"},
+		{NumberFormat{}, "twenty five people", "twenty five people"},
+		{words, "twenty five and three", "25 and three"},
+		{words, "I have 5 cats", "I have five cats"},
+		{words, "5 cats", "Five cats"},
+		{words, "at 5 pm", "at 5 pm"},
+		{words, "at 5 a.m.", "at 5 a.m."},
+		{words, "at 3 o'clock", "at 3 o'clock"},
+		{words, "at 3:30", "at 3:30"},
+		{words, "at five pm", "at 5 pm"},
+		{words, "it costs $5", "it costs $5"},
+		{words, "5 km away", "5 km away"},
+		{NumberFormat{currency: true}, "twenty five dollars", "$25"},
+		{NumberFormat{currency: true}, "5 dollars and 50 cents", "$5.50"},
+		{NumberFormat{currency: true}, "ten quid", "£10"},
+		{NumberFormat{units: true}, "twenty percent", "20%"},
+		{NumberFormat{units: true}, "5 kilometers", "5 km"},
+		{NumberFormat{units: true}, "ninety degrees fahrenheit", "90°F"},
+		{NumberFormat{date: "2006-01-02"}, "March the fifth, 2024", "2024-03-05"},
+		{NumberFormat{date: "2006-01-02"}, "the 5th of March 2024", "2024-03-05"},
+		{NumberFormat{date: "2006-01-02"}, "February 30, 2024", "February 30, 2024"},
+		{NumberFormat{phone: "(555) 123-4567"}, "call 555 123 4567", "call (555) 123-4567"},
+		{NumberFormat{phone: "555-123-4567"}, "call 1 555 123 4567", "call +1 555-123-4567"},
+		{NumberFormat{phone: "555-123-4567"}, "five five five one two three four five six seven", "555-123-4567"},
+		{NumberFormat{phone: "555-123-4567"}, "scores of 10 20 30 40", "scores of 10 20 30 40"},
+	} {
+		if got := tt.format.apply(tt.text); got != tt.want {
+			t.Errorf("%+v.apply(%q) = %q, want %q", tt.format, tt.text, got, tt.want)
+		}
+	}
+}
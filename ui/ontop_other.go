@@ -0,0 +1,7 @@
+//go:build !windows && !(linux && !wayland)
+
+package ui
+
+func setNativeOnTop(context any, above bool) error {
+	return errUnsupportedOnTop
+}
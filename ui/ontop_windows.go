@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"syscall"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+var setWindowPos = syscall.NewLazyDLL("user32.dll").NewProc("SetWindowPos")
+
+const (
+	hwndTopmost   = ^uintptr(0)     // HWND_TOPMOST, -1
+	hwndNoTopmost = ^uintptr(1)     // HWND_NOTOPMOST, -2
+	swpNoMoveSize = 0x0001 | 0x0002 // SWP_NOSIZE | SWP_NOMOVE
+)
+
+func setNativeOnTop(context any, above bool) error {
+	win, ok := context.(driver.WindowsWindowContext)
+	if !ok || win.HWND == 0 {
+		return errUnsupportedOnTop
+	}
+	after := hwndNoTopmost
+	if above {
+		after = hwndTopmost
+	}
+	if ok, _, err := setWindowPos.Call(win.HWND, after, 0, 0, 0, 0, swpNoMoveSize); ok == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build linux && !wayland
+
+package ui
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <string.h>
+
+// setAbove asks the window manager to keep the window above others, using
+// the EWMH _NET_WM_STATE_ABOVE state.
+static int setAbove(unsigned long window, int above) {
+	Display *display = XOpenDisplay(NULL);
+	if (display == NULL) {
+		return 0;
+	}
+	XEvent event;
+	memset(&event, 0, sizeof(event));
+	event.xclient.type = ClientMessage;
+	event.xclient.window = window;
+	event.xclient.message_type = XInternAtom(display, "_NET_WM_STATE", False);
+	event.xclient.format = 32;
+	event.xclient.data.l[0] = above ? 1 : 0; // _NET_WM_STATE_ADD or _REMOVE
+	event.xclient.data.l[1] = XInternAtom(display, "_NET_WM_STATE_ABOVE", False);
+	event.xclient.data.l[3] = 1; // Normal application
+	XSendEvent(display, DefaultRootWindow(display), False, SubstructureRedirectMask | SubstructureNotifyMask, &event);
+	XFlush(display);
+	XCloseDisplay(display);
+	return 1;
+}
+*/
+import "C"
+
+import (
+	"errors"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+func setNativeOnTop(context any, above bool) error {
+	x11, ok := context.(driver.X11WindowContext)
+	if !ok || x11.WindowHandle == 0 {
+		return errUnsupportedOnTop
+	}
+	var on C.int
+	if above {
+		on = 1
+	}
+	if C.setAbove(C.ulong(x11.WindowHandle), on) == 0 {
+		return errors.New("can't open the X display")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// languageAuto is the Options.Language that detects the spoken language.
+const languageAuto = "auto"
+
+// Options are settings given at launch, by flag or environment variable, so
+// the app can be started preconfigured from a script. They apply over the
+// saved settings without being saved, unless changed in Settings.
+type Options struct {
+	ConfigPath string // Settings file; empty for the default
+	Profile    string // Saved profile to switch to, as if chosen in Settings
+	Device     string // Microphone, by part of its name
+	Language   string // A speechLanguages code, or languageAuto
+	Toggle     bool   // Start or stop recording, in the running instance if there is one
+
+	AssemblyAPIKey string
+	GroqAPIKey     string
+}
+
+// Check reports options that can't be used.
+func (o Options) Check() error {
+	if o.Language != "" && o.Language != languageAuto {
+		if _, ok := speechLanguages[o.Language]; !ok {
+			var codes []string
+			for code := range speechLanguages {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			return fmt.Errorf("unknown language %q: use %s or one of %s", o.Language, languageAuto, strings.Join(codes, ", "))
+		}
+	}
+	if o.Profile != "" {
+		if _, err := os.Stat(profilePath(o.Profile)); err != nil {
+			return fmt.Errorf("no saved profile named %q", o.Profile)
+		}
+	}
+	return nil
+}
+
+// overrides returns the settings the options set, by path in the config.
+func (o Options) overrides() map[string]any {
+	overrides := make(map[string]any)
+	if o.AssemblyAPIKey != "" {
+		overrides["stt.api_key"] = o.AssemblyAPIKey
+	}
+	if o.GroqAPIKey != "" {
+		overrides["llm.api_key"] = o.GroqAPIKey
+	}
+	if o.Device != "" {
+		overrides["audio.device"] = o.Device
+	}
+	// Only the multilingual model hears languages other than English
+	if o.Language != "" {
+		overrides["stt.auto_language"] = o.Language != defaultLanguage
+	}
+	return overrides
+}
+
+// instanceCommand is what a launch with these options asks a running
+// instance to do.
+func (o Options) instanceCommand() string {
+	if o.Toggle {
+		return instanceToggle
+	}
+	return instanceShow
+}
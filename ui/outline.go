@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type OutlineKind int
+
+const (
+	OutlineHeading OutlineKind = iota
+	OutlineBookmark
+	OutlineTopic
+)
+
+// OutlineEntry is a navigable point in the transcript.
+type OutlineEntry struct {
+	Kind  OutlineKind
+	Title string
+	Line  int
+	Level int
+}
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	spokenHeadingPattern   = regexp.MustCompile(`(?i)^(?:new\s+)?(heading|section|chapter)[\s:,.-]+(.+?)[.]?$`)
+	bookmarkPattern        = regexp.MustCompile(`(?i)^(?:\w+:\s*)?bookmark[\s:,.-]*(.*?)[.]?$`)
+	outlineWordPattern     = regexp.MustCompile(`[\p{L}\p{N}']+`)
+)
+
+// Topic shift detection compares the vocabulary of the lines before and after
+// each line break (a simplified TextTiling).
+const (
+	topicWindowLines    = 3
+	topicMinWords       = 8
+	topicMinGapLines    = 5
+	topicShiftThreshold = 0.08
+)
+
+var outlineStopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`the and that this with have from they will would there their what about
+		which when were been into more some then than them these just like also very your over such only other could
+		should where while after before being because going really think know yeah okay right well here need want`) {
+		outlineStopwords[word] = true
+	}
+}
+
+// buildOutline extracts headings, bookmarks and topic shifts from the text.
+func buildOutline(text string) []OutlineEntry {
+	lines := strings.Split(text, "\n")
+
+	var entries []OutlineEntry
+	var plain []int // Lines eligible for topic shift detection
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+			entries = append(entries, OutlineEntry{Kind: OutlineHeading, Title: m[2], Line: i, Level: len(m[1])})
+		} else if m := spokenHeadingPattern.FindStringSubmatch(line); m != nil {
+			level := 1
+			if strings.EqualFold(m[1], "section") {
+				level = 2
+			}
+			entries = append(entries, OutlineEntry{Kind: OutlineHeading, Title: m[2], Line: i, Level: level})
+		} else if m := bookmarkPattern.FindStringSubmatch(line); m != nil {
+			title := m[1]
+			if title == "" {
+				title = "Bookmark"
+			}
+			entries = append(entries, OutlineEntry{Kind: OutlineBookmark, Title: title, Line: i})
+		} else {
+			plain = append(plain, i)
+		}
+	}
+
+	entries = append(entries, detectTopicShifts(lines, plain)...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Line < entries[j].Line })
+	return entries
+}
+
+func detectTopicShifts(lines []string, plain []int) []OutlineEntry {
+	var entries []OutlineEntry
+	last := 0
+	for gap := topicWindowLines; gap <= len(plain)-topicWindowLines; gap++ {
+		if gap-last < topicMinGapLines {
+			continue
+		}
+		before := termVector(lines, plain[gap-topicWindowLines:gap])
+		after := termVector(lines, plain[gap:gap+topicWindowLines])
+		if vectorWords(before) < topicMinWords || vectorWords(after) < topicMinWords {
+			continue
+		}
+		if cosineSimilarity(before, after) < topicShiftThreshold {
+			line := plain[gap]
+			entries = append(entries, OutlineEntry{Kind: OutlineTopic, Title: truncateWords(lines[line], 6), Line: line})
+			last = gap
+		}
+	}
+	return entries
+}
+
+func termVector(lines []string, indices []int) map[string]int {
+	vector := make(map[string]int)
+	for _, i := range indices {
+		for _, word := range outlineWordPattern.FindAllString(strings.ToLower(lines[i]), -1) {
+			if len(word) > 3 && !outlineStopwords[word] {
+				vector[word]++
+			}
+		}
+	}
+	return vector
+}
+
+func vectorWords(vector map[string]int) int {
+	n := 0
+	for _, count := range vector {
+		n += count
+	}
+	return n
+}
+
+func cosineSimilarity(a, b map[string]int) float64 {
+	var dot, normA, normB float64
+	for word, count := range a {
+		dot += float64(count * b[word])
+		normA += float64(count * count)
+	}
+	for _, count := range b {
+		normB += float64(count * count)
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+func truncateWords(text string, n int) string {
+	words := strings.Fields(text)
+	if len(words) <= n {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:n], " ") + "…"
+}
+
+func (a *App) newOutlinePane() fyne.CanvasObject {
+	a.outlineList = widget.NewList(
+		func() int { return len(a.outline) },
+		func() fyne.CanvasObject {
+			return container.NewHBox(widget.NewIcon(theme.DocumentIcon()), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := a.outline[id]
+			row := item.(*fyne.Container)
+			icon := row.Objects[0].(*widget.Icon)
+			label := row.Objects[1].(*widget.Label)
+
+			indent := ""
+			switch entry.Kind {
+			case OutlineHeading:
+				icon.SetResource(theme.DocumentIcon())
+				indent = strings.Repeat("  ", max(entry.Level-1, 0))
+			case OutlineBookmark:
+				icon.SetResource(theme.RadioButtonCheckedIcon())
+			case OutlineTopic:
+				icon.SetResource(theme.MoreHorizontalIcon())
+			}
+			label.SetText(indent + entry.Title)
+		},
+	)
+	a.outlineList.OnSelected = func(id widget.ListItemID) {
+		a.outlineList.UnselectAll()
+		if id < len(a.outline) {
+			a.jumpToLine(a.outline[id].Line)
+		}
+	}
+
+	spacer := canvas.NewRectangle(nil)
+	spacer.SetMinSize(fyne.NewSize(180, 0))
+	a.outlinePane = container.NewStack(spacer, a.outlineList)
+	a.outlinePane.Hide()
+	return a.outlinePane
+}
+
+func (a *App) toggleOutline() {
+	if a.outlinePane.Visible() {
+		a.outlinePane.Hide()
+		return
+	}
+	a.outlinePane.Show()
+	a.refreshOutline()
+}
+
+func (a *App) refreshOutline() {
+	if a.outlinePane == nil || !a.outlinePane.Visible() {
+		return
+	}
+	a.outline = buildOutline(a.textArea.Text)
+	a.outlineList.Refresh()
+}
+
+// jumpToLine moves the caret to the start of a transcript line, which
+// scrolls it into view. The live view has no caret, so it is scrolled to the
+// line's approximate position instead.
+func (a *App) jumpToLine(line int) {
+	if a.liveScroll.Visible() {
+		total := len(strings.Split(a.textArea.Text, "\n"))
+		y := a.liveText.Size().Height * float32(line) / float32(max(total, 1))
+		a.liveScroll.ScrollToOffset(fyne.NewPos(0, y))
+		return
+	}
+
+	a.textArea.CursorRow = line
+	a.textArea.CursorColumn = 0
+	a.textArea.Refresh()
+	a.window.Canvas().Focus(a.textArea)
+}
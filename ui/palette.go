@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PaletteCommand is an action listed in the command palette.
+type PaletteCommand struct {
+	Name     string
+	Shortcut string
+	run      func()
+}
+
+// paletteCommands lists every action, including each export format and
+// pipeline, with its current shortcut.
+func (a *App) paletteCommands() []PaletteCommand {
+	cfg := a.settings()
+	var commands []PaletteCommand
+	for _, action := range shortcutActions {
+		run, ok := shortcutHandlers[action.ID]
+		if !ok || action.ID == "palette" {
+			continue
+		}
+		commands = append(commands, PaletteCommand{tr(action.Name), cfg.binding(action.ID).String(), func() { run(a) }})
+	}
+	commands = append(commands,
+		PaletteCommand{Name: tr("Open settings"), run: a.showSettingsModal},
+		PaletteCommand{Name: tr("Export settings"), run: a.showExportSettings},
+		PaletteCommand{Name: tr("Import settings"), run: func() { a.showImportSettings(nil) }},
+		PaletteCommand{Name: tr("Switch between Edit and Live"), run: a.toggleEditMode},
+		PaletteCommand{Name: tr("Pick turns to copy or process"), run: a.showTurnSelection},
+		PaletteCommand{Name: tr("Edit meeting attendees"), run: a.showAttendees},
+		PaletteCommand{Name: tr("Browse history"), run: a.showHistory},
+		PaletteCommand{Name: tr("Show transcript revisions"), run: a.showRevisions},
+		PaletteCommand{Name: tr("Rename the transcript tab"), run: a.renameTab},
+		PaletteCommand{Name: tr("Show or hide the outline"), run: a.toggleOutline},
+		PaletteCommand{Name: tr("Show or hide the turn timeline"), run: a.toggleTimeline},
+		PaletteCommand{Name: tr("Start a dictation sprint"), run: a.showSprintDialog},
+		PaletteCommand{Name: tr("Show usage and costs"), run: a.showUsagePanel},
+		PaletteCommand{Name: tr("Show storage and retention"), run: a.showStorage},
+		PaletteCommand{Name: tr("Back up or import history"), run: func() { a.showBackup(nil) }},
+		PaletteCommand{Name: tr("Write the weekly digest now"), run: a.writeDigestNow},
+		PaletteCommand{Name: tr("Show logs"), run: a.showLogs},
+		PaletteCommand{Name: tr("Replay a recording or message log"), run: a.showReplay},
+		PaletteCommand{Name: tr("Cancel the LLM request"), run: a.cancelLLMTasks},
+		PaletteCommand{Name: tr("Export Anki flashcards"), run: a.exportAnki},
+		PaletteCommand{Name: tr("Edit pipelines"), run: a.editPipelines},
+	)
+	commands = append(commands, menuCommands(trMark("Export as %s"), a.formatMenuItems(a.exportAs))...)
+	pipelines, _ := a.loadPipelines()
+	pipelines = append(pipelines, extractionPipelines...)
+	commands = append(commands, menuCommands(trMark("Run pipeline: %s"), pipelineMenuItems(pipelines, a.startPipeline))...)
+	return commands
+}
+
+// menuCommands lists menu items in the palette, named by a format with the
+// item's label.
+func menuCommands(format string, items []*fyne.MenuItem) []PaletteCommand {
+	var commands []PaletteCommand
+	for _, item := range items {
+		commands = append(commands, PaletteCommand{Name: tr(format, item.Label), run: item.Action})
+	}
+	return commands
+}
+
+// fuzzyScore matches the query's letters in order anywhere in name, and
+// scores matches at word starts and runs of consecutive letters higher. It
+// returns false if the query doesn't match.
+func fuzzyScore(query, name string) (int, bool) {
+	query = strings.ToLower(strings.Join(strings.Fields(query), ""))
+	if query == "" {
+		return 0, true
+	}
+	target := []rune(strings.ToLower(name))
+	score, last := 0, -2
+	i := 0
+	for _, q := range query {
+		for i < len(target) && target[i] != q {
+			i++
+		}
+		if i == len(target) {
+			return 0, false
+		}
+		switch {
+		case i == last+1:
+			score += 3
+		case i == 0 || !unicode.IsLetter(target[i-1]):
+			score += 2
+		default:
+			score++
+		}
+		last = i
+		i++
+	}
+	// Prefer shorter names when scores tie, e.g. "copy" over "copy selected"
+	return score*100 - len(target), true
+}
+
+// filterPalette returns the commands matching the query, best first.
+func filterPalette(commands []PaletteCommand, query string) []PaletteCommand {
+	type scored struct {
+		cmd   PaletteCommand
+		score int
+	}
+	var matches []scored
+	for _, cmd := range commands {
+		if score, ok := fuzzyScore(query, cmd.Name); ok {
+			matches = append(matches, scored{cmd, score})
+		}
+	}
+	if strings.TrimSpace(query) != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+	filtered := make([]PaletteCommand, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.cmd
+	}
+	return filtered
+}
+
+// PaletteEntry is the palette's search box. Up and Down move through the
+// results while the box keeps focus.
+type PaletteEntry struct {
+	widget.Entry
+	onMove func(delta int)
+	onEsc  func()
+}
+
+func newPaletteEntry() *PaletteEntry {
+	e := &PaletteEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *PaletteEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	case fyne.KeyEscape:
+		e.onEsc()
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// showCommandPalette lists every action with fuzzy search; Enter runs the
+// selected one.
+func (a *App) showCommandPalette() {
+	commands := a.paletteCommands()
+	shown := commands
+	selected := 0
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			shortcut := widget.NewLabel("")
+			shortcut.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, shortcut, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(shown[id].Name)
+			row.Objects[1].(*widget.Label).SetText(shown[id].Shortcut)
+		},
+	)
+	search := newPaletteEntry()
+	search.SetPlaceHolder(tr("Type a command, e.g. \"exp md\" or \"translate\""))
+
+	var d dialog.Dialog
+	run := func(id int) {
+		if id < 0 || id >= len(shown) {
+			return
+		}
+		cmd := shown[id]
+		d.Hide()
+		cmd.run()
+	}
+	selectRow := func(id int) {
+		if len(shown) == 0 {
+			return
+		}
+		selected = min(max(id, 0), len(shown)-1)
+		list.Select(selected)
+		list.ScrollTo(selected)
+	}
+	search.OnChanged = func(query string) {
+		shown = filterPalette(commands, query)
+		list.UnselectAll()
+		list.Refresh()
+		selectRow(0)
+	}
+	search.OnSubmitted = func(string) { run(selected) }
+	search.onMove = func(delta int) { selectRow(selected + delta) }
+	search.onEsc = func() { d.Hide() }
+	list.OnSelected = func(id widget.ListItemID) {
+		if id == selected {
+			return
+		}
+		// Clicked rather than moved to with the arrow keys
+		run(id)
+	}
+
+	content := container.NewBorder(search, nil, nil, nil, list)
+	d = dialog.NewCustom(tr("Command Palette"), tr("Close"), content, a.window)
+	d.Resize(fyne.NewSize(480, 420))
+	d.Show()
+	selectRow(0)
+	a.window.Canvas().Focus(search)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMenuCommandsRunTheirItem(t *testing.T) {
+	pipelines := []Pipeline{{Name: "Clean up"}, {Name: "Summarize"}}
+	var ran []string
+	commands := menuCommands("Run pipeline: %s", pipelineMenuItems(pipelines, func(p Pipeline) { ran = append(ran, p.Name) }))
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.Name)
+		c.run()
+	}
+	if want := []string{"Run pipeline: Clean up", "Run pipeline: Summarize"}; !slices.Equal(names, want) {
+		t.Errorf("commands = %v, want %v", names, want)
+	}
+	if want := []string{"Clean up", "Summarize"}; !slices.Equal(ran, want) {
+		t.Errorf("commands ran %v, want %v", ran, want)
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	for _, tt := range []struct {
+		query, name string
+		match       bool
+	}{
+		{"", "Anything", true},
+		{"exp md", "Export as Markdown", true},
+		{"EXPORT", "Export as Markdown", true},
+		{"xyz", "Export as Markdown", false},
+		{"mdx", "Export as Markdown", false},
+	} {
+		if _, ok := fuzzyScore(tt.query, tt.name); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.name, ok, tt.match)
+		}
+	}
+	// Word starts and runs score higher than scattered letters
+	start, _ := fuzzyScore("em", "Export as Markdown")
+	scattered, _ := fuzzyScore("xo", "Export as Markdown")
+	if start <= scattered {
+		t.Errorf("word start match scored %d, scattered %d", start, scattered)
+	}
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Turn separators
+const (
+	separatorSpace     = "space"
+	separatorNewline   = "newline"
+	separatorBlankLine = "blank_line"
+)
+
+var turnSeparators = map[string]string{
+	separatorSpace:     " ",
+	separatorNewline:   "\n",
+	separatorBlankLine: "\n\n",
+}
+
+var separatorLabels = map[string]string{
+	separatorSpace:     trMark("Space"),
+	separatorNewline:   trMark("New line"),
+	separatorBlankLine: trMark("Blank line"),
+}
+
+// turnSeparator returns what goes between prev and next: a paragraph break
+// after a long enough pause, otherwise the configured separator. Turns with
+// speaker labels always start a line.
+func turnSeparator(cfg *Settings, prev *Turn, next Turn) string {
+	if prev != nil && cfg.ParagraphPause > 0 && !prev.End.IsZero() && !next.Start.IsZero() &&
+		next.Start.Sub(prev.End) >= time.Duration(cfg.ParagraphPause)*time.Second {
+		return "\n\n"
+	}
+	sep, ok := turnSeparators[cfg.TurnSeparator]
+	if !ok {
+		sep = "\n"
+	}
+	if sep == " " && (next.Speaker != "" || prev != nil && prev.Speaker != "") {
+		return "\n"
+	}
+	return sep
+}
+
+// joinTurns renders turns as they're appended to text, each with the
+// separator that goes before it. prev is the turn text ends with, if any.
+func joinTurns(cfg *Settings, text string, prev *Turn, turns []Turn) []string {
+	var pieces []string
+	for i := range turns {
+		turn := turns[i]
+		if turn.Text == "" {
+			continue
+		}
+		sep := ""
+		if text != "" {
+			sep = turnSeparator(cfg, prev, turn)
+		}
+		body := turn.display()
+		if cfg.SmartJoin && turn.Speaker == "" {
+			sep, body = smartJoin(text, sep, body)
+		}
+		pieces = append(pieces, sep+body)
+		text += sep + body
+		prev = &turns[i]
+	}
+	return pieces
+}
+
+// smartJoin fixes a turn's spacing and capitalization for where it lands.
+// Turns start a sentence with a capital; one continuing a sentence on the
+// same line starts lower case, unless its first word looks like "I" or an
+// acronym. Leading punctuation attaches to the previous word.
+func smartJoin(text, sep, body string) (string, string) {
+	if sep == " " && strings.ContainsAny(body[:1], ",.;:!?") {
+		sep = ""
+	}
+	trimmed := strings.TrimRightFunc(text, unicode.IsSpace)
+	r, size := utf8.DecodeRuneInString(body)
+	switch {
+	case trimmed == "" || strings.ContainsRune(".!?…", lastRune(trimmed)):
+		body = string(unicode.ToUpper(r)) + body[size:]
+	case sep == " " && !keepsCapital(body):
+		body = string(unicode.ToLower(r)) + body[size:]
+	}
+	return sep, body
+}
+
+func keepsCapital(text string) bool {
+	word := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' })
+	if len(word) == 0 {
+		return true
+	}
+	first := word[0]
+	if first == "I" || strings.HasPrefix(first, "I'") {
+		return true
+	}
+	return utf8.RuneCountInString(first) > 1 && strings.ToUpper(first) == first
+}
+
+// newParagraphSettings is the turn joining part of the settings dialog.
+func newParagraphSettings(cfg *Settings) (fyne.CanvasObject, func(s *Settings), func() error) {
+	ids := []string{separatorSpace, separatorNewline, separatorBlankLine}
+	var labels []string
+	for _, id := range ids {
+		labels = append(labels, tr(separatorLabels[id]))
+	}
+	separatorRadio := widget.NewRadioGroup(labels, nil)
+	separatorRadio.Horizontal = true
+	separatorRadio.SetSelected(tr(separatorLabels[separatorNewline]))
+	if label, ok := separatorLabels[cfg.TurnSeparator]; ok {
+		separatorRadio.SetSelected(tr(label))
+	}
+	pauseEntry := widget.NewEntry()
+	pauseEntry.SetPlaceHolder(tr("Off"))
+	if cfg.ParagraphPause > 0 {
+		pauseEntry.SetText(strconv.Itoa(cfg.ParagraphPause))
+	}
+	smartCheck := widget.NewCheck(tr("Smart joining: fix spacing and capitals where turns meet mid-sentence"), nil)
+	smartCheck.SetChecked(cfg.SmartJoin)
+
+	content := container.NewVBox(
+		container.NewHBox(widget.NewLabel(tr("Between turns:")), separatorRadio),
+		container.NewBorder(nil, nil, widget.NewLabel(tr("New paragraph after a pause of (seconds):")), nil, pauseEntry),
+		smartCheck,
+	)
+	read := func(s *Settings) {
+		for _, id := range ids {
+			if tr(separatorLabels[id]) == separatorRadio.Selected {
+				s.TurnSeparator = id
+			}
+		}
+		s.ParagraphPause, _ = strconv.Atoi(strings.TrimSpace(pauseEntry.Text))
+		s.SmartJoin = smartCheck.Checked
+	}
+	validate := func() error {
+		if text := strings.TrimSpace(pauseEntry.Text); text != "" {
+			if pause, err := strconv.Atoi(text); err != nil || pause < 0 {
+				return fmt.Errorf("The paragraph pause must be a whole number of seconds")
+			}
+		}
+		return nil
+	}
+	return content, read, validate
+}
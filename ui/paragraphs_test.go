@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTurnSeparator(t *testing.T) {
+	start := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	turn := func(speaker string, from, to int) *Turn {
+		return &Turn{Speaker: speaker, Start: start.Add(time.Duration(from) * time.Second), End: start.Add(time.Duration(to) * time.Second)}
+	}
+	for _, tt := range []struct {
+		name      string
+		separator string
+		pause     int
+		prev      *Turn
+		next      Turn
+		want      string
+	}{
+		{"default", "", 0, nil, Turn{}, "\n"},
+		{"space", separatorSpace, 0, &Turn{}, Turn{}, " "},
+		{"blank line", separatorBlankLine, 0, &Turn{}, Turn{}, "\n\n"},
+		{"short pause", separatorSpace, 5, turn("", 0, 2), *turn("", 4, 6), " "},
+		{"long pause", separatorSpace, 5, turn("", 0, 2), *turn("", 7, 9), "\n\n"},
+		{"no timing", separatorSpace, 5, &Turn{}, Turn{}, " "},
+		{"speaker", separatorSpace, 0, &Turn{}, *turn("Ana", 0, 1), "\n"},
+		{"after speaker", separatorSpace, 0, turn("Ana", 0, 1), Turn{}, "\n"},
+	} {
+		cfg := &Settings{TurnSeparator: tt.separator, ParagraphPause: tt.pause}
+		if got := turnSeparator(cfg, tt.prev, tt.next); got != tt.want {
+			t.Errorf("%s: separator = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSmartJoin(t *testing.T) {
+	for _, tt := range []struct {
+		text, sep, body   string
+		wantSep, wantBody string
+	}{
+		{"", "", "hello there", "", "Hello there"},
+		{"It works.", " ", "and then", " ", "And then"},
+		{"I went", " ", "To the shop", " ", "to the shop"},
+		{"I went", "\n", "To the shop", "\n", "To the shop"},
+		{"Yes", " ", ", I did.", "", ", I did."},
+		{"We use", " ", "NASA data", " ", "NASA data"},
+		{"Then", " ", "I'll go", " ", "I'll go"},
+		{"Was it good?  ", " ", "yes", " ", "Yes"},
+	} {
+		sep, body := smartJoin(tt.text, tt.sep, tt.body)
+		if sep != tt.wantSep || body != tt.wantBody {
+			t.Errorf("smartJoin(%q, %q, %q) = %q, %q, want %q, %q", tt.text, tt.sep, tt.body, sep, body, tt.wantSep, tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var phraseWordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// PhraseStripper removes trigger phrases (wake words, voice commands) from
+// transcript text. Phrases are matched word by word, ignoring case and
+// punctuation, and may be split across two consecutive turns.
+type PhraseStripper struct {
+	phrases [][]string
+}
+
+type phraseWord struct {
+	norm       string
+	start, end int
+}
+
+func newPhraseStripper(phrases []string) *PhraseStripper {
+	ps := &PhraseStripper{}
+	for _, phrase := range phrases {
+		var words []string
+		for _, w := range splitPhraseWords(phrase) {
+			words = append(words, w.norm)
+		}
+		if len(words) > 0 {
+			ps.phrases = append(ps.phrases, words)
+		}
+	}
+	return ps
+}
+
+func splitPhraseWords(text string) []phraseWord {
+	var words []phraseWord
+	for _, loc := range phraseWordPattern.FindAllStringIndex(text, -1) {
+		words = append(words, phraseWord{
+			norm:  strings.ToLower(text[loc[0]:loc[1]]),
+			start: loc[0],
+			end:   loc[1],
+		})
+	}
+	return words
+}
+
+func wordsMatch(words []phraseWord, phrase []string) bool {
+	if len(words) != len(phrase) {
+		return false
+	}
+	for i := range words {
+		if words[i].norm != phrase[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// strip removes every complete phrase occurrence from text.
+func (ps *PhraseStripper) strip(text string) string {
+	if len(ps.phrases) == 0 {
+		return text
+	}
+
+	for changed := true; changed; {
+		changed = false
+		words := splitPhraseWords(text)
+	search:
+		for i := range words {
+			for _, phrase := range ps.phrases {
+				if i+len(phrase) <= len(words) && wordsMatch(words[i:i+len(phrase)], phrase) {
+					text = cleanupStripped(text[:words[i].start] + skipPunctuation(text[words[i+len(phrase)-1].end:]))
+					changed = true
+					break search
+				}
+			}
+		}
+	}
+	return text
+}
+
+// stripBoundary removes a phrase that starts at the end of prev and finishes
+// at the beginning of next.
+func (ps *PhraseStripper) stripBoundary(prev, next string) (string, string, bool) {
+	prevWords := splitPhraseWords(prev)
+	nextWords := splitPhraseWords(next)
+	for _, phrase := range ps.phrases {
+		for k := 1; k < len(phrase); k++ {
+			if k > len(prevWords) || len(phrase)-k > len(nextWords) {
+				continue
+			}
+			if !wordsMatch(prevWords[len(prevWords)-k:], phrase[:k]) || !wordsMatch(nextWords[:len(phrase)-k], phrase[k:]) {
+				continue
+			}
+			prev = cleanupStripped(prev[:prevWords[len(prevWords)-k].start])
+			next = cleanupStripped(skipPunctuation(next[nextWords[len(phrase)-k-1].end:]))
+			return prev, next, true
+		}
+	}
+	return prev, next, false
+}
+
+// skipPunctuation drops the punctuation that followed a removed phrase.
+func skipPunctuation(text string) string {
+	return strings.TrimLeftFunc(text, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '"' && r != '(' && r != '\''
+	})
+}
+
+func cleanupStripped(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.TrimLeftFunc(text, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSpace(r) })
+	text = strings.TrimRight(text, " ,;:")
+	if r, size := utf8.DecodeRuneInString(text); r != utf8.RuneError && unicode.IsLower(r) {
+		text = string(unicode.ToUpper(r)) + text[size:]
+	}
+	return text
+}
+
+// stripPhrasesList returns the user-configured phrases plus those registered
+// by voice control features.
+func (a *App) stripPhrasesList() []string {
+	cfg := a.settings()
+	phrases := parseKeywords(cfg.StripPhrases)
+	if cfg.HandsFree && cfg.SleepPhrase != "" {
+		phrases = append(phrases, cfg.SleepPhrase)
+	}
+	if phrase := wakePhrase(cfg.WakeWord); cfg.HandsFree && cfg.PreRoll > 0 && phrase != "" {
+		// The pre-roll usually catches the wake word itself
+		phrases = append(phrases, phrase)
+	}
+	return phrases
+}
+
+// stripTurnPhrases removes trigger phrases from a finalized turn, including a
+// phrase split between the previous turn of the same stream and this one.
+// Caller must hold a.mu.
+func (a *App) stripTurnPhrases(st *Stream, order int, text string) string {
+	ps := newPhraseStripper(a.stripPhrasesList())
+	if len(ps.phrases) == 0 {
+		return text
+	}
+
+	text = ps.strip(text)
+	for i := len(a.turns) - 1; i >= 0; i-- {
+		prev := &a.turns[i]
+		if prev.Session != st.session || prev.Stream != st.index || prev.Order >= order {
+			continue
+		}
+		if stripped, next, ok := ps.stripBoundary(prev.Text, text); ok {
+			prev.Text = stripped
+			text = next
+		}
+		break
+	}
+	return text
+}
@@ -0,0 +1,44 @@
+package ui
+
+import "testing"
+
+func TestStripPhrases(t *testing.T) {
+	ps := newPhraseStripper([]string{"new paragraph", "Stop recording."})
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{"Hello world", "Hello world"},
+		{"New paragraph", ""},
+		{"Hello new paragraph world", "Hello world"},
+		{"Hello new paragraph, world", "Hello world"},
+		{"NEW PARAGRAPH next", "Next"},
+		{"That's all. Stop recording", "That's all."},
+		{"new paragraph new paragraph done", "Done"},
+		// Only whole words match
+		{"renew paragraphs", "renew paragraphs"},
+	} {
+		if got := ps.strip(tt.text); got != tt.want {
+			t.Errorf("strip(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestStripPhraseAcrossTurns(t *testing.T) {
+	ps := newPhraseStripper([]string{"stop recording now"})
+	for _, tt := range []struct {
+		prev, next         string
+		wantPrev, wantNext string
+		stripped           bool
+	}{
+		{"That's it, stop", "recording now.", "That's it", "", true},
+		{"That's it, stop recording", "now. Thanks", "That's it", "Thanks", true},
+		{"Please stop", "the music", "Please stop", "the music", false},
+		{"Stop recording now", "Next", "Stop recording now", "Next", false},
+	} {
+		prev, next, stripped := ps.stripBoundary(tt.prev, tt.next)
+		if prev != tt.wantPrev || next != tt.wantNext || stripped != tt.stripped {
+			t.Errorf("stripBoundary(%q, %q) = %q, %q, %v, want %q, %q, %v",
+				tt.prev, tt.next, prev, next, stripped, tt.wantPrev, tt.wantNext, tt.stripped)
+		}
+	}
+}
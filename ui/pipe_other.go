@@ -0,0 +1,14 @@
+//go:build !windows
+
+package ui
+
+import (
+	"os"
+	"syscall"
+)
+
+// openPipe opens a named pipe for writing without waiting for a reader; with
+// none it fails with ENXIO.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|syscall.O_NONBLOCK, 0)
+}
@@ -0,0 +1,9 @@
+package ui
+
+import "os"
+
+// openPipe opens a named pipe such as \\.\pipe\dictation for writing. Opening
+// a Windows pipe with no server fails straight away.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
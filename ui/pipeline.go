@@ -0,0 +1,294 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+	"dict/llm"
+)
+
+// Pipeline is an ordered list of prompt steps where each step's output is
+// the next step's input.
+type Pipeline struct {
+	Name  string         `json:"name"`
+	Steps []PipelineStep `json:"steps"`
+}
+
+// PipelineStep runs one system prompt. An empty Model uses the configured
+// one, and parameters left unset use the settings' values. Raw keeps the
+// reply as the model wrote it, for steps whose output isn't plain text.
+type PipelineStep struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt"`
+	Model  string `json:"model,omitempty"`
+	Raw    bool   `json:"raw,omitempty"`
+	llm.Params
+
+	// A JSON Schema the reply must match, for extracting structured data
+	Schema json.RawMessage `json:"schema,omitempty"`
+}
+
+// StepResult is the output of one step of a pipeline run.
+type StepResult struct {
+	Step     PipelineStep
+	Output   string
+	Duration time.Duration
+	Data     any // The decoded reply of a step with a schema, whose Output is its JSON
+}
+
+// text is the result as it goes in the transcript, with structured replies
+// rendered as Markdown.
+func (r StepResult) text() string {
+	if r.Data == nil {
+		return r.Output
+	}
+	s, _ := parseSchema(r.Step.Schema)
+	return structuredMarkdown(r.Data, s)
+}
+
+const examplePipelines = `[
+  {
+    "name": "Clean up and summarize",
+    "steps": [
+      {"name": "Clean up", "prompt": "Fix punctuation and remove filler words. Return only the text.", "temperature": 0},
+      {"name": "Summarize", "prompt": "Summarize this as bullet points.", "model": "llama-3.1-8b-instant"}
+    ]
+  }
+]`
+
+func (a *App) getPipelinesPath() string {
+	return filepath.Join(a.getConfigDir(), "pipelines.json")
+}
+
+func parsePipelines(data []byte) ([]Pipeline, error) {
+	var pipelines []Pipeline
+	if err := json.Unmarshal(data, &pipelines); err != nil {
+		return nil, fmt.Errorf("failed to parse pipelines: %v", err)
+	}
+	for _, p := range pipelines {
+		if p.Name == "" || len(p.Steps) == 0 {
+			return nil, fmt.Errorf("every pipeline needs a name and at least one step")
+		}
+		for _, step := range p.Steps {
+			if step.Prompt == "" {
+				return nil, fmt.Errorf("pipeline %q has a step without a prompt", p.Name)
+			}
+			if len(step.Schema) > 0 {
+				if _, err := parseSchema(step.Schema); err != nil {
+					return nil, fmt.Errorf("pipeline %q step %q: %v", p.Name, step.Name, err)
+				}
+			}
+		}
+	}
+	return pipelines, nil
+}
+
+func (a *App) loadPipelines() ([]Pipeline, error) {
+	data, err := os.ReadFile(a.getPipelinesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipelines: %v", err)
+	}
+	return parsePipelines(data)
+}
+
+// runPipeline runs each step in order, calling progress before each one.
+// It stops at the first failing step, returning the results so far.
+func (a *App) runPipeline(ctx context.Context, p Pipeline, input string, vars PromptContext, progress func(step int)) ([]StepResult, error) {
+	var results []StepResult
+	text := input
+	for i, step := range p.Steps {
+		progress(i)
+		model := step.Model
+		if model == "" {
+			model = a.settings().GroqModel
+		}
+		vars.Text = text
+		started := time.Now()
+		params := a.settings().LLMParams.Override(step.Params)
+		var output string
+		var data any
+		var err error
+		if len(step.Schema) > 0 {
+			data, output, err = a.callStructured(ctx, model, params, expandPrompt(step.Prompt, vars), text, step.Name, step.Schema)
+		} else {
+			output, err = a.callGroqModel(ctx, model, params, expandPrompt(step.Prompt, vars), text)
+			if err == nil && !step.Raw {
+				output = a.cleanLLMReply(output, text)
+			}
+		}
+		if err != nil {
+			return results, fmt.Errorf("step %d (%s) failed: %v", i+1, step.Name, err)
+		}
+		results = append(results, StepResult{Step: step, Output: output, Duration: time.Since(started), Data: data})
+		slog.Info("pipeline step finished", "pipeline", p.Name, "step", step.Name, "model", model, "duration", time.Since(started))
+		text = output
+	}
+	return results, nil
+}
+
+func (a *App) showPipelineMenu() {
+	pipelines, err := a.loadPipelines()
+	if err != nil {
+		dialog.ShowError(err, a.window)
+	}
+
+	items := pipelineMenuItems(pipelines, a.startPipeline)
+	if len(items) > 0 {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+	var extractItems []*fyne.MenuItem
+	for _, p := range extractionPipelines {
+		p := p
+		extractItems = append(extractItems, fyne.NewMenuItem(p.Steps[0].Name, func() { a.startPipeline(p) }))
+	}
+	extract := fyne.NewMenuItem(tr("Extract"), nil)
+	extract.ChildMenu = fyne.NewMenu("", extractItems...)
+	items = append(items, extract)
+	items = append(items, fyne.NewMenuItem(tr("Compare Models (A/B)"), a.compareModels))
+	items = append(items, fyne.NewMenuItem(tr("Edit Pipelines..."), a.editPipelines))
+
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(a.pipelineBtn)
+	pos = pos.Add(fyne.NewPos(0, a.pipelineBtn.Size().Height))
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), a.window.Canvas(), pos)
+}
+
+// pipelineMenuItems has an item per pipeline, which run is called with.
+func pipelineMenuItems(pipelines []Pipeline, run func(p Pipeline)) []*fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, p := range pipelines {
+		p := p
+		items = append(items, fyne.NewMenuItem(p.Name, func() { run(p) }))
+	}
+	return items
+}
+
+// startPipeline runs a pipeline over the transcript and replaces it with the
+// final output. Every step's output is shown in the results window.
+func (a *App) startPipeline(p Pipeline) {
+	if a.settings().GroqAPIKey == "" {
+		a.showError(&apierr.AuthError{Service: "Groq", Err: errors.New("Please configure Groq API key in Settings")})
+		return
+	}
+	text := a.textArea.Text
+	if text == "" {
+		a.updateStatus(tr("No text to process"))
+		return
+	}
+
+	a.previousText = text
+	a.pipelineBtn.Disable()
+	vars := a.promptContext(text)
+	ctx, done := a.startLLMTask()
+
+	go func() {
+		results, err := a.runPipeline(ctx, p, text, vars, func(step int) {
+			fyne.Do(func() {
+				a.updateStatus(tr("%s: step %d of %d (%s)...", p.Name, step+1, len(p.Steps), p.Steps[step].Name))
+			})
+		})
+		fyne.Do(func() {
+			done()
+			a.pipelineBtn.Enable()
+			if err != nil {
+				if err := a.llmError(err); err == nil {
+					a.updateStatus(tr("%s cancelled", p.Name))
+				} else {
+					a.updateStatus(tr("Pipeline failed: %s", err))
+					a.showError(err)
+				}
+			} else {
+				a.textArea.SetText(results[len(results)-1].text())
+				a.undoBtn.Enable()
+				a.addRevision(p.Name)
+				a.rememberClip(p.Name, a.textArea.Text)
+				a.reportResult(tr("%s finished", p.Name))
+			}
+			if len(results) > 0 {
+				a.showPipelineResults(p, text, results)
+			}
+		})
+	}()
+}
+
+// showPipelineResults shows the input and each step's output, expandable.
+func (a *App) showPipelineResults(p Pipeline, input string, results []StepResult) {
+	resultEntry := func(text string) *TextArea {
+		entry := newTextArea()
+		entry.Wrapping = fyne.TextWrapWord
+		entry.SetText(text)
+		entry.SetMinRowsVisible(6)
+		return entry
+	}
+
+	accordion := widget.NewAccordion(widget.NewAccordionItem(tr("Input"), resultEntry(input)))
+	for i, r := range results {
+		title := fmt.Sprintf("%d. %s (%.1fs)", i+1, r.Step.Name, r.Duration.Seconds())
+		if r.Step.Model != "" {
+			title += " — " + r.Step.Model
+		}
+		if r.Data != nil {
+			s, _ := parseSchema(r.Step.Schema)
+			accordion.Append(widget.NewAccordionItem(title, structuredView(r.Data, s)))
+			accordion.Append(widget.NewAccordionItem(tr("%s — JSON", title), resultEntry(r.Output)))
+			continue
+		}
+		accordion.Append(widget.NewAccordionItem(title, resultEntry(r.Output)))
+	}
+	accordion.Open(len(accordion.Items) - 1)
+
+	w := a.fyneApp.NewWindow(p.Name)
+	w.SetContent(container.NewVScroll(accordion))
+	w.Resize(fyne.NewSize(600, 500))
+	w.Show()
+}
+
+// editPipelines edits the pipelines file as JSON.
+func (a *App) editPipelines() {
+	data, err := os.ReadFile(a.getPipelinesPath())
+	if err != nil {
+		data = []byte(examplePipelines)
+	}
+	editor := newTextArea()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.SetText(string(data))
+
+	var editDialog dialog.Dialog
+	saveBtn := widget.NewButtonWithIcon(tr("Save"), theme.DocumentSaveIcon(), func() {
+		if _, err := parsePipelines([]byte(editor.Text)); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if err := os.MkdirAll(a.getConfigDir(), 0755); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if err := os.WriteFile(a.getPipelinesPath(), []byte(editor.Text), 0644); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save pipelines: %v", err), a.window)
+			return
+		}
+		editDialog.Hide()
+	})
+
+	help := widget.NewLabel(tr("Each step's output is the next step's input. Prompts can use the system prompt variables; \"model\", \"temperature\", \"max_tokens\", \"top_p\", \"raw\" (keep the reply exactly as written) and \"schema\" (a JSON Schema the reply must match) are optional."))
+	help.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(help, saveBtn, nil, nil, editor)
+	editDialog = dialog.NewCustom(tr("Pipelines"), tr("Cancel"), content, a.window)
+	editDialog.Resize(fyne.NewSize(640, 480))
+	editDialog.Show()
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPipelineMenuItemsRunTheirPipeline(t *testing.T) {
+	pipelines := []Pipeline{{Name: "Clean up"}, {Name: "Summarize"}, {Name: "Translate"}}
+	var labels, ran []string
+	for _, item := range pipelineMenuItems(pipelines, func(p Pipeline) { ran = append(ran, p.Name) }) {
+		labels = append(labels, item.Label)
+		item.Action()
+	}
+	want := []string{"Clean up", "Summarize", "Translate"}
+	if !slices.Equal(labels, want) || !slices.Equal(ran, want) {
+		t.Errorf("menu items %v ran %v, want %v", labels, ran, want)
+	}
+}
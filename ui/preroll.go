@@ -0,0 +1,23 @@
+package ui
+
+import "dict/audio"
+
+const (
+	defaultPreRoll    = 1500 // Milliseconds
+	maxPreRoll        = 2000
+	maxPendingAudio   = audio.SampleRate * 2 * 10 // Bytes held while connecting: 10 seconds
+	pendingChunkBytes = audio.SampleRate * 2 / 10 // Sent in 100 ms chunks, which the service accepts
+)
+
+// preRollBytes is the size of a pre-roll of millis milliseconds.
+func preRollBytes(millis int) int {
+	return audio.SampleRate * 2 * millis / 1000
+}
+
+// takeWakeAudio returns the audio from just before and during the wake word
+// for the recording it starts, once. Caller must hold a.mu.
+func (a *App) takeWakeAudio() []byte {
+	pcm := a.wakeAudio
+	a.wakeAudio = nil
+	return pcm
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// profaneWords are masked when the profanity filter is on. AssemblyAI only
+// filters profanity for pre-recorded audio, so streaming turns are masked
+// here instead.
+var profaneWords = []string{
+	"fuck", "fucks", "fucked", "fucker", "fuckers", "fucking", "motherfucker", "motherfucking",
+	"shit", "shits", "shitty", "bullshit", "horseshit",
+	"bitch", "bitches", "bitching", "bastard", "bastards",
+	"asshole", "assholes", "arsehole", "arseholes", "dickhead", "dickheads",
+	"cunt", "cunts", "twat", "twats", "wanker", "wankers", "prick", "pricks",
+	"bollocks", "goddamn", "goddamned", "piss", "pissed", "cock", "cocks",
+	"slut", "sluts", "whore", "whores",
+}
+
+var profanityPattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(profaneWords, "|") + `)\b`)
+
+// maskProfanity keeps the first letter of each profane word and stars the
+// rest, as in "f***", so the sentence still reads naturally.
+func maskProfanity(text string) string {
+	return profanityPattern.ReplaceAllStringFunc(text, func(word string) string {
+		_, size := utf8.DecodeRuneInString(word)
+		return word[:size] + strings.Repeat("*", utf8.RuneCountInString(word)-1)
+	})
+}
+
+// cleanTurnText applies the session's redaction and profanity filter to
+// dictated text, final if the recognizer has ended the turn. Caller must
+// hold a.mu.
+func (a *App) cleanTurnText(text string, final bool) string {
+	text = a.redactor.redact(text, final)
+	if a.sessionCfg.FilterProfanity {
+		text = maskProfanity(text)
+	}
+	return text
+}
@@ -0,0 +1,21 @@
+package ui
+
+import "testing"
+
+func TestMaskProfanity(t *testing.T) {
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{"What the fuck?", "What the f***?"},
+		{"Shit, SHIT and Bullshit", "S***, S*** and B*******"},
+		{"He's a wanker.", "He's a w*****."},
+		// Only whole words are masked
+		{"Scunthorpe, cockpit and assessment", "Scunthorpe, cockpit and assessment"},
+		{"Pass the peacock", "Pass the peacock"},
+		{"Nothing to see here", "Nothing to see here"},
+	} {
+		if got := maskProfanity(tt.text); got != tt.want {
+			t.Errorf("maskProfanity(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultLanguage = "en"
+
+var (
+	promptVariablePattern   = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+	languageVariablePattern = regexp.MustCompile(`\{\{\s*language\s*\}\}`)
+)
+
+// PromptContext holds the values substituted for variables such as {{date}}
+// in system prompts.
+type PromptContext struct {
+	Now       time.Time
+	Text      string // The text being processed
+	Selection string
+	Clipboard string
+	Language  string
+	Title     string
+	Attendees []string
+
+	MatchLanguage bool // Ask for output in Language when it isn't English
+}
+
+func (c PromptContext) variable(name string) (string, bool) {
+	switch name {
+	case "date":
+		return c.Now.Format("2006-01-02"), true
+	case "time":
+		return c.Now.Format("15:04"), true
+	case "language":
+		return c.Language, true
+	case "wordcount":
+		return strconv.Itoa(countWords(c.Text)), true
+	case "clipboard":
+		return c.Clipboard, true
+	case "selection":
+		return c.Selection, true
+	case "title":
+		return c.Title, true
+	case "attendees":
+		return strings.Join(c.Attendees, ", "), true
+	}
+	return "", false
+}
+
+// expandPrompt replaces known variables in prompt. Unknown ones are left as
+// written.
+func expandPrompt(prompt string, c PromptContext) string {
+	// Prompts that use {{language}} already say what language to write in
+	usesLanguage := languageVariablePattern.MatchString(prompt)
+	prompt = promptVariablePattern.ReplaceAllStringFunc(prompt, func(match string) string {
+		name := promptVariablePattern.FindStringSubmatch(match)[1]
+		if value, ok := c.variable(name); ok {
+			return value
+		}
+		return match
+	})
+	if c.MatchLanguage && !usesLanguage && c.Language != defaultLanguage {
+		name := languageName(c.Language)
+		prompt += fmt.Sprintf("\n\nThe text is in %s. Write your output in %s.", name, name)
+	}
+	return prompt
+}
+
+// promptContext captures the variables for processing text. Call it on the
+// UI thread.
+func (a *App) promptContext(text string) PromptContext {
+	a.mu.RLock()
+	language, title, attendees := a.language, a.sessionTitle, a.sessionAttendees
+	a.mu.RUnlock()
+	if language == "" {
+		language = defaultLanguage
+	}
+	return PromptContext{
+		Now:       time.Now(),
+		Text:      text,
+		Selection: a.textArea.SelectedText(),
+		Clipboard: a.window.Clipboard().Content(),
+		Language:  language,
+		Title:     title,
+		Attendees: attendees,
+
+		MatchLanguage: a.settings().AutoLanguage,
+	}
+}
@@ -0,0 +1,473 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+const (
+	defaultReadAloudRate = 175 // Words per minute
+	minReadAloudRate     = 80
+	maxReadAloudRate     = 400
+	readAloudTick        = 40 * time.Millisecond
+)
+
+var (
+	sentenceBreakPattern = regexp.MustCompile(`[.!?]+["')\]]*\s+|\n+`)
+	proofWordPattern     = regexp.MustCompile(`\S+`)
+)
+
+// ReadAloud is a read-aloud pass over the transcript. Pausing cancels the
+// speech; resuming synthesizes again from the word it stopped at.
+type ReadAloud struct {
+	cancel context.CancelFunc
+	text   string // The transcript being read
+	word   [2]int // Byte range of the word being read
+	paused bool
+}
+
+// sentenceSpans splits text from offset into sentences, as byte ranges
+// without surrounding whitespace.
+func sentenceSpans(text string, offset int) [][2]int {
+	var spans [][2]int
+	start := offset
+	add := func(end int) {
+		s := text[start:end]
+		from := start + len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+		to := start + len(strings.TrimRightFunc(s, unicode.IsSpace))
+		if from < to {
+			spans = append(spans, [2]int{from, to})
+		}
+	}
+	for _, m := range sentenceBreakPattern.FindAllStringIndex(text[offset:], -1) {
+		add(offset + m[1])
+		start = offset + m[1]
+	}
+	add(len(text))
+	return spans
+}
+
+// wordAt estimates which word is being spoken once fraction of a sentence's
+// audio has played, giving longer words more time.
+func wordAt(words [][]int, fraction float64) int {
+	total := 0
+	for _, w := range words {
+		total += w[1] - w[0] + 1
+	}
+	target := fraction * float64(total)
+	sum := 0
+	for i, w := range words {
+		sum += w[1] - w[0] + 1
+		if float64(sum) >= target {
+			return i
+		}
+	}
+	return len(words) - 1
+}
+
+// speechCommand returns the system text-to-speech command, which reads text
+// from stdin and writes a WAV file to path.
+func speechCommand(ctx context.Context, path string, wpm int) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "say", "-r", strconv.Itoa(wpm), "-o", path, "--data-format=LEI16@22050"), nil
+	case "windows":
+		// SAPI rates run from -10 to 10, where 0 is about 180 words per minute
+		rate := min(max((wpm-180)/20, -10), 10)
+		script := fmt.Sprintf("[Console]::InputEncoding = [Text.Encoding]::UTF8; Add-Type -AssemblyName System.Speech; "+
+			"$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; $s.Rate = %d; $s.SetOutputToWaveFile('%s'); "+
+			"$s.Speak([Console]::In.ReadToEnd()); $s.Dispose()", rate, strings.ReplaceAll(path, "'", "''"))
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script), nil
+	default:
+		for _, name := range []string{"espeak-ng", "espeak"} {
+			if _, err := exec.LookPath(name); err == nil {
+				return exec.CommandContext(ctx, name, "-s", strconv.Itoa(wpm), "-w", path, "--stdin"), nil
+			}
+		}
+		return nil, errors.New("reading aloud needs espeak-ng; install it with your package manager")
+	}
+}
+
+// synthesizeSpeech renders text as mono 16-bit PCM, returning its sample
+// rate.
+func synthesizeSpeech(ctx context.Context, text string, wpm int) ([]byte, int, error) {
+	f, err := os.CreateTemp("", "voice-typing-*.wav")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create speech file: %v", err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	cmd, err := speechCommand(ctx, path, wpm)
+	if err != nil {
+		return nil, 0, err
+	}
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, 0, fmt.Errorf("text to speech failed: %v: %s", err, bytes.TrimSpace(out))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read speech: %v", err)
+	}
+	return parseWAV(data)
+}
+
+// parseWAV extracts 16-bit PCM from a WAV file, mixing any channels down to
+// mono.
+func parseWAV(data []byte) ([]byte, int, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, errors.New("speech isn't a WAV file")
+	}
+	channels, rate, bits := 0, 0, 0
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		body := data[pos+8 : min(pos+8+size, len(data))]
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, 0, errors.New("invalid WAV format chunk")
+			}
+			channels = int(binary.LittleEndian.Uint16(body[2:]))
+			rate = int(binary.LittleEndian.Uint32(body[4:]))
+			bits = int(binary.LittleEndian.Uint16(body[14:]))
+		case "data":
+			if bits != 16 || channels < 1 {
+				return nil, 0, fmt.Errorf("unsupported WAV format: %d-bit, %d channels", bits, channels)
+			}
+			return downmix(body, channels), rate, nil
+		}
+		// Chunks are padded to an even length
+		pos += 8 + size + size%2
+	}
+	return nil, 0, errors.New("WAV file has no audio")
+}
+
+// downmix averages interleaved 16-bit channels to mono.
+func downmix(pcm []byte, channels int) []byte {
+	if channels == 1 {
+		return pcm
+	}
+	frame := 2 * channels
+	out := make([]byte, 0, len(pcm)/channels)
+	for i := 0; i+frame <= len(pcm); i += frame {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(int16(binary.LittleEndian.Uint16(pcm[i+2*c:])))
+		}
+		out = binary.LittleEndian.AppendUint16(out, uint16(int16(sum/channels)))
+	}
+	return out
+}
+
+type spokenSentence struct {
+	span [2]int
+	pcm  []byte
+	rate int
+	err  error
+}
+
+// readAloud speaks r's text from offset a sentence at a time, synthesizing
+// the next sentence while the current one plays.
+func (a *App) readAloud(ctx context.Context, r *ReadAloud, offset, wpm int) {
+	sentences := make(chan spokenSentence, 1)
+	go func() {
+		defer close(sentences)
+		for _, span := range sentenceSpans(r.text, offset) {
+			pcm, rate, err := synthesizeSpeech(ctx, r.text[span[0]:span[1]], wpm)
+			select {
+			case sentences <- spokenSentence{span, pcm, rate, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for s := range sentences {
+		if ctx.Err() != nil {
+			return
+		}
+		if s.err != nil {
+			slog.Error("read aloud failed", "err", s.err)
+			fyne.Do(func() {
+				if a.reading == r {
+					a.stopReadAloud()
+					a.showError(s.err)
+				}
+			})
+			return
+		}
+		if err := a.speakSentence(ctx, r, s); err != nil {
+			slog.Error("read aloud playback failed", "err", err)
+			fyne.Do(func() {
+				if a.reading == r {
+					a.stopReadAloud()
+					a.showError(err)
+				}
+			})
+			return
+		}
+	}
+	if ctx.Err() == nil {
+		fyne.Do(func() {
+			if a.reading == r {
+				a.stopReadAloud()
+				a.updateStatus(tr("Finished reading aloud"))
+			}
+		})
+	}
+}
+
+// speakSentence plays a sentence, highlighting each word as it's reached.
+func (a *App) speakSentence(ctx context.Context, r *ReadAloud, s spokenSentence) error {
+	words := proofWordPattern.FindAllStringIndex(r.text[s.span[0]:s.span[1]], -1)
+	var played atomic.Int64
+	done := make(chan error, 1)
+	go func() { done <- audio.Play(ctx, s.pcm, s.rate, &played) }()
+
+	ticker := time.NewTicker(readAloudTick)
+	defer ticker.Stop()
+	current := -1
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			i := wordAt(words, float64(played.Load())/float64(max(len(s.pcm), 1)))
+			if i == current {
+				continue
+			}
+			current = i
+			start, end := s.span[0]+words[i][0], s.span[0]+words[i][1]
+			fyne.Do(func() { a.highlightWord(r, start, end) })
+		}
+	}
+}
+
+// ProofText shows the transcript read-only while it's read aloud. Tapping
+// it pauses reading to edit at the current word.
+type ProofText struct {
+	widget.RichText
+	onTapped func()
+}
+
+func newProofText() *ProofText {
+	t := &ProofText{}
+	t.Wrapping = fyne.TextWrapWord
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *ProofText) Tapped(*fyne.PointEvent) {
+	if t.onTapped != nil {
+		t.onTapped()
+	}
+}
+
+var proofWordStyle = widget.RichTextStyle{
+	Inline:    true,
+	ColorName: theme.ColorNamePrimary,
+	TextStyle: fyne.TextStyle{Bold: true},
+}
+
+func (a *App) newProofView() fyne.CanvasObject {
+	a.proofText = newProofText()
+	a.proofText.onTapped = a.editAtWord
+	a.proofScroll = container.NewVScroll(a.proofText)
+	a.proofScroll.Hide()
+	return a.proofScroll
+}
+
+// newReadAloudBox is the bar with pause, speed and stop controls shown while
+// reading aloud.
+func (a *App) newReadAloudBox() fyne.CanvasObject {
+	a.readPauseBtn = widget.NewButtonWithIcon(tr("Pause"), theme.MediaPauseIcon(), a.toggleReadAloudPause)
+	stopBtn := widget.NewButtonWithIcon(tr("Stop"), theme.MediaStopIcon(), a.stopReadAloud)
+	speedLbl := widget.NewLabel("")
+	a.readSpeed = widget.NewSlider(minReadAloudRate, maxReadAloudRate)
+	a.readSpeed.Step = 5
+	a.readSpeed.OnChanged = func(wpm float64) {
+		speedLbl.SetText(tr("%d wpm", int(wpm)))
+	}
+	a.readSpeed.OnChangeEnded = a.setReadAloudRate
+	a.readSpeed.SetValue(float64(a.settings().ReadAloudRate))
+
+	hint := widget.NewLabel(tr("Click the text to pause and edit"))
+	hint.Importance = widget.LowImportance
+	a.readBox = container.NewBorder(nil, nil, container.NewHBox(a.readPauseBtn, stopBtn), container.NewHBox(speedLbl, hint), a.readSpeed)
+	a.readBox.Hide()
+	return a.readBox
+}
+
+// toggleReadAloud reads the transcript aloud from the caret, or stops.
+func (a *App) toggleReadAloud() {
+	if a.reading != nil {
+		a.stopReadAloud()
+		return
+	}
+	offset := a.cursorOffset()
+	if strings.TrimSpace(a.textArea.Text[offset:]) == "" {
+		// A caret left at the end reads everything
+		offset = 0
+	}
+	a.startReadAloud(offset)
+}
+
+func (a *App) startReadAloud(offset int) {
+	if a.recording.Load() {
+		a.updateStatus(tr("Stop recording to read aloud"))
+		return
+	}
+	text := a.textArea.Text
+	if strings.TrimSpace(text) == "" {
+		a.updateStatus(tr("No text to read"))
+		return
+	}
+	if a.reading != nil {
+		a.reading.cancel()
+	}
+	a.closeSpelling()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ReadAloud{cancel: cancel, text: text, word: [2]int{offset, offset}}
+	a.reading = r
+
+	a.highlightWord(r, offset, offset)
+	a.editView.Hide()
+	a.proofScroll.Show()
+	a.readPauseBtn.SetText(tr("Pause"))
+	a.readPauseBtn.SetIcon(theme.MediaPauseIcon())
+	a.readBox.Show()
+	a.updateStatus(tr("Reading aloud..."))
+	go a.readAloud(ctx, r, offset, a.settings().ReadAloudRate)
+}
+
+func (a *App) pauseReadAloud() {
+	r := a.reading
+	if r == nil || r.paused {
+		return
+	}
+	r.cancel()
+	r.paused = true
+	a.readPauseBtn.SetText(tr("Resume"))
+	a.readPauseBtn.SetIcon(theme.MediaPlayIcon())
+	a.updateStatus(tr("Reading paused"))
+}
+
+// toggleReadAloudPause pauses, or resumes from the word reading stopped at,
+// or from the caret if the text was edited meanwhile.
+func (a *App) toggleReadAloudPause() {
+	r := a.reading
+	if r == nil {
+		return
+	}
+	if !r.paused {
+		a.pauseReadAloud()
+		return
+	}
+	offset := r.word[0]
+	if a.textArea.Text != r.text || a.editView.Visible() {
+		offset = a.cursorOffset()
+	}
+	a.startReadAloud(offset)
+}
+
+// editAtWord pauses reading and puts the caret at the current word.
+func (a *App) editAtWord() {
+	r := a.reading
+	if r == nil {
+		return
+	}
+	a.pauseReadAloud()
+	a.proofScroll.Hide()
+	a.editView.Show()
+	a.textArea.moveCaretTo(min(r.word[0], len(a.textArea.Text)))
+	a.window.Canvas().Focus(a.textArea)
+}
+
+// pauseReadAloudForEdit pauses reading when the text changes underneath it.
+func (a *App) pauseReadAloudForEdit() {
+	if r := a.reading; r != nil && !r.paused && a.textArea.Text != r.text {
+		a.editAtWord()
+	}
+}
+
+func (a *App) stopReadAloud() {
+	r := a.reading
+	if r == nil {
+		return
+	}
+	r.cancel()
+	a.reading = nil
+	a.readBox.Hide()
+	a.proofScroll.Hide()
+	a.editView.Show()
+}
+
+// highlightWord marks the word being read and keeps it in view.
+func (a *App) highlightWord(r *ReadAloud, start, end int) {
+	if a.reading != r {
+		return
+	}
+	r.word = [2]int{start, end}
+	a.proofText.Segments = []widget.RichTextSegment{
+		&widget.TextSegment{Style: widget.RichTextStyleInline, Text: r.text[:start]},
+		&widget.TextSegment{Style: proofWordStyle, Text: r.text[start:end]},
+		&widget.TextSegment{Style: widget.RichTextStyleInline, Text: r.text[end:]},
+	}
+	a.proofText.Refresh()
+	y := a.proofText.Size().Height*float32(start)/float32(max(len(r.text), 1)) - a.proofScroll.Size().Height/2
+	a.proofScroll.ScrollToOffset(fyne.NewPos(0, max(y, 0)))
+}
+
+// setReadAloudRate saves the speed, restarting the speech at the current
+// word so the change is heard straight away.
+func (a *App) setReadAloudRate(wpm float64) {
+	a.updateSettings(func(s *Settings) { s.ReadAloudRate = int(wpm) })
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		slog.Warn("failed to save read aloud speed", "err", err)
+	}
+	if r := a.reading; r != nil && !r.paused {
+		a.startReadAloud(r.word[0])
+	}
+}
+
+// cursorOffset is the caret's byte offset in the transcript, moved back to
+// the start of its word.
+func (a *App) cursorOffset() int {
+	text := a.textArea.Text
+	offset := a.textArea.caretOffset()
+	for offset > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:offset])
+		if unicode.IsSpace(r) {
+			break
+		}
+		offset -= size
+	}
+	return offset
+}
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrence is the part of an RRULE (RFC 5545 section 3.3.10) that meetings
+// use: a daily, weekly, monthly or yearly frequency with an interval, a count
+// or end, and the days it falls on. Weeks start on Monday.
+type recurrence struct {
+	freq       string
+	interval   int
+	count      int       // Occurrences in all, 0 for no limit
+	until      time.Time // Last possible start, zero for no limit
+	byDay      []weekdayRule
+	byMonthDay []int
+}
+
+// weekdayRule is a BYDAY entry, such as MO, 2TU or -1FR.
+type weekdayRule struct {
+	n   int // Which one in the month, counting back if negative; 0 for every
+	day time.Weekday
+}
+
+// maxRecurrencePeriods bounds the expansion of rules with no end.
+const maxRecurrencePeriods = 10000
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRRule parses an RRULE value for an event starting at start. Rules
+// repeating more often than daily aren't supported and give nil.
+func parseRRule(value string, start time.Time) *recurrence {
+	r := &recurrence{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			r.freq = val
+		case "INTERVAL":
+			if n, err := strconv.Atoi(val); err == nil && n > 0 {
+				r.interval = n
+			}
+		case "COUNT":
+			r.count, _ = strconv.Atoi(val)
+		case "UNTIL":
+			if len(val) == len("20060102") {
+				// A date includes the whole day
+				if t, err := time.ParseInLocation("20060102", val, start.Location()); err == nil {
+					r.until = t.AddDate(0, 0, 1).Add(-time.Second)
+				}
+			} else {
+				r.until = parseICSTime(val, "")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				if len(day) < 2 {
+					continue
+				}
+				wd, ok := icsWeekdays[day[len(day)-2:]]
+				if !ok {
+					continue
+				}
+				n, _ := strconv.Atoi(day[:len(day)-2])
+				r.byDay = append(r.byDay, weekdayRule{n: n, day: wd})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				if n, err := strconv.Atoi(day); err == nil && n != 0 {
+					r.byMonthDay = append(r.byMonthDay, n)
+				}
+			}
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+		return r
+	}
+	return nil
+}
+
+// each calls fn with the starts of the event beginning at start, in order,
+// up to to. Periods wholly before from are skipped where that doesn't change
+// which occurrences count.
+func (r *recurrence) each(start, from, to time.Time, fn func(time.Time)) {
+	first := 0
+	if days := map[string]int{"DAILY": 1, "WEEKLY": 7}[r.freq]; days > 0 && r.count == 0 && from.After(start) {
+		length := time.Duration(days*r.interval) * 24 * time.Hour
+		first = max(int(from.Sub(start)/length)-1, 0)
+	}
+	n := 0
+	for i := first; i < first+maxRecurrencePeriods; i++ {
+		base, starts := r.period(start, i)
+		if base.After(to) || !r.until.IsZero() && base.After(r.until) {
+			return
+		}
+		for _, t := range starts {
+			if t.Before(start) {
+				continue
+			}
+			if t.After(to) || !r.until.IsZero() && t.After(r.until) {
+				return
+			}
+			if n++; r.count > 0 && n > r.count {
+				return
+			}
+			fn(t)
+		}
+	}
+}
+
+// period returns the beginning of the i'th period of the rule and the starts
+// in it, in order.
+func (r *recurrence) period(start time.Time, i int) (time.Time, []time.Time) {
+	y, m, d := start.Date()
+	h, mi, s := start.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, h, mi, s, 0, start.Location())
+	}
+	var starts []time.Time
+	switch r.freq {
+	case "DAILY":
+		base := at(y, m, d+i*r.interval)
+		if r.onDay(base.Weekday()) {
+			starts = append(starts, base)
+		}
+		return base, starts
+	case "WEEKLY":
+		monday := d - (int(start.Weekday())+6)%7 + 7*i*r.interval
+		if len(r.byDay) == 0 {
+			starts = append(starts, at(y, m, d+7*i*r.interval))
+		}
+		for _, rule := range r.byDay {
+			starts = append(starts, at(y, m, monday+(int(rule.day)+6)%7))
+		}
+		slices.SortFunc(starts, time.Time.Compare)
+		return at(y, m, monday), slices.CompactFunc(starts, time.Time.Equal)
+	case "MONTHLY":
+		month := at(y, m+time.Month(i*r.interval), 1)
+		return month, r.daysInMonth(month, d, at)
+	default:
+		year := at(y+i*r.interval, time.January, 1)
+		// A start on 29 February only recurs in leap years
+		if t := at(year.Year(), m, d); t.Month() == m {
+			starts = append(starts, t)
+		}
+		return year, starts
+	}
+}
+
+// onDay reports whether a daily rule includes the weekday.
+func (r *recurrence) onDay(day time.Weekday) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, rule := range r.byDay {
+		if rule.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// daysInMonth returns the starts in the month beginning at first: the days of
+// BYMONTHDAY or BYDAY, or else the day of the month of the first start.
+func (r *recurrence) daysInMonth(first time.Time, day int, at func(int, time.Month, int) time.Time) []time.Time {
+	y, m := first.Year(), first.Month()
+	last := at(y, m+1, 0).Day()
+	var days []int
+	switch {
+	case len(r.byMonthDay) > 0:
+		for _, n := range r.byMonthDay {
+			if n < 0 {
+				n += last + 1
+			}
+			days = append(days, n)
+		}
+	case len(r.byDay) > 0:
+		for _, rule := range r.byDay {
+			// The first such weekday in the month, then every week after
+			firstDay := 1 + (int(rule.day)-int(first.Weekday())+7)%7
+			var matches []int
+			for d := firstDay; d <= last; d += 7 {
+				matches = append(matches, d)
+			}
+			switch {
+			case rule.n == 0:
+				days = append(days, matches...)
+			case rule.n > 0 && rule.n <= len(matches):
+				days = append(days, matches[rule.n-1])
+			case rule.n < 0 && -rule.n <= len(matches):
+				days = append(days, matches[len(matches)+rule.n])
+			}
+		}
+	default:
+		days = append(days, day)
+	}
+	slices.Sort(days)
+	var starts []time.Time
+	for _, d := range slices.Compact(days) {
+		// Months too short for the day are skipped
+		if d >= 1 && d <= last {
+			starts = append(starts, at(y, m, d))
+		}
+	}
+	return starts
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Kinds of personal information that can be redacted
+const (
+	redactEmail  = "email"
+	redactPhone  = "phone"
+	redactNumber = "number" // Card, account and ID numbers
+	redactName   = "name"
+)
+
+var redactKinds = []struct {
+	ID    string
+	Label string
+	Mask  string
+}{
+	{redactEmail, "Email addresses", "[EMAIL]"},
+	{redactPhone, "Phone numbers", "[PHONE]"},
+	{redactNumber, "Card, account and ID numbers", "[NUMBER]"},
+	{redactName, "Names (attendees, your list and introductions)", "[NAME]"},
+}
+
+func redactMask(kind string) string {
+	for _, k := range redactKinds {
+		if k.ID == kind {
+			return k.Mask
+		}
+	}
+	return "[REDACTED]"
+}
+
+var (
+	// Written addresses, and spoken ones like "jane at example dot com"
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b|\b[a-z0-9._-]+ at [a-z0-9-]+(?: dot [a-z0-9-]+)+\b`)
+	// Runs of digits joined by single separators, classified by length and
+	// grouping
+	numberRunPattern = regexp.MustCompile(`\+?\(?\d+\)?(?:[ .-]?\(?\d+\)?)*`)
+	idNumberPattern  = regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`)
+	datePattern      = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$|^\d{1,2}[./-]\d{1,2}[./-]\d{2,4}$`)
+	// A capitalized name after an introduction or title
+	introductionPattern = regexp.MustCompile(`\b(?:[Mm]y name is|[Tt]his is|I'm|I am|[Cc]all me|[Ss]peaking with|Mr\.?|Mrs\.?|Ms\.?|Dr\.?)\s+(\p{Lu}[\p{L}'-]+(?:\s+\p{Lu}[\p{L}'-]+)?)`)
+)
+
+// notNames are capitalized words that follow "this is" or "I'm" without
+// being anyone's name.
+var notNames = map[string]bool{
+	"I": true, "Monday": true, "Tuesday": true, "Wednesday": true, "Thursday": true, "Friday": true,
+	"Saturday": true, "Sunday": true, "January": true, "February": true, "March": true, "April": true,
+	"May": true, "June": true, "July": true, "August": true, "September": true, "October": true,
+	"November": true, "December": true, "Okay": true, "Not": true, "Just": true, "Sure": true,
+}
+
+// notName reports whether what follows an introduction starts with one of
+// notNames, as in "this is Monday's".
+func notName(name string) bool {
+	first := strings.Fields(name)[0]
+	return notNames[strings.TrimSuffix(first, "'s")]
+}
+
+// Redactor masks personal information in dictated text before it's stored,
+// so the transcript, history, exports, live outputs and LLM requests never
+// see it. Names picked up from introductions are remembered for the rest of
+// the session.
+type Redactor struct {
+	mu    sync.Mutex
+	kinds map[string]bool
+	names []string
+	known *regexp.Regexp // Matches any of names
+}
+
+// newRedactor redacts the given kinds. names are always masked when names
+// are redacted, along with each of their words, so "Jane Doe" also covers a
+// later "Jane".
+func newRedactor(kinds []string, names []string) *Redactor {
+	r := &Redactor{kinds: make(map[string]bool)}
+	for _, kind := range kinds {
+		r.kinds[kind] = true
+	}
+	if len(r.kinds) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		r.learn(name)
+	}
+	return r
+}
+
+// learn adds a name and its words to the names masked. Caller must hold
+// r.mu, or be constructing r.
+func (r *Redactor) learn(name string) {
+	parts := append([]string{name}, strings.Fields(name)...)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if len([]rune(part)) < 2 || notNames[part] || containsFold(r.names, part) {
+			continue
+		}
+		r.names = append(r.names, part)
+	}
+	if len(r.names) == 0 {
+		return
+	}
+	quoted := make([]string, len(r.names))
+	for i, n := range r.names {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	// Longest first so a full name is masked as one
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	r.known = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// redact masks the enabled kinds of personal information in text. Names
+// introduced in final turns are remembered; in partial ones they're only
+// masked where they appear, since the recognizer may still change them. A
+// nil Redactor leaves text alone.
+func (r *Redactor) redact(text string, final bool) string {
+	if r == nil {
+		return text
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.kinds[redactEmail] {
+		text = emailPattern.ReplaceAllString(text, redactMask(redactEmail))
+	}
+	if r.kinds[redactPhone] || r.kinds[redactNumber] {
+		text = numberRunPattern.ReplaceAllStringFunc(text, r.redactNumber)
+	}
+	if r.kinds[redactName] {
+		if final {
+			for _, m := range introductionPattern.FindAllStringSubmatch(text, -1) {
+				if !notName(m[1]) {
+					r.learn(m[1])
+				}
+			}
+		} else {
+			text = maskIntroductions(text)
+		}
+		if r.known != nil {
+			text = r.known.ReplaceAllString(text, redactMask(redactName))
+		}
+	}
+	return text
+}
+
+// maskIntroductions masks the names after introductions in text without
+// learning them.
+func maskIntroductions(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range introductionPattern.FindAllStringSubmatchIndex(text, -1) {
+		if notName(text[m[2]:m[3]]) {
+			continue
+		}
+		b.WriteString(text[last:m[2]])
+		b.WriteString(redactMask(redactName))
+		last = m[3]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// redactNumber masks a run of digits if it's long enough to be a card
+// number, or shaped like an ID or phone number, keeping trailing
+// separators. Phone numbers are grouped 555 123 4567 or start with + and a
+// country code, so lists like "10 20 30 40" are left alone.
+func (r *Redactor) redactNumber(run string) string {
+	trimmed := strings.TrimRightFunc(run, func(c rune) bool { return !unicode.IsDigit(c) && c != ')' })
+	digits := 0
+	for _, c := range trimmed {
+		if unicode.IsDigit(c) {
+			digits++
+		}
+	}
+	suffix := run[len(trimmed):]
+	switch {
+	case datePattern.MatchString(trimmed):
+		return run
+	case r.kinds[redactNumber] && (digits >= 13 || idNumberPattern.MatchString(trimmed)):
+		return redactMask(redactNumber) + suffix
+	case r.kinds[redactPhone] && ((strings.HasPrefix(trimmed, "+") && digits >= 8 && digits <= 15) || phoneGroups(digitGroupPattern.FindAllString(trimmed, -1))):
+		return redactMask(redactPhone) + suffix
+	}
+	return run
+}
+
+// redactKindList parses the comma separated kinds in the settings.
+func redactKindList(setting string) []string {
+	var kinds []string
+	for _, kind := range strings.Split(setting, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// newSessionRedactor builds the redactor for a recording from its settings
+// and attendees. Caller must hold a.mu.
+func (a *App) newSessionRedactor(cfg *Settings) *Redactor {
+	names := append([]string{}, a.sessionAttendees...)
+	for _, line := range strings.Split(cfg.RedactNames, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return newRedactor(redactKindList(cfg.RedactPII), names)
+}
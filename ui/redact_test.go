@@ -0,0 +1,54 @@
+package ui
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	all := []string{redactEmail, redactPhone, redactNumber, redactName}
+	for _, tt := range []struct {
+		name  string
+		kinds []string
+		names []string
+		text  string
+		want  string
+	}{
+		{"email", all, nil, "write to jane.doe@example.com today", "write to [EMAIL] today"},
+		{"spoken email", all, nil, "it's jane at example dot com", "it's [EMAIL]"},
+		{"phone", all, nil, "call 555-123-4567.", "call [PHONE]."},
+		{"phone with country code", all, nil, "call 1 (555) 123 4567", "call [PHONE]"},
+		{"international phone", all, nil, "call +44 20 7946 0958", "call [PHONE]"},
+		{"card", all, nil, "card 4111 1111 1111 1111 expires", "card [NUMBER] expires"},
+		{"id", all, nil, "SSN 123-45-6789", "SSN [NUMBER]"},
+		{"attendee", all, []string{"Jane Doe"}, "Jane Doe and later Jane", "[NAME] and later [NAME]"},
+		{"introduction", all, nil, "Hi, my name is Sam Lee.", "Hi, my name is [NAME]."},
+		{"not a name", all, nil, "This is Monday's plan", "This is Monday's plan"},
+		{"list of numbers", all, nil, "scores of 10 20 30 40", "scores of 10 20 30 40"},
+		{"date", all, nil, "due 2024-03-05", "due 2024-03-05"},
+		{"amount", all, nil, "about 12,500 people", "about 12,500 people"},
+		{"kind disabled", []string{redactEmail}, []string{"Jane"}, "Jane on 555-123-4567", "Jane on 555-123-4567"},
+	} {
+		r := newRedactor(tt.kinds, tt.names)
+		if got := r.redact(tt.text, true); got != tt.want {
+			t.Errorf("%s: redact(%q) = %q, want %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRedactLearnsFromFinalTurns(t *testing.T) {
+	r := newRedactor([]string{redactName}, nil)
+	if got, want := r.redact("this is Ma", false), "this is [NAME]"; got != want {
+		t.Errorf("partial = %q, want %q", got, want)
+	}
+	if got := r.redact("Ma said so", true); got != "Ma said so" {
+		t.Errorf("name learned from partial turn: %q", got)
+	}
+	r.redact("this is Maria", true)
+	if got, want := r.redact("Maria said so", true), "[NAME] said so"; got != want {
+		t.Errorf("after final turn = %q, want %q", got, want)
+	}
+}
+
+func TestNoRedactor(t *testing.T) {
+	if r := newRedactor(nil, []string{"Jane"}); r != nil || r.redact("Jane", true) != "Jane" {
+		t.Error("redactor without kinds changed text")
+	}
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+
+	"dict/audio"
+	"dict/stt"
+)
+
+// replay is a recorded session played back in place of the microphone, and
+// of AssemblyAI for a message log, so changes to the UI and turn handling
+// can be tried without speaking or spending API minutes.
+type replay struct {
+	name     string
+	pcm      []byte      // Played in place of the microphone; silence if nil
+	messages *stt.Replay // Played in place of AssemblyAI, or nil to stream pcm
+}
+
+// parseReplay reads a WAV file, or a message log as saved from the protocol
+// inspector.
+func parseReplay(name string, data []byte) (*replay, error) {
+	if strings.EqualFold(filepath.Ext(name), ".wav") {
+		pcm, err := audio.DecodeWAV(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", name, err)
+		}
+		return &replay{name: name, pcm: pcm}, nil
+	}
+	messages, err := stt.ParseReplay(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	return &replay{name: name, messages: messages}, nil
+}
+
+// offline reports whether the session needs nothing from AssemblyAI.
+func (r *replay) offline() bool {
+	return r != nil && r.messages != nil
+}
+
+// capture and streamer stand in for App.newCapture and App.newStreamer.
+func (r *replay) capture() (audio.Capture, error) {
+	return audio.NewFile(r.pcm), nil
+}
+
+func (r *replay) streamer(*Settings) (stt.Streamer, error) {
+	return r.messages, nil
+}
+
+// replaySettings tunes a session config to replay r as the microphone.
+func replaySettings(r *replay) func(*Settings) *Settings {
+	return func(cfg *Settings) *Settings {
+		s := *cfg
+		s.replay = r
+		s.CaptureSource = captureSourceMicrophone
+		return &s
+	}
+}
+
+// showReplay asks for a WAV file or message log and starts a session
+// replaying it.
+func (a *App) showReplay() {
+	if a.recording.Load() {
+		a.updateStatus(tr("Stop recording to replay a session"))
+		return
+	}
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			a.showError(fmt.Errorf("failed to read %s: %v", reader.URI().Name(), err))
+			return
+		}
+		r, err := parseReplay(reader.URI().Name(), data)
+		if err != nil {
+			a.showError(err)
+			return
+		}
+		slog.Info("replaying session", "file", r.name, "offline", r.offline())
+		a.startRecordingWith(replaySettings(r))
+	}, a.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".wav", ".jsonl"}))
+	open.Show()
+}
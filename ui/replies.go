@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var (
+	// "Sure! Here is the cleaned text:" and the like, on its own line or
+	// before the text
+	replyLeadPattern = regexp.MustCompile(`(?i)^(?:(?:sure|certainly|okay|of course)[,!.]?\s*)?(?:here(?:'s| is| are)|below is) (?:the |your |a )?(?:[\w-]+ ){0,3}(?:text|version|transcript|translation|summary|notes|result|output|email|message|rewrite)\b[^:\n]{0,40}:\s*`)
+	// A label line such as "Cleaned text:" or "Corrected version:"
+	replyLabelPattern = regexp.MustCompile(`(?i)^(?:the )?(?:cleaned|corrected|edited|revised|rewritten|formatted|translated|fixed|final|output)(?: up)?(?: text| version| transcript)?:[ \t]*\n`)
+	// Offers of further help at the end
+	replyTailPattern = regexp.MustCompile(`(?i)\n+[ \t]*(?:let me know|i hope this helps|hope this helps|feel free to|if you (?:need|have|want|would like))[^\n]*$`)
+	// A reply that's all one code block
+	replyFencePattern = regexp.MustCompile("^```[\\w+-]*[ \\t]*\\n([\\s\\S]*?)\\n?```$")
+)
+
+// replyQuotes are the pairs of quotes a whole reply is sometimes wrapped in.
+var replyQuotes = [][2]string{{`"`, `"`}, {"“", "”"}, {"«", "»"}}
+
+// parseReplyPatterns compiles the extra wrapper patterns from the settings,
+// one regular expression per line.
+func parseReplyPatterns(setting string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, line := range strings.Split(setting, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?im)" + line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply pattern %q: %v", line, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// cleanReply removes what models wrap plain text replies in: a lead-in like
+// "Here is the cleaned text:", a closing offer of more help, a code fence
+// around the whole reply, and quotes around the whole reply. Text matching
+// any of patterns is removed first. Anything that was already in the input
+// is kept, since then it's the user's and not the model's.
+func cleanReply(reply, input string, patterns []*regexp.Regexp) string {
+	lowerInput := strings.ToLower(input)
+	inInput := func(s string) bool { return strings.Contains(lowerInput, strings.ToLower(strings.TrimSpace(s))) }
+	strip := func(re *regexp.Regexp, text string) string {
+		return re.ReplaceAllStringFunc(text, func(m string) string {
+			if inInput(m) {
+				return m
+			}
+			return ""
+		})
+	}
+
+	text := reply
+	for _, re := range patterns {
+		text = strip(re, text)
+	}
+	text = strings.TrimSpace(text)
+	for {
+		before := text
+		text = strip(replyLeadPattern, text)
+		text = strip(replyLabelPattern, text)
+		text = strings.TrimSpace(strip(replyTailPattern, text))
+		if m := replyFencePattern.FindStringSubmatch(text); m != nil && !strings.Contains(input, "```") {
+			text = strings.TrimSpace(m[1])
+		}
+		for _, q := range replyQuotes {
+			inner := strings.TrimSuffix(strings.TrimPrefix(text, q[0]), q[1])
+			if len(inner)+len(q[0])+len(q[1]) == len(text) && !strings.Contains(inner, q[0]) && !strings.Contains(inner, q[1]) && !inInput(text) {
+				text = strings.TrimSpace(inner)
+			}
+		}
+		if text == before {
+			break
+		}
+	}
+	// Better the whole reply than nothing
+	if text == "" {
+		return reply
+	}
+	return text
+}
+
+// cleanLLMReply cleans the reply to input from a prompt that expects plain
+// text, when that's turned on in the settings.
+func (a *App) cleanLLMReply(reply, input string) string {
+	cfg := a.settings()
+	if !cfg.CleanReplies {
+		return reply
+	}
+	patterns, err := parseReplyPatterns(cfg.ReplyPatterns)
+	if err != nil {
+		slog.Warn("ignoring reply patterns", "err", err)
+		patterns = nil
+	}
+	return cleanReply(reply, input, patterns)
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	pruneInterval = time.Hour
+	pruneGrace    = 5 * time.Minute // Files written this recently may belong to the current session
+)
+
+// StorageUsage is the disk space taken by each kind of data in the config
+// directory.
+type StorageUsage struct {
+	Recordings     int64
+	RecordingFiles int
+	History        int64
+	HistoryFiles   int
+	Logs           int64
+	Other          int64
+}
+
+func (u StorageUsage) total() int64 {
+	return u.Recordings + u.History + u.Logs + u.Other
+}
+
+// storageUsage adds up the files in the config directory.
+func (a *App) storageUsage() (StorageUsage, error) {
+	var u StorageUsage
+	root := a.getConfigDir()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		switch strings.SplitN(filepath.ToSlash(rel), "/", 2)[0] {
+		case "recordings":
+			u.Recordings += info.Size()
+			u.RecordingFiles++
+		case "history":
+			u.History += info.Size()
+			u.HistoryFiles++
+		case "logs":
+			u.Logs += info.Size()
+		default:
+			u.Other += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return u, fmt.Errorf("failed to measure storage: %v", err)
+	}
+	return u, nil
+}
+
+// formatBytes shows a size in B, KB, MB or GB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB"} {
+		if size < unit {
+			break
+		}
+		size, suffix = size/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", size, suffix)
+}
+
+// storedFile is a recording or history session that retention may prune.
+type storedFile struct {
+	path     string
+	when     time.Time // When the session took place
+	modified time.Time
+	size     int64
+	audio    bool
+}
+
+func (a *App) storedFiles() ([]storedFile, error) {
+	var files []storedFile
+	paths, err := filepath.Glob(filepath.Join(a.getRecordingsDir(), "*.wav"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			files = append(files, storedFile{path: path, when: info.ModTime(), modified: info.ModTime(), size: info.Size(), audio: true})
+		}
+	}
+	sessions, err := a.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range sessions {
+		path := filepath.Join(a.getHistoryDir(), h.ID+".json")
+		if info, err := os.Stat(path); err == nil {
+			files = append(files, storedFile{path: path, when: h.Ended, modified: info.ModTime(), size: info.Size()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].when.Before(files[j].when) })
+	return files, nil
+}
+
+// pruneStorage deletes recordings and history sessions the retention
+// settings no longer keep: those older than their limit, then, while the
+// two together are over the disk limit, the oldest recordings and after
+// them the oldest sessions. It returns how many files it removed and the
+// space freed.
+func (a *App) pruneStorage(cfg *Settings, now time.Time) (int, int64, error) {
+	files, err := a.storedFiles()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list stored sessions: %v", err)
+	}
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+
+	removed, freed := 0, int64(0)
+	remove := func(f *storedFile, reason string) {
+		if err := os.Remove(f.path); err != nil {
+			slog.Warn("failed to prune file", "path", f.path, "err", err)
+			return
+		}
+		slog.Info("pruned file", "path", f.path, "reason", reason)
+		removed++
+		freed += f.size
+		total -= f.size
+		f.size = -1
+	}
+	prunable := func(f *storedFile) bool {
+		return f.size >= 0 && now.Sub(f.modified) >= pruneGrace
+	}
+
+	audioCutoff := now.AddDate(0, 0, -cfg.RetainAudioDays)
+	transcriptCutoff := now.AddDate(0, -cfg.RetainTranscriptMonths, 0)
+	for i := range files {
+		f := &files[i]
+		expired := (f.audio && cfg.RetainAudioDays > 0 && f.when.Before(audioCutoff)) ||
+			(!f.audio && cfg.RetainTranscriptMonths > 0 && f.when.Before(transcriptCutoff))
+		if expired && prunable(f) {
+			remove(f, "age")
+		}
+	}
+
+	limit := int64(cfg.MaxStorageMB) << 20
+	for _, audio := range []bool{true, false} {
+		for i := range files {
+			if limit <= 0 || total <= limit {
+				break
+			}
+			if f := &files[i]; f.audio == audio && prunable(f) {
+				remove(f, "disk limit")
+			}
+		}
+	}
+	return removed, freed, nil
+}
+
+// startRetention prunes storage at startup and then every hour, when any
+// retention limit is set.
+func (a *App) startRetention() {
+	go func() {
+		prune := func() {
+			cfg := a.settings()
+			if cfg.RetainAudioDays <= 0 && cfg.RetainTranscriptMonths <= 0 && cfg.MaxStorageMB <= 0 {
+				return
+			}
+			removed, freed, err := a.pruneStorage(cfg, time.Now())
+			if err != nil {
+				slog.Error("pruning storage failed", "err", err)
+				return
+			}
+			if removed > 0 {
+				slog.Info("storage pruned", "files", removed, "freed", formatBytes(freed))
+			}
+		}
+		prune()
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			prune()
+		}
+	}()
+}
+
+// showStorage shows the space each kind of data takes, with the retention
+// limits and a button to prune now.
+func (a *App) showStorage() {
+	cfg := a.settings()
+	usageLbl := widget.NewLabel("")
+	refresh := func() {
+		u, err := a.storageUsage()
+		if err != nil {
+			usageLbl.SetText(err.Error())
+			return
+		}
+		usageLbl.SetText(tr("Recordings: %s (%d files)\nHistory: %s (%d sessions)\nLogs: %s\nSettings and other: %s\nTotal: %s", formatBytes(u.Recordings), u.RecordingFiles, formatBytes(u.History), u.HistoryFiles, formatBytes(u.Logs), formatBytes(u.Other), formatBytes(u.total())))
+	}
+	refresh()
+
+	limitEntry := func(value int, placeholder string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(placeholder)
+		if value > 0 {
+			e.SetText(strconv.Itoa(value))
+		}
+		return e
+	}
+	audioEntry := limitEntry(cfg.RetainAudioDays, "Forever")
+	transcriptEntry := limitEntry(cfg.RetainTranscriptMonths, "Forever")
+	maxEntry := limitEntry(cfg.MaxStorageMB, "No limit")
+	limitForm := widget.NewForm(
+		widget.NewFormItem(tr("Keep audio (days)"), audioEntry),
+		widget.NewFormItem(tr("Keep transcripts (months)"), transcriptEntry),
+		widget.NewFormItem(tr("Max disk usage (MB)"), maxEntry),
+	)
+
+	var d dialog.Dialog
+	saveBtn := widget.NewButton(tr("Save Limits"), func() {
+		var limits [3]int
+		for i, e := range []*widget.Entry{audioEntry, transcriptEntry, maxEntry} {
+			if text := strings.TrimSpace(e.Text); text != "" {
+				n, err := strconv.Atoi(text)
+				if err != nil || n < 0 {
+					dialog.ShowError(fmt.Errorf("Limits must be whole numbers, or empty for none"), a.window)
+					return
+				}
+				limits[i] = n
+			}
+		}
+		a.updateSettings(func(s *Settings) {
+			s.RetainAudioDays, s.RetainTranscriptMonths, s.MaxStorageMB = limits[0], limits[1], limits[2]
+		})
+		a.saveConfig()
+		d.Hide()
+	})
+	pruneBtn := widget.NewButton(tr("Prune Now"), func() {
+		removed, freed, err := a.pruneStorage(a.settings(), time.Now())
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		refresh()
+		a.updateStatus(tr("Pruned %d files, freeing %s", removed, formatBytes(freed)))
+	})
+
+	note := widget.NewLabel(tr("Old recordings and sessions are pruned every hour. Over the disk limit, the oldest recordings go first, then the oldest sessions. Logs rotate on their own."))
+	note.Wrapping = fyne.TextWrapWord
+	note.Importance = widget.LowImportance
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(tr("Disk Usage"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		usageLbl,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(tr("Retention"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		limitForm,
+		container.NewHBox(saveBtn, pruneBtn),
+		note,
+	)
+	d = dialog.NewCustom(tr("Storage"), tr("Close"), content, a.window)
+	d.Resize(fyne.NewSize(460, 0))
+	d.Show()
+}
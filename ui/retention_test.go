@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPruneStorage(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	// Recordings of 1 MB each, and sessions, by age
+	recordings := map[string]time.Duration{
+		"old.wav":   40 * 24 * time.Hour,
+		"new.wav":   2 * 24 * time.Hour,
+		"fresh.wav": time.Minute, // Still being written
+	}
+	sessions := map[string]time.Time{
+		"20230401-100000": now.AddDate(0, -14, 0),
+		"20240501-100000": now.AddDate(0, -1, 0),
+	}
+
+	for _, tt := range []struct {
+		name string
+		cfg  Settings
+		want []string // Files left
+	}{
+		{"no limits", Settings{}, []string{"fresh.wav", "new.wav", "old.wav", "20230401-100000.json", "20240501-100000.json"}},
+		{"audio age", Settings{RetainAudioDays: 30}, []string{"fresh.wav", "new.wav", "20230401-100000.json", "20240501-100000.json"}},
+		{"transcript age", Settings{RetainTranscriptMonths: 12}, []string{"fresh.wav", "new.wav", "old.wav", "20240501-100000.json"}},
+		// Recordings go first, oldest first, but not the one being written
+		{"disk limit", Settings{MaxStorageMB: 2}, []string{"fresh.wav", "20230401-100000.json", "20240501-100000.json"}},
+		{"tight disk limit", Settings{MaxStorageMB: 1}, []string{"fresh.wav"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			a := &App{}
+			recordingsDir := filepath.Join(a.getConfigDir(), "recordings")
+			if err := os.MkdirAll(recordingsDir, 0700); err != nil {
+				t.Fatal(err)
+			}
+			for name, age := range recordings {
+				path := filepath.Join(recordingsDir, name)
+				if err := os.WriteFile(path, make([]byte, 1<<20), 0600); err != nil {
+					t.Fatal(err)
+				}
+				os.Chtimes(path, now.Add(-age), now.Add(-age))
+			}
+			for id, ended := range sessions {
+				h := &HistorySession{ID: id, Started: ended.Add(-time.Hour), Ended: ended, Revisions: []Revision{{Text: "Notes"}}}
+				if err := a.saveHistorySession(h); err != nil {
+					t.Fatal(err)
+				}
+				os.Chtimes(filepath.Join(a.getHistoryDir(), id+".json"), ended, ended)
+			}
+
+			if _, _, err := a.pruneStorage(&tt.cfg, now); err != nil {
+				t.Fatal(err)
+			}
+			var left []string
+			for _, dir := range []string{recordingsDir, a.getHistoryDir()} {
+				entries, _ := os.ReadDir(dir)
+				for _, e := range entries {
+					left = append(left, e.Name())
+				}
+			}
+			if !slices.Equal(left, tt.want) {
+				t.Errorf("files left = %v, want %v", left, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for _, tt := range []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+		{3 << 30, "3.0 GB"},
+	} {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"dict/audio"
+)
+
+// Re-transcription sends one turn's audio from the session recording through
+// a new streaming session, with the current settings, and swaps the text
+// that comes back in for the turn's, such as when a network hiccup garbled
+// it.
+
+const revisionRetranscribed = "Re-transcribed"
+
+// retranscribeTurn re-transcribes a turn from its recorded audio.
+func (a *App) retranscribeTurn(turn Turn) {
+	if turn.Audio == "" {
+		a.updateStatus(tr("No audio was recorded for this turn (see Record session audio in Settings)"))
+		return
+	}
+	if a.recording.Load() {
+		a.updateStatus(tr("Stop recording before re-transcribing a turn"))
+		return
+	}
+	cfg := a.settings()
+	a.updateStatus(tr("Re-transcribing %s – %s...", clockTime(turn.AudioStart), clockTime(turn.AudioEnd)))
+	go func() {
+		text, err := a.transcribeTurnAudio(cfg, turn)
+		fyne.Do(func() {
+			if err != nil {
+				slog.Error("re-transcription failed", "path", turn.Audio, "err", err)
+				a.updateStatus(tr("Re-transcription failed: %s", err))
+				a.showError(err)
+				return
+			}
+			a.replaceTurnText(turn, text)
+		})
+	}()
+}
+
+// transcribeTurnAudio streams a turn's audio, with the padding replay uses,
+// and returns the formatted text of the turns heard in it.
+func (a *App) transcribeTurnAudio(cfg *Settings, turn Turn) (string, error) {
+	pcm, err := audio.ReadSegment(turn.Audio, turn.AudioStart-replayPadding, turn.AudioEnd+replayPadding)
+	if err != nil {
+		return "", err
+	}
+	streamer, err := a.newStreamer(cfg)
+	if err != nil {
+		return "", err
+	}
+	opts := streamOptions(cfg)
+	opts.FormatTurns = true
+	conn, err := streamer.Connect(opts)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	// Each turn's formatted text replaces its unformatted text
+	var texts []string
+	received := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := conn.Receive()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				received <- err
+				return
+			}
+			if msg.Type == "Turn" && msg.EndOfTurn && msg.TurnOrder >= 0 {
+				for len(texts) <= msg.TurnOrder {
+					texts = append(texts, "")
+				}
+				texts[msg.TurnOrder] = msg.Transcript
+			}
+		}
+	}()
+
+	// Sent at the pace it was spoken, as from the microphone
+	size, minimum := chunkSizes(chunkPeriod(cfg), streamer)
+	ticker := time.NewTicker(audio.Duration(int64(size)))
+	defer ticker.Stop()
+	for len(pcm) > 0 {
+		chunk := pcm[:min(size, len(pcm))]
+		pcm = pcm[len(chunk):]
+		if short := minimum - len(chunk); short > 0 {
+			chunk = append(chunk[:len(chunk):len(chunk)], make([]byte, short)...)
+		}
+		if err := conn.SendAudio(chunk); err != nil {
+			return "", err
+		}
+		<-ticker.C
+	}
+	if err := conn.EndTurn(); err != nil {
+		return "", err
+	}
+	if err := conn.Terminate(); err != nil {
+		return "", err
+	}
+	select {
+	case err := <-received:
+		if err != nil {
+			return "", err
+		}
+	case <-time.After(terminateTimeout):
+		return "", errors.New("timed out waiting for the transcript")
+	}
+
+	var parts []string
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", errors.New("no speech was recognized in the turn's audio")
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// replaceTurnText cleans up a turn's new text as dictated turns are, and
+// swaps it in for the old in the session's turns and where the old text
+// last appears in the transcript.
+func (a *App) replaceTurnText(turn Turn, text string) {
+	a.mu.Lock()
+	text = a.cleanTurnText(a.formatNumbers(newPhraseStripper(a.stripPhrasesList()).strip(a.correctTranscript(text))), true)
+	text = localizePunctuation(a.language, text)
+	for i := range a.turns {
+		if a.turns[i].Session == turn.Session && a.turns[i].Stream == turn.Stream && a.turns[i].Order == turn.Order {
+			a.turns[i].Text = text
+		}
+	}
+	a.mu.Unlock()
+	a.refreshTimeline()
+
+	current := a.textArea.Text
+	i := strings.LastIndex(current, turn.Text)
+	if turn.Text == "" || i < 0 {
+		a.reportResult(tr("Re-transcribed, but the turn was edited out of the transcript: %s", text))
+		return
+	}
+	a.previousText = current
+	a.textArea.SetText(current[:i] + text + current[i+len(turn.Text):])
+	a.undoBtn.Enable()
+	a.addRevision(revisionRetranscribed)
+	a.reportResult(tr("Turn re-transcribed"))
+}
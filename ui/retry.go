@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"dict/apierr"
+)
+
+const (
+	defaultLLMRetries = 3
+	maxLLMRetries     = 10
+	retryBaseDelay    = time.Second
+	retryMaxDelay     = time.Minute
+)
+
+// backoffDelay is the wait before retry number attempt (from 1): exponential
+// with ±50% jitter so clients don't retry in lockstep.
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	jitter := 0.5 + rand.Float64()
+	return min(time.Duration(float64(delay)*jitter), retryMaxDelay)
+}
+
+// withRetries calls call until it succeeds, fails with an error that isn't
+// retryable, has been retried maxRetries times, or ctx is done. Waits are
+// shown in the status bar.
+func (a *App) withRetries(ctx context.Context, name string, maxRetries int, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		var retryable *apierr.RetryableError
+		if err == nil || !errors.As(err, &retryable) || attempt > maxRetries {
+			return err
+		}
+
+		delay := backoffDelay(attempt)
+		if retryable.RetryAfter > 0 {
+			delay = min(retryable.RetryAfter, retryMaxDelay)
+		}
+		slog.Warn("retrying request", "request", name, "attempt", attempt, "of", maxRetries, "delay", delay, "err", err)
+		status := fmt.Sprintf("%s failed, retrying in %.0fs… (retry %d of %d)", name, delay.Seconds(), attempt, maxRetries)
+		fyne.Do(func() { a.updateStatus(status) })
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Revision sources besides revisionTranscript and prompt or pipeline names.
+const (
+	revisionEdited   = "Edited"
+	revisionUndo     = "Undo"
+	revisionRestored = "Restored"
+)
+
+// addRevision saves the transcript as a new revision of the current
+// document after a material change, such as LLM processing. Typing isn't
+// saved as it happens; if the text was edited since the last revision, the
+// edited text is saved first so the diff shows only this change.
+func (a *App) addRevision(source string) {
+	text := a.textArea.Text
+	if a.document == nil {
+		now := time.Now()
+		a.mu.RLock()
+		a.document = &HistorySession{ID: now.Format("20060102-150405"), Title: a.sessionTitle, Attendees: a.sessionAttendees, Started: now, Ended: now}
+		a.mu.RUnlock()
+	}
+	h := a.document
+	now := time.Now()
+	switch before := a.previousText; {
+	case before == "":
+	case len(h.Revisions) == 0:
+		h.Revisions = append(h.Revisions, Revision{Created: now, Source: revisionTranscript, Text: before})
+	case h.latest().Text != before:
+		h.Revisions = append(h.Revisions, Revision{Created: now, Source: revisionEdited, Text: before})
+	}
+	if len(h.Revisions) > 0 && h.latest().Text == text {
+		return
+	}
+	h.Revisions = append(h.Revisions, Revision{Created: now, Source: source, Text: text})
+	if err := a.saveHistorySession(h); err != nil {
+		slog.Error("failed to save revision", "id", h.ID, "err", err)
+	}
+}
+
+// showRevisions browses the current transcript's revisions.
+func (a *App) showRevisions() {
+	if a.document == nil || len(a.document.Revisions) < 2 {
+		dialog.ShowInformation(tr("Revisions"), tr("Revisions are kept when the transcript is processed, translated or replaced.\nThere are none for this transcript yet."), a.window)
+		return
+	}
+	a.showRevisionBrowser(a.document, nil)
+}
+
+// showRevisionBrowser lists a session's revisions, newest first, with the
+// changes each made to the one before it. Restoring a revision adds a copy
+// of it as the newest, so nothing is lost; for the current document it also
+// replaces the transcript. refresh is called after a restore.
+func (a *App) showRevisionBrowser(h *HistorySession, refresh func()) {
+	summary := widget.NewLabel(tr("Select a revision to see what it changed."))
+	changes := widget.NewRichText()
+	changes.Wrapping = fyne.TextWrapWord
+	selected := -1
+
+	// Revisions are listed newest first
+	index := func(id widget.ListItemID) int { return len(h.Revisions) - 1 - id }
+	list := widget.NewList(
+		func() int { return len(h.Revisions) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			rev := h.Revisions[index(id)]
+			obj.(*widget.Label).SetText(tr("%s — %s (%d words)", rev.Created.Format("2 Jan 15:04:05"), rev.Source, countWords(rev.Text)))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = index(id)
+		rev := h.Revisions[selected]
+		if selected == 0 {
+			summary.SetText(tr("The original text"))
+			changes.Segments = []widget.RichTextSegment{&widget.TextSegment{Style: widget.RichTextStyleInline, Text: rev.Text}}
+			changes.Refresh()
+			return
+		}
+		chunks := diffWords(h.Revisions[selected-1].Text, rev.Text)
+		deleted, inserted := diffSummary(chunks)
+		summary.SetText(tr("Changes from the previous revision: %d words removed, %d added", deleted, inserted))
+		changes.Segments = diffSegments(chunks)
+		changes.Refresh()
+	}
+
+	var d dialog.Dialog
+	restoreBtn := widget.NewButtonWithIcon(tr("Restore"), theme.HistoryIcon(), func() {
+		if selected < 0 || selected == len(h.Revisions)-1 {
+			return
+		}
+		rev := h.Revisions[selected]
+		source := fmt.Sprintf("%s (%s, %s)", revisionRestored, rev.Source, rev.Created.Format("15:04:05"))
+		if h == a.document {
+			a.previousText = a.textArea.Text
+			a.textArea.SetText(rev.Text)
+			a.undoBtn.Enable()
+			a.addRevision(source)
+		} else {
+			h.Revisions = append(h.Revisions, Revision{Created: time.Now(), Source: source, Text: rev.Text})
+			if err := a.saveHistorySession(h); err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+		}
+		a.updateStatus(tr("Restored the revision from %s", rev.Created.Format("15:04:05")))
+		if refresh != nil {
+			refresh()
+		}
+		d.Hide()
+	})
+	restoreBtn.Importance = widget.HighImportance
+
+	top := container.NewBorder(nil, nil, nil, restoreBtn, summary)
+	split := container.NewVSplit(list, container.NewBorder(top, nil, nil, nil, container.NewVScroll(changes)))
+	split.Offset = 0.3
+	title := "Revisions"
+	if h.Title != "" {
+		title += ": " + h.Title
+	}
+	d = dialog.NewCustom(title, tr("Close"), split, a.window)
+	d.Resize(fyne.NewSize(720, 560))
+	d.Show()
+	list.Select(0)
+}
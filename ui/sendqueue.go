@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"dict/audio"
+	"dict/stt"
+)
+
+const (
+	sendQueueAudio  = 5 * time.Second // Capture periods queued before the oldest is dropped
+	slowBacklog     = time.Second     // Audio waiting to be sent that counts as a slow network
+	dropLogInterval = 100             // Chunks dropped between warnings
+
+	// Audio per capture period, which users can trade: longer periods cost
+	// less CPU and fewer messages, shorter ones cut latency
+	defaultChunkMillis = 50
+	minChunkMillis     = 20
+	maxChunkMillis     = 200
+)
+
+// Each stream has one sender goroutine, the only one that writes messages to
+// its connection, since a WebSocket connection allows one writer at a time.
+// Audio reaches it through a bounded queue, and the messages that end the
+// session through a command channel. Pings may be sent from any goroutine.
+
+// chunkPeriod is the capture period cfg asks for.
+func chunkPeriod(cfg *Settings) time.Duration {
+	if cfg.ChunkMillis <= 0 {
+		return defaultChunkMillis * time.Millisecond
+	}
+	return time.Duration(cfg.ChunkMillis) * time.Millisecond
+}
+
+// chunkSizes returns the size of the audio messages sent to streamer: a
+// capture period, kept within the service's limits, and the smallest
+// message it accepts, which the end of the audio is padded to.
+func chunkSizes(period time.Duration, streamer stt.Streamer) (size, minimum int) {
+	size = audio.Bytes(period)
+	if l, ok := streamer.(stt.ChunkLimiter); ok {
+		lo, hi := l.ChunkLimits()
+		minimum = audio.Bytes(lo)
+		size = min(max(size, minimum), audio.Bytes(hi))
+	}
+	return size, minimum
+}
+
+// connCommand is a message for a stream's sender to send once the audio
+// queued before it has been sent.
+type connCommand struct {
+	send func(conn stt.Conn) error
+	done chan error
+}
+
+// enqueue queues captured audio for the stream's sender without blocking
+// the capture callback. When the network can't keep up and the queue is
+// full, the oldest audio is dropped. Only the capture callback calls it.
+func (st *Stream) enqueue(pcm []byte) {
+	// The device may reuse its buffer once the callback returns
+	pcm = append([]byte(nil), pcm...)
+	for {
+		select {
+		case st.queue <- pcm:
+			st.queuedBytes.Add(int64(len(pcm)))
+			return
+		default:
+		}
+		select {
+		case old := <-st.queue:
+			st.queuedBytes.Add(-int64(len(old)))
+			st.droppedBytes.Add(int64(len(old)))
+			if st.droppedChunks.Add(1)%dropLogInterval == 1 {
+				slog.Warn("network too slow, dropping oldest audio", "stream", st.index, "dropped", audio.Duration(st.droppedBytes.Load()))
+			}
+		default:
+		}
+	}
+}
+
+// startSender starts the stream's sender. It runs until stopSender, after
+// the connection has closed.
+func (a *App) startSender(st *Stream, period time.Duration) {
+	st.queue = make(chan []byte, int(sendQueueAudio/period))
+	st.commands = make(chan connCommand)
+	st.quit = make(chan struct{})
+	st.sent = make(chan struct{})
+	go a.sendLoop(st)
+}
+
+// stopSender stops the stream's sender and waits for it to finish, then
+// finishes the session recording, which only the sender writes. Capture
+// must have stopped.
+func (a *App) stopSender(st *Stream) {
+	if st.quit == nil {
+		return
+	}
+	close(st.quit)
+	<-st.sent
+	st.quit = nil
+	if st.audio != nil {
+		// Kept so turns finalized while stopping still point at it
+		st.audio.Close()
+	}
+	slog.Info("audio sender stopped", "stream", st.index, "peak_queued", audio.Duration(st.queuePeak.Load()), "dropped", audio.Duration(st.droppedBytes.Load()))
+}
+
+// sendCommand has the stream's sender send a message, such as
+// stt.Conn.Terminate, after everything queued so far, and waits until it's
+// sent.
+func (a *App) sendCommand(st *Stream, send func(conn stt.Conn) error) error {
+	if st.sent == nil {
+		return errors.New("audio sender not started")
+	}
+	done := make(chan error, 1)
+	select {
+	case st.commands <- connCommand{send: send, done: done}:
+	case <-st.sent:
+		return errors.New("audio sender stopped")
+	}
+	return <-done
+}
+
+// sendChunks sends the stream's unsent audio in messages of chunkBytes,
+// keeping back the rest until there's enough for another, or, when
+// flushing, padding it with silence to the smallest message the service
+// accepts. Only the sender calls it.
+func (a *App) sendChunks(st *Stream, conn stt.Conn, flush bool) {
+	size := st.chunkBytes
+	if size <= 0 {
+		size = len(st.unsent)
+	}
+	n := 0
+	for ; len(st.unsent)-n >= size && size > 0; n += size {
+		a.sendAudio(st, conn, st.unsent[n:n+size])
+	}
+	st.unsent = st.unsent[:copy(st.unsent, st.unsent[n:])]
+	if flush && len(st.unsent) > 0 {
+		if short := st.minChunkBytes - len(st.unsent); short > 0 {
+			st.unsent = append(st.unsent, make([]byte, short)...)
+		}
+		a.sendAudio(st, conn, st.unsent)
+		st.unsent = st.unsent[:0]
+	}
+}
+
+// sendLoop writes queued audio and commands to the stream's connection,
+// coalescing capture periods into messages of the size the service
+// prefers. Audio captured while connecting or reconnecting is held and sent
+// once connected.
+func (a *App) sendLoop(st *Stream) {
+	defer close(st.sent)
+	var chunks int
+	sendPending := func(conn stt.Conn) {
+		if len(st.pending) == 0 {
+			return
+		}
+		slog.Debug("sending audio captured while connecting", "stream", st.index, "bytes", len(st.pending))
+		// In larger messages, to catch up sooner
+		n := 0
+		for size := max(st.chunkBytes, pendingChunkBytes); len(st.pending)-n >= size; n += size {
+			a.sendAudio(st, conn, st.pending[n:n+size])
+		}
+		st.unsent = append(st.pending[n:], st.unsent...)
+		st.pending = nil
+	}
+	// Audio is held until every stream has connected, unless the session
+	// is already ending
+	send := func(pcm []byte, ending bool) {
+		queued := st.queuedBytes.Add(-int64(len(pcm))) + int64(len(pcm))
+		if queued > st.queuePeak.Load() {
+			st.queuePeak.Store(queued)
+		}
+		st.health.setBacklog(audio.Duration(queued), audio.Duration(st.droppedBytes.Load()))
+
+		conn := st.connection()
+		if conn == nil || !(a.recording.Load() || ending) {
+			// Still connecting, or reconnecting
+			st.pending = audio.KeepRecent(st.pending, st.unsent, maxPendingAudio)
+			st.pending = audio.KeepRecent(st.pending, pcm, maxPendingAudio)
+			st.unsent = st.unsent[:0]
+			return
+		}
+		sendPending(conn)
+		st.unsent = append(st.unsent, pcm...)
+		a.sendChunks(st, conn, false)
+
+		// Only log every 100th chunk to avoid spam
+		chunks++
+		if chunks%100 == 0 {
+			slog.Debug("sent audio", "stream", st.index, "chunks", chunks, "bytes", len(pcm), "queued", audio.Duration(st.queuedBytes.Load()))
+		}
+	}
+
+	for {
+		select {
+		case pcm := <-st.queue:
+			send(pcm, false)
+		case cmd := <-st.commands:
+			// Commands end the session, so everything captured goes first
+			for flushed := false; !flushed; {
+				select {
+				case pcm := <-st.queue:
+					send(pcm, true)
+				default:
+					flushed = true
+				}
+			}
+			conn := st.connection()
+			if conn == nil {
+				cmd.done <- errors.New("not connected")
+				continue
+			}
+			sendPending(conn)
+			a.sendChunks(st, conn, true)
+			cmd.done <- cmd.send(conn)
+		case <-st.quit:
+			st.health.setBacklog(0, audio.Duration(st.droppedBytes.Load()))
+			return
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"dict/audio"
+	"dict/stt"
+)
+
+type received struct {
+	kind int
+	data []byte
+}
+
+// dialTestServer connects to a WebSocket server that passes on every message
+// it receives.
+func dialTestServer(t *testing.T) (stt.Conn, <-chan received) {
+	t.Helper()
+	messages := make(chan received, 1000)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				close(messages)
+				return
+			}
+			messages <- received{kind, data}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	streamer := &stt.AssemblyAI{URL: "ws" + strings.TrimPrefix(srv.URL, "http")}
+	conn, err := streamer.Connect(stt.Options{SampleRate: audio.SampleRate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, messages
+}
+
+func chunk(i int) []byte {
+	return []byte{byte(i), byte(i >> 8)}
+}
+
+func TestSenderWritesInOrder(t *testing.T) {
+	conn, messages := dialTestServer(t)
+	a := &App{}
+	st := &Stream{}
+	recording, err := audio.Record(filepath.Join(t.TempDir(), "session.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.audio = recording
+	a.startSender(st, defaultChunkMillis*time.Millisecond)
+
+	// Audio captured before connecting is held, then sent first
+	const chunks = 60
+	captured := make(chan struct{})
+	go func() {
+		defer close(captured)
+		for i := 0; i < chunks; i++ {
+			st.enqueue(chunk(i))
+			if i == chunks/3 {
+				st.setConnection(conn)
+				a.recording.Store(true)
+			}
+			// Pings come from another goroutine meanwhile
+			conn.Ping(nil, time.Now().Add(time.Second))
+		}
+	}()
+	<-captured
+	a.recording.Store(false)
+	if err := a.sendCommand(st, stt.Conn.Terminate); err != nil {
+		t.Fatal(err)
+	}
+	a.stopSender(st)
+
+	// Held audio is sent in larger messages, so compare the bytes
+	var got []byte
+	m := <-messages
+	for ; m.kind == websocket.BinaryMessage; m = <-messages {
+		got = append(got, m.data...)
+	}
+	if string(m.data) != `{"type":"Terminate"}` {
+		t.Fatalf("last message = %q, want Terminate", m.data)
+	}
+	if len(got) != chunks*2 {
+		t.Fatalf("got %d bytes of audio, want %d", len(got), chunks*2)
+	}
+	for i := 0; i < chunks; i++ {
+		if string(got[i*2:i*2+2]) != string(chunk(i)) {
+			t.Fatalf("audio out of order at chunk %d", i)
+		}
+	}
+	if n := st.sentBytes.Load(); n != chunks*2 {
+		t.Errorf("sentBytes = %d, want %d", n, chunks*2)
+	}
+
+	// The recording holds what was sent, finished once the sender stopped
+	saved, err := audio.ReadSegment(recording.Path, 0, audio.Duration(chunks*2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != string(got) {
+		t.Errorf("recording holds %d bytes, want the %d sent", len(saved), len(got))
+	}
+	data, err := os.ReadFile(recording.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := binary.LittleEndian.Uint32(data[40:44]); size != chunks*2 {
+		t.Errorf("recording header says %d bytes, want %d", size, chunks*2)
+	}
+}
+
+func TestEnqueueDropsOldest(t *testing.T) {
+	const capacity, extra = 100, 20
+	st := &Stream{queue: make(chan []byte, capacity)}
+	for i := 0; i < capacity+extra; i++ {
+		st.enqueue(chunk(i))
+	}
+
+	if n := len(st.queue); n != capacity {
+		t.Fatalf("queue holds %d chunks, want %d", n, capacity)
+	}
+	if first := <-st.queue; string(first) != string(chunk(extra)) {
+		t.Errorf("oldest chunk kept is %v, want %v", first, chunk(extra))
+	}
+	if n := st.droppedBytes.Load(); n != extra*2 {
+		t.Errorf("droppedBytes = %d, want %d", n, extra*2)
+	}
+	if n := st.queuedBytes.Load(); n != capacity*2 {
+		t.Errorf("queuedBytes = %d, want %d", n, capacity*2)
+	}
+}
+
+func TestSenderCoalescesChunks(t *testing.T) {
+	conn, messages := dialTestServer(t)
+	a := &App{}
+	st := &Stream{chunkBytes: 6, minChunkBytes: 8}
+	st.setConnection(conn)
+	a.recording.Store(true)
+	a.startSender(st, defaultChunkMillis*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		st.enqueue(chunk(i))
+	}
+	if err := a.sendCommand(st, stt.Conn.Terminate); err != nil {
+		t.Fatal(err)
+	}
+	a.stopSender(st)
+
+	// Three full messages, then the rest padded with silence
+	var sizes []int
+	for m := <-messages; m.kind == websocket.BinaryMessage; m = <-messages {
+		sizes = append(sizes, len(m.data))
+	}
+	if want := []int{6, 6, 6, 8}; !slices.Equal(sizes, want) {
+		t.Errorf("message sizes = %v, want %v", sizes, want)
+	}
+}
+
+func TestChunkSizes(t *testing.T) {
+	assembly := &stt.AssemblyAI{}
+	for _, tt := range []struct {
+		millis    int
+		streamer  stt.Streamer
+		size, min int
+	}{
+		{50, assembly, 1600, 1600},
+		{20, assembly, 1600, 1600}, // Below AssemblyAI's 50 ms
+		{200, assembly, 6400, 1600},
+		{20, &stt.Replay{}, 640, 0},
+	} {
+		size, min := chunkSizes(time.Duration(tt.millis)*time.Millisecond, tt.streamer)
+		if size != tt.size || min != tt.min {
+			t.Errorf("chunkSizes(%d ms, %T) = %d, %d, want %d, %d", tt.millis, tt.streamer, size, min, tt.size, tt.min)
+		}
+	}
+}
+
+func TestSendCommandWithoutConnection(t *testing.T) {
+	a := &App{}
+	st := &Stream{}
+	a.startSender(st, defaultChunkMillis*time.Millisecond)
+	st.enqueue(chunk(1))
+	if err := a.sendCommand(st, stt.Conn.Terminate); err == nil {
+		t.Error("sendCommand succeeded without a connection")
+	}
+	a.stopSender(st)
+	if err := a.sendCommand(st, stt.Conn.Terminate); err == nil {
+		t.Error("sendCommand succeeded after the sender stopped")
+	}
+}
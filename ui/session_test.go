@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"dict/audio"
+)
+
+func TestSessionReplacesTurnsWithFormattedText(t *testing.T) {
+	server := newMockAssembly(t, "dictation.jsonl")
+	a := newTestApp(t, server)
+
+	fyne.DoAndWait(a.startRecording)
+	waitFor(t, "both turns formatted", func() bool {
+		return slices.Equal(a.turnTexts(), []string{"Hello world.", "This is a test."})
+	})
+	a.stopAndWait(t)
+
+	// Each turn arrives unformatted and then formatted; the second replaces
+	// the first rather than being added
+	if texts := a.turnTexts(); len(texts) != 2 {
+		t.Fatalf("turns = %q, want 2", texts)
+	}
+	if got, want := a.transcript(), "Hello world.\nThis is a test."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+
+	if n := server.connections(); n != 1 {
+		t.Errorf("connected %d times, want 1", n)
+	}
+	query, key := server.request(0)
+	if key != "test-key" {
+		t.Errorf("authorized with %q, want the API key", key)
+	}
+	if query.Get("sample_rate") != "16000" || query.Get("format_turns") != "true" {
+		t.Errorf("connected with %v, want 16 kHz formatted turns", query)
+	}
+	sent := server.receivedAudio(0)
+	if len(sent) == 0 || len(sent)%2 != 0 {
+		t.Errorf("received %d bytes of audio, want whole 16-bit samples", len(sent))
+	}
+	if texts := server.receivedTexts(); !slices.Equal(texts, []string{`{"type":"ForceEndpoint"}`, `{"type":"Terminate"}`}) {
+		t.Errorf("received %q, want ForceEndpoint then Terminate", texts)
+	}
+	// Formatted versions of a turn don't count again
+	if stats := a.streamStats(); stats.turns != 2 || stats.sent != int64(len(sent)) {
+		t.Errorf("stats = %+v, want 2 turns and the %d bytes sent", stats, len(sent))
+	}
+}
+
+func TestSessionReconnectsAfterDrop(t *testing.T) {
+	server := newMockAssembly(t, "dropped-1.jsonl", "dropped-2.jsonl")
+	a := newTestApp(t, server)
+
+	fyne.DoAndWait(a.startRecording)
+	waitFor(t, "a turn after reconnecting", func() bool {
+		return len(a.turnTexts()) == 2
+	})
+	a.stopAndWait(t)
+
+	// Turn order restarts on the new connection, so its first turn mustn't
+	// replace the one before the drop
+	if texts := a.turnTexts(); !slices.Equal(texts, []string{"First turn.", "Second turn."}) {
+		t.Errorf("turns = %q, want both in order", texts)
+	}
+	a.mu.RLock()
+	orders := []int{a.turns[0].Order, a.turns[1].Order}
+	a.mu.RUnlock()
+	if orders[0] >= orders[1] {
+		t.Errorf("turn orders = %v, want increasing", orders)
+	}
+	if n := server.connections(); n != 2 {
+		t.Errorf("connected %d times, want 2", n)
+	}
+	if len(server.receivedAudio(1)) == 0 {
+		t.Error("no audio sent after reconnecting")
+	}
+	if got, want := a.transcript(), "First turn.\nSecond turn."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestReplayMessageLog(t *testing.T) {
+	server := newMockAssembly(t)
+	a := newTestApp(t, server)
+	a.updateSettings(func(s *Settings) { s.AssemblyAPIKey = "" })
+	data, err := os.ReadFile(filepath.Join("testdata", "dictation.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := parseReplay("dictation.jsonl", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A message log needs neither an API key nor AssemblyAI
+	fyne.DoAndWait(func() { a.startRecordingWith(replaySettings(r)) })
+	waitFor(t, "both turns replayed", func() bool {
+		return slices.Equal(a.turnTexts(), []string{"Hello world.", "This is a test."})
+	})
+	a.stopAndWait(t)
+
+	if n := server.connections(); n != 0 {
+		t.Errorf("connected %d times, want none", n)
+	}
+	if got, want := a.transcript(), "Hello world.\nThis is a test."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestRetranscribeTurn(t *testing.T) {
+	server := newMockAssembly(t, "dictation.jsonl", "retranscribe.jsonl")
+	a := newTestApp(t, server)
+	a.updateSettings(func(s *Settings) { s.RecordAudio = true })
+
+	fyne.DoAndWait(a.startRecording)
+	waitFor(t, "both turns formatted", func() bool {
+		return slices.Equal(a.turnTexts(), []string{"Hello world.", "This is a test."})
+	})
+	// The recording has to reach past the first turn's end
+	waitFor(t, "the first turn recorded", func() bool {
+		return len(server.receivedAudio(0)) >= audio.Bytes(1500*time.Millisecond)
+	})
+	a.stopAndWait(t)
+
+	a.mu.RLock()
+	turn := a.turns[0]
+	a.mu.RUnlock()
+	if turn.Audio == "" {
+		t.Fatal("turn has no recorded audio")
+	}
+	fyne.DoAndWait(func() { a.retranscribeTurn(turn) })
+	waitFor(t, "the turn re-transcribed", func() bool {
+		return slices.Equal(a.turnTexts(), []string{"Hello there, world.", "This is a test."})
+	})
+	waitFor(t, "the transcript updated", func() bool {
+		return a.transcript() == "Hello there, world.\nThis is a test."
+	})
+
+	if n := server.connections(); n != 2 {
+		t.Errorf("connected %d times, want 2", n)
+	}
+	if len(server.receivedAudio(1)) == 0 {
+		t.Error("no audio sent when re-transcribing")
+	}
+}
@@ -0,0 +1,375 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const dictationAction = "dictation"
+
+// ShortcutAction is an action the user can bind to a key.
+type ShortcutAction struct {
+	ID      string
+	Name    string
+	Default string
+}
+
+var shortcutActions = []ShortcutAction{
+	{"record", trMark("Start or stop recording"), "Ctrl+R"},
+	{"clear", trMark("Clear the transcript"), "Ctrl+L"},
+	{"copy", trMark("Copy the transcript"), "Ctrl+C"},
+	{"process", trMark("Process with the LLM"), "Ctrl+P"},
+	{"compare", trMark("Compare the LLM model with model B"), "Ctrl+Shift+P"},
+	{"translate", trMark("Translate the transcript"), "Ctrl+T"},
+	{"undo", trMark("Undo the last LLM rewrite"), "Ctrl+Z"},
+	{"help", trMark("Open help"), "Ctrl+/"},
+	{"inspector", trMark("Open the protocol inspector"), "Ctrl+Shift+I"},
+	{"palette", trMark("Open the command palette"), "Ctrl+K"},
+	{"mini", trMark("Switch mini mode on or off"), "Ctrl+Shift+M"},
+	{"zoomIn", trMark("Make the transcript text bigger"), "Ctrl+="},
+	{"zoomOut", trMark("Make the transcript text smaller"), "Ctrl+-"},
+	{"zoomReset", trMark("Reset the transcript text size"), "Ctrl+0"},
+	{"find", trMark("Find in the transcript"), "Ctrl+F"},
+	{"replace", trMark("Find and replace in the transcript"), "Ctrl+H"},
+	{"snippet", trMark("Dictate an instant snippet to copy or type"), "Ctrl+Shift+Space"},
+	{"readAloud", trMark("Read the transcript aloud from the caret"), "Ctrl+Shift+R"},
+	{"spelling", trMark("Check the transcript's spelling"), "F7"},
+	{"newTab", trMark("Open a new transcript tab"), "Ctrl+N"},
+	{"closeTab", trMark("Close the transcript tab"), "Ctrl+W"},
+	{"focus", trMark("Move keyboard focus to the transcript"), "F6"},
+	{dictationAction, trMark("Dictation key: tap to record, hold for commands"), "F9"},
+}
+
+// shortcutHandlers run each action. They're set in init, as the handlers
+// lead back to the settings and so to shortcutActions. The dictation key has
+// none, as it's handled on key down and up.
+var shortcutHandlers map[string]func(a *App)
+
+func init() {
+	shortcutHandlers = map[string]func(a *App){
+		"record":    (*App).toggleRecording,
+		"clear":     (*App).clearText,
+		"copy":      (*App).copyText,
+		"process":   (*App).processWithLLM,
+		"compare":   (*App).compareModels,
+		"translate": (*App).showTranslate,
+		"undo":      (*App).undoText,
+		"help":      (*App).showHelp,
+		"inspector": (*App).showInspector,
+		"palette":   (*App).showCommandPalette,
+		"mini":      (*App).toggleMiniMode,
+		"zoomIn":    (*App).zoomIn,
+		"zoomOut":   (*App).zoomOut,
+		"zoomReset": (*App).zoomReset,
+		"find":      (*App).showFind,
+		"replace":   (*App).showReplace,
+		"snippet":   (*App).startSnippet,
+		"readAloud": (*App).toggleReadAloud,
+		"spelling":  (*App).toggleSpelling,
+		"newTab":    (*App).newTab,
+		"closeTab":  (*App).closeCurrentTab,
+		"focus":     (*App).focusTranscript,
+	}
+}
+
+func defaultShortcuts() map[string]string {
+	shortcuts := make(map[string]string)
+	for _, action := range shortcutActions {
+		shortcuts[action.ID] = action.Default
+	}
+	return shortcuts
+}
+
+// Binding is a key with modifiers, written like "Ctrl+Shift+P".
+type Binding struct {
+	Key      fyne.KeyName
+	Modifier fyne.KeyModifier
+}
+
+var modifierNames = []struct {
+	name     string
+	modifier fyne.KeyModifier
+}{
+	{"Ctrl", fyne.KeyModifierControl},
+	{"Alt", fyne.KeyModifierAlt},
+	{"Shift", fyne.KeyModifierShift},
+	{"Super", fyne.KeyModifierSuper},
+}
+
+var functionKeys = []fyne.KeyName{
+	fyne.KeyF1, fyne.KeyF2, fyne.KeyF3, fyne.KeyF4, fyne.KeyF5, fyne.KeyF6,
+	fyne.KeyF7, fyne.KeyF8, fyne.KeyF9, fyne.KeyF10, fyne.KeyF11, fyne.KeyF12,
+}
+
+var namedKeys = map[string]fyne.KeyName{
+	"SPACE": fyne.KeySpace, "TAB": fyne.KeyTab, "ENTER": fyne.KeyReturn, "RETURN": fyne.KeyReturn,
+	"INSERT": fyne.KeyInsert, "DELETE": fyne.KeyDelete, "HOME": fyne.KeyHome, "END": fyne.KeyEnd,
+	"PAGEUP": fyne.KeyPageUp, "PAGEDOWN": fyne.KeyPageDown,
+}
+
+func isFunctionKey(key fyne.KeyName) bool {
+	for _, k := range functionKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBinding reads a binding such as "Ctrl+Shift+P" or "F9". Keys without
+// modifiers are limited to F1–F12 so they can't interfere with typing. An
+// empty binding leaves the action unbound.
+func parseBinding(text string) (Binding, error) {
+	var b Binding
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return b, nil
+	}
+	parts := strings.Split(text, "+")
+	if strings.HasSuffix(text, "++") {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	for _, part := range parts[:len(parts)-1] {
+		found := false
+		for _, m := range modifierNames {
+			if strings.EqualFold(strings.TrimSpace(part), m.name) || (m.name == "Ctrl" && strings.EqualFold(part, "Control")) || (m.name == "Super" && strings.EqualFold(part, "Cmd")) {
+				b.Modifier |= m.modifier
+				found = true
+			}
+		}
+		if !found {
+			return b, fmt.Errorf("unknown modifier %q in shortcut %q", part, text)
+		}
+	}
+
+	key := strings.ToUpper(strings.TrimSpace(parts[len(parts)-1]))
+	switch {
+	case namedKeys[key] != "":
+		b.Key = namedKeys[key]
+	case isFunctionKey(fyne.KeyName(key)):
+		b.Key = fyne.KeyName(key)
+	case len([]rune(key)) == 1:
+		b.Key = fyne.KeyName(key)
+	default:
+		return b, fmt.Errorf("unknown key %q in shortcut %q", key, text)
+	}
+	if b.Modifier == 0 && !isFunctionKey(b.Key) {
+		return b, fmt.Errorf("shortcut %q needs a modifier such as Ctrl, unless it's a function key", text)
+	}
+	return b, nil
+}
+
+func (b Binding) String() string {
+	if b.Key == "" {
+		return ""
+	}
+	var parts []string
+	for _, m := range modifierNames {
+		if b.Modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	key := string(b.Key)
+	for name, k := range namedKeys {
+		if k == b.Key && name != "RETURN" {
+			key = name[:1] + strings.ToLower(name[1:])
+		}
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// shortcut returns the fyne shortcut the window sends for the binding.
+// Platform shortcuts such as Ctrl+C arrive as fyne.ShortcutCopy rather than
+// a custom shortcut.
+func (b Binding) shortcut() fyne.Shortcut {
+	if b.Modifier == fyne.KeyModifierShortcutDefault {
+		switch b.Key {
+		case fyne.KeyC:
+			return &fyne.ShortcutCopy{}
+		case fyne.KeyX:
+			return &fyne.ShortcutCut{}
+		case fyne.KeyV:
+			return &fyne.ShortcutPaste{}
+		case fyne.KeyZ:
+			return &fyne.ShortcutUndo{}
+		case fyne.KeyY:
+			return &fyne.ShortcutRedo{}
+		case fyne.KeyA:
+			return &fyne.ShortcutSelectAll{}
+		}
+	}
+	return &desktop.CustomShortcut{KeyName: b.Key, Modifier: b.Modifier}
+}
+
+// Keys the text area uses with modifiers to move the caret and edit.
+var editingKeys = map[fyne.KeyName]bool{
+	fyne.KeyLeft: true, fyne.KeyRight: true, fyne.KeyUp: true, fyne.KeyDown: true,
+	fyne.KeyHome: true, fyne.KeyEnd: true, fyne.KeyPageUp: true, fyne.KeyPageDown: true,
+	fyne.KeyBackspace: true, fyne.KeyDelete: true,
+}
+
+// isEditingShortcut reports whether a shortcut edits text, so the text area
+// keeps it while focused even if an action is bound to it.
+func isEditingShortcut(shortcut fyne.Shortcut) bool {
+	switch s := shortcut.(type) {
+	case *desktop.CustomShortcut:
+		return editingKeys[s.KeyName]
+	case *fyne.ShortcutCopy, *fyne.ShortcutCut, *fyne.ShortcutPaste, *fyne.ShortcutUndo, *fyne.ShortcutRedo, *fyne.ShortcutSelectAll:
+		return true
+	}
+	return false
+}
+
+// validateShortcuts checks every binding parses and no two actions share one.
+func validateShortcuts(shortcuts map[string]string) error {
+	used := make(map[Binding]string)
+	for _, action := range shortcutActions {
+		b, err := parseBinding(shortcuts[action.ID])
+		if err != nil {
+			return err
+		}
+		if b.Key == "" {
+			continue
+		}
+		if action.ID == dictationAction && b.Modifier != 0 {
+			return fmt.Errorf("the dictation key must be a function key without modifiers, as it's held down")
+		}
+		if other, ok := used[b]; ok {
+			return fmt.Errorf("%s is used for both %q and %q", b, other, action.Name)
+		}
+		used[b] = action.Name
+	}
+	return nil
+}
+
+// binding returns the key bound to an action, if any.
+func (s *Settings) binding(id string) Binding {
+	b, err := parseBinding(s.Shortcuts[id])
+	if err != nil {
+		slog.Warn("ignoring invalid shortcut", "action", id, "shortcut", s.Shortcuts[id], "err", err)
+		return Binding{}
+	}
+	return b
+}
+
+// applyShortcuts (re)binds every action to the keys in the settings and
+// updates the button tooltips to match.
+func (a *App) applyShortcuts() {
+	c := a.window.Canvas()
+	for _, shortcut := range a.boundShortcuts {
+		c.RemoveShortcut(shortcut)
+	}
+	a.boundShortcuts = nil
+	a.functionKeys = make(map[fyne.KeyName]func(*App))
+	a.shortcutRuns = make(map[string]func(*App))
+
+	cfg := a.settings()
+	for _, action := range shortcutActions {
+		b := cfg.binding(action.ID)
+		if btn, ok := a.shortcutButtons[action.ID]; ok {
+			btn.Tip = a.shortcutTips[action.ID]
+			if b.Key != "" {
+				btn.Tip += " (" + b.String() + ")"
+			}
+		}
+		run := shortcutHandlers[action.ID]
+		if b.Key == "" || run == nil {
+			continue
+		}
+		if b.Modifier == 0 {
+			a.functionKeys[b.Key] = run
+			continue
+		}
+		shortcut := b.shortcut()
+		c.AddShortcut(shortcut, func(_ fyne.Shortcut) { run(a) })
+		a.boundShortcuts = append(a.boundShortcuts, shortcut)
+		a.shortcutRuns[shortcut.ShortcutName()] = run
+	}
+	slog.Debug("shortcuts applied", "bound", len(a.boundShortcuts)+len(a.functionKeys))
+}
+
+// registerShortcutButton shows an action's current shortcut in a button's
+// tooltip.
+func (a *App) registerShortcutButton(id string, btn *TipButton) {
+	if a.shortcutButtons == nil {
+		a.shortcutButtons = make(map[string]*TipButton)
+		a.shortcutTips = make(map[string]string)
+	}
+	a.shortcutButtons[id] = btn
+	a.shortcutTips[id] = btn.Tip
+}
+
+// shortcutKeyDown handles function keys bound to actions and the dictation
+// key, returning true if the key was handled.
+func (a *App) shortcutKeyDown(key *fyne.KeyEvent) bool {
+	if run, ok := a.functionKeys[key.Name]; ok {
+		run(a)
+		return true
+	}
+	return a.dictationKeyDown(key)
+}
+
+// textAreaShortcut runs the action bound to a shortcut typed while the text
+// area has focus, returning true if it did. Editing shortcuts such as Ctrl+C
+// and Ctrl+Z stay with the text area.
+func (a *App) textAreaShortcut(shortcut fyne.Shortcut) bool {
+	if isEditingShortcut(shortcut) {
+		return false
+	}
+	if run, ok := a.shortcutRuns[shortcut.ShortcutName()]; ok {
+		run(a)
+		return true
+	}
+	return false
+}
+
+// shortcutsMarkdown lists the current bindings for the help window.
+func (a *App) shortcutsMarkdown() string {
+	cfg := a.settings()
+	var b strings.Builder
+	b.WriteString("# Keyboard Shortcuts\n\n")
+	for _, action := range shortcutActions {
+		if binding := cfg.binding(action.ID); binding.Key != "" {
+			fmt.Fprintf(&b, "- **%s** — %s\n", binding, strings.ToLower(action.Name[:1])+action.Name[1:])
+		}
+	}
+	b.WriteString("- **F1** (outside the text area) — open this help\n\nChange them under *Shortcuts* in Settings. While you're typing in the transcript, editing keys such as Ctrl+C, Ctrl+Z and Ctrl+A edit the text instead of running the action bound to them.")
+	return b.String()
+}
+
+// newShortcutsForm is the Shortcuts section of the settings dialog.
+func newShortcutsForm(cfg *Settings) (fyne.CanvasObject, func() map[string]string) {
+	form := widget.NewForm()
+	entries := make(map[string]*widget.Entry)
+	for _, action := range shortcutActions {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(tr("Unbound, e.g. %s", action.Default))
+		entry.SetText(cfg.Shortcuts[action.ID])
+		entries[action.ID] = entry
+		form.Append(tr(action.Name), entry)
+	}
+	reset := widget.NewButton(tr("Reset to Defaults"), func() {
+		for _, action := range shortcutActions {
+			entries[action.ID].SetText(action.Default)
+		}
+	})
+	read := func() map[string]string {
+		shortcuts := make(map[string]string)
+		for _, action := range shortcutActions {
+			b, err := parseBinding(entries[action.ID].Text)
+			if err != nil {
+				shortcuts[action.ID] = strings.TrimSpace(entries[action.ID].Text) // Reported by validateShortcuts
+				continue
+			}
+			shortcuts[action.ID] = b.String()
+		}
+		return shortcuts
+	}
+	return container.NewVBox(form, container.NewHBox(reset)), read
+}
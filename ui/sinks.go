@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default sink templates. The webhook's default sends JSON.
+const (
+	defaultFileSinkTemplate    = `{{.Timestamp.Format "15:04:05"}} {{if .Speaker}}{{.Speaker}}: {{end}}{{.Text}}`
+	defaultPipeSinkTemplate    = `{{.Text}}`
+	defaultWebhookSinkTemplate = `{"text": {{json .Text}}, "timestamp": {{json .Timestamp}}, "speaker": {{json .Speaker}}, "session": {{.Session}}, "confidence": {{.Confidence}}}`
+)
+
+// SinkTurn is the data a sink template formats for each finalized turn.
+type SinkTurn struct {
+	Text       string
+	Timestamp  time.Time
+	Speaker    string
+	Session    int
+	Confidence float64
+	Turn       int  // Order within the session
+	Continues  bool // Carries on the previous turn's sentence
+}
+
+// Sink receives each finalized turn while recording.
+type Sink interface {
+	name() string
+	send(turn SinkTurn) error
+}
+
+// FileSink appends one formatted line per turn to a file.
+type FileSink struct {
+	path string
+	tmpl *template.Template
+}
+
+func (s *FileSink) name() string { return "file " + s.path }
+
+func (s *FileSink) send(turn SinkTurn) error {
+	line, err := renderSinkTemplate(s.tmpl, turn)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	return nil
+}
+
+// PipeSink writes one formatted line per turn to a named pipe or a Unix
+// socket, for editors and scripts listening on a local path. A pipe with no
+// reader is an error rather than a wait, so a closed listener can't hold up
+// the other sinks. With editor set, lines are editor protocol messages
+// instead.
+type PipeSink struct {
+	path   string
+	tmpl   *template.Template
+	editor bool
+}
+
+func (s *PipeSink) name() string { return "pipe " + s.path }
+
+func (s *PipeSink) send(turn SinkTurn) error {
+	var line string
+	var err error
+	if s.editor {
+		line, err = editorLine(turn)
+	} else {
+		line, err = renderSinkTemplate(s.tmpl, turn)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	var w interface {
+		Write([]byte) (int, error)
+		Close() error
+	}
+	if info, err := os.Stat(s.path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.DialTimeout("unix", s.path, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to connect to socket: %v", err)
+		}
+		conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		w = conn
+	} else {
+		f, err := openPipe(s.path)
+		if err != nil {
+			return fmt.Errorf("failed to open pipe: %v", err)
+		}
+		w = f
+	}
+	defer w.Close()
+	if _, err := w.Write([]byte(line)); err != nil {
+		return fmt.Errorf("failed to write to pipe: %v", err)
+	}
+	return nil
+}
+
+// WebhookSink POSTs each formatted turn to a URL.
+type WebhookSink struct {
+	url    string
+	tmpl   *template.Template
+	client *http.Client
+}
+
+func (s *WebhookSink) name() string { return "webhook " + s.url }
+
+func (s *WebhookSink) send(turn SinkTurn) error {
+	body, err := renderSinkTemplate(s.tmpl, turn)
+	if err != nil {
+		return err
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		contentType = "application/json"
+	}
+
+	resp, err := s.client.Post(s.url, contentType, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func renderSinkTemplate(tmpl *template.Template, turn SinkTurn) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, turn); err != nil {
+		return "", fmt.Errorf("failed to format turn: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// parseSinkTemplate parses a user sink template, falling back to def when the
+// template is empty.
+func parseSinkTemplate(name, text, def string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = def
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+// configuredSinks builds the sinks enabled in settings. Webhooks are sent
+// with client.
+func configuredSinks(cfg *Settings, client *http.Client) ([]Sink, error) {
+	var sinks []Sink
+	funcs := localeFor(cfg.Locale).funcs()
+	if cfg.SinkFilePath != "" {
+		tmpl, err := parseSinkTemplate("output file", cfg.SinkFileTemplate, defaultFileSinkTemplate)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, &FileSink{path: cfg.SinkFilePath, tmpl: tmpl.Funcs(funcs)})
+	}
+	if cfg.SinkPipePath != "" {
+		tmpl, err := parseSinkTemplate("pipe", cfg.SinkPipeTemplate, defaultPipeSinkTemplate)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, &PipeSink{path: cfg.SinkPipePath, tmpl: tmpl.Funcs(funcs), editor: cfg.SinkPipeFormat == pipeFormatEditor})
+	}
+	if cfg.SinkWebhookURL != "" {
+		tmpl, err := parseSinkTemplate("webhook", cfg.SinkWebhookTemplate, defaultWebhookSinkTemplate)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, &WebhookSink{url: cfg.SinkWebhookURL, tmpl: tmpl.Funcs(funcs), client: client})
+	}
+	return sinks, nil
+}
+
+// sendToSinks queues a finalized turn for the output sinks. Turns are
+// delivered in order by a single worker so slow sinks never block the
+// transcript. Caller must hold a.mu.
+func (a *App) sendToSinks(turn Turn) {
+	if cfg := a.settings(); cfg.SinkFilePath == "" && cfg.SinkPipePath == "" && cfg.SinkWebhookURL == "" {
+		return
+	}
+	if a.sinkQueue == nil {
+		a.sinkQueue = make(chan SinkTurn, 100)
+		go a.runSinks(a.sinkQueue)
+	}
+
+	select {
+	case a.sinkQueue <- SinkTurn{
+		Text:       turn.Text,
+		Timestamp:  turn.Start,
+		Speaker:    turn.Speaker,
+		Session:    turn.Session,
+		Confidence: turn.Confidence,
+		Turn:       turn.Order,
+	}:
+	default:
+		slog.Warn("output sink queue full, dropping turn", "turn", turn.Order)
+	}
+}
+
+func (a *App) runSinks(queue chan SinkTurn) {
+	var prev *SinkTurn
+	for turn := range queue {
+		turn.Continues = continuesSentence(prev, turn)
+		sent := turn
+		prev = &sent
+		client, err := a.httpClient(10 * time.Second)
+		if err != nil {
+			slog.Error("network settings invalid", "err", err)
+			continue
+		}
+		sinks, err := configuredSinks(a.settings(), client)
+		if err != nil {
+			slog.Error("output sinks misconfigured", "err", err)
+			continue
+		}
+		for _, sink := range sinks {
+			if a.breakers.allow(sink.name()) != nil {
+				continue
+			}
+			err := sink.send(turn)
+			if err != nil {
+				slog.Warn("failed to send turn to sink", "sink", sink.name(), "err", err)
+			}
+			a.breakers.record(sink.name(), err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testSinkTurn = SinkTurn{
+	Text:       `Say "hi"`,
+	Timestamp:  time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC),
+	Speaker:    "Ana",
+	Session:    2,
+	Confidence: 0.5,
+}
+
+func TestSinkTemplates(t *testing.T) {
+	for _, tt := range []struct {
+		name, text, def, want string
+	}{
+		{"file default", "", defaultFileSinkTemplate, `14:07:09 Ana: Say "hi"`},
+		{"webhook default", " ", defaultWebhookSinkTemplate,
+			`{"text": "Say \"hi\"", "timestamp": "2024-03-05T14:07:09Z", "speaker": "Ana", "session": 2, "confidence": 0.5}`},
+		{"custom", "[{{.Session}}] {{.Text}}", defaultFileSinkTemplate, `[2] Say "hi"`},
+	} {
+		tmpl, err := parseSinkTemplate(tt.name, tt.text, tt.def)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, err := renderSinkTemplate(tmpl, testSinkTurn)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := parseSinkTemplate("file", "{{.Text", defaultFileSinkTemplate); err == nil {
+		t.Error("parseSinkTemplate accepted an unclosed action")
+	}
+	tmpl, _ := parseSinkTemplate("file", "{{.Missing}}", defaultFileSinkTemplate)
+	if _, err := renderSinkTemplate(tmpl, testSinkTurn); err == nil {
+		t.Error("renderSinkTemplate succeeded with an unknown field")
+	}
+}
+
+func TestFileSinkAppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	tmpl, _ := parseSinkTemplate("file", "{{.Text}}", defaultFileSinkTemplate)
+	sink := &FileSink{path: path, tmpl: tmpl}
+	for _, text := range []string{"one", "two\n"} {
+		if err := sink.send(SinkTurn{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "one\ntwo\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookSinkContentType(t *testing.T) {
+	var contentType, body string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		template, wantType string
+	}{
+		{"", "application/json"},
+		{"{{.Text}}", "text/plain; charset=utf-8"},
+		{` [{{json .Text}}]`, "application/json"},
+	} {
+		tmpl, _ := parseSinkTemplate("webhook", tt.template, defaultWebhookSinkTemplate)
+		sink := &WebhookSink{url: srv.URL, tmpl: tmpl, client: srv.Client()}
+		if err := sink.send(testSinkTurn); err != nil {
+			t.Fatal(err)
+		}
+		if contentType != tt.wantType {
+			t.Errorf("template %q sent %q as %s, want %s", tt.template, body, contentType, tt.wantType)
+		}
+	}
+
+	status = http.StatusInternalServerError
+	tmpl, _ := parseSinkTemplate("webhook", "", defaultWebhookSinkTemplate)
+	sink := &WebhookSink{url: srv.URL, tmpl: tmpl, client: srv.Client()}
+	if err := sink.send(testSinkTurn); err == nil {
+		t.Error("send succeeded with a server error")
+	}
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/stt"
+)
+
+// What happens to an instant snippet's text
+const (
+	snippetCopy = "copy"
+	snippetType = "type"
+)
+
+// snippetTurnDetection ends the turn at the first short pause, trading
+// accuracy on long sentences for speed.
+var snippetTurnDetection = TurnDetection{Confidence: 0.3, MinSilenceMillis: 100, MaxSilenceMillis: 800}
+
+// snippetTimeout stops a snippet that never hears anything.
+const snippetTimeout = 15 * time.Second
+
+// snippetSettings tunes a session config for one short utterance from the
+// microphone. Turns go nowhere in the transcript; the text is handed on
+// when the turn ends.
+func snippetSettings(cfg *Settings) *Settings {
+	s := *cfg
+	s.snippet = true
+	s.TurnDetection = snippetTurnDetection
+	s.CaptureSource = captureSourceMicrophone
+	s.TurnPlacement = turnPlacementCursor
+	s.MeetingNotes = false
+	return &s
+}
+
+// snippetTurnEnded reports whether a turn message finishes a snippet: the
+// first end of turn, or its formatted version if snippets are formatted.
+func snippetTurnEnded(cfg *Settings, msg stt.Message) bool {
+	return cfg.snippet && msg.EndOfTurn && (msg.TurnIsFormatted || !cfg.SnippetFormat)
+}
+
+// startSnippet records a single short utterance, such as a search query or a
+// chat reply, and copies or types it when it ends.
+func (a *App) startSnippet() {
+	if a.recording.Load() {
+		a.updateStatus(tr("Stop recording to dictate a snippet"))
+		return
+	}
+	before := a.session
+	a.startRecordingWith(snippetSettings)
+	if a.session == before {
+		return
+	}
+	session := a.session
+	a.snippetSession = session
+	time.AfterFunc(snippetTimeout, func() {
+		fyne.Do(func() {
+			if a.recording.Load() && a.snippetSession == session {
+				a.stopRecording()
+				a.updateStatus(tr("Snippet cancelled: nothing heard"))
+			}
+		})
+	})
+}
+
+// finishSnippet stops the snippet's recording and hands on its text.
+func (a *App) finishSnippet(session int, text string) {
+	if a.snippetSession != session {
+		return
+	}
+	a.snippetSession = 0
+	a.stopRecording()
+	text = strings.TrimSpace(text)
+	if text == "" {
+		a.updateStatus(tr("Snippet cancelled: nothing heard"))
+		return
+	}
+
+	a.copyToClipboard("Snippet", text)
+	if a.settings().SnippetInject != snippetType {
+		a.updateStatus(tr("Snippet copied: %s", text))
+		return
+	}
+	go func() {
+		err := typeIntoFocusedApp(text)
+		fyne.Do(func() {
+			if err != nil {
+				slog.Warn("failed to type snippet", "err", err)
+				a.updateStatus(tr("Snippet copied; typing it failed: %s", err))
+				return
+			}
+			a.updateStatus(tr("Snippet typed: %s", text))
+		})
+	}()
+}
+
+// typeIntoFocusedApp types text into whichever window has focus, with the
+// platform's input tool.
+func typeIntoFocusedApp(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", "on run argv", "-e", `tell application "System Events" to keystroke (item 1 of argv)`, "-e", "end run", "--", text)
+	case "windows":
+		// SendKeys treats these characters as key codes unless braced
+		script := `Add-Type -AssemblyName System.Windows.Forms; $t = [Console]::In.ReadToEnd(); ` +
+			`[System.Windows.Forms.SendKeys]::SendWait([regex]::Replace($t, '[+^%~(){}\[\]]', '{$0}'))`
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+		cmd.Stdin = strings.NewReader(text)
+	default:
+		if _, err := exec.LookPath("wtype"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+			cmd = exec.Command("wtype", "--", text)
+		} else if _, err := exec.LookPath("xdotool"); err == nil {
+			cmd = exec.Command("xdotool", "type", "--clearmodifiers", "--file", "-")
+			cmd.Stdin = strings.NewReader(text)
+		} else {
+			return errors.New("install xdotool (X11) or wtype (Wayland) to type snippets")
+		}
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// newSnippetSettings is the Instant Snippets part of the settings dialog.
+func newSnippetSettings(cfg *Settings) (fyne.CanvasObject, func(s *Settings)) {
+	injectRadio := widget.NewRadioGroup([]string{tr("Copy to clipboard"), tr("Type into the focused app")}, nil)
+	injectRadio.Horizontal = true
+	injectRadio.SetSelected(tr("Copy to clipboard"))
+	if cfg.SnippetInject == snippetType {
+		injectRadio.SetSelected(tr("Type into the focused app"))
+	}
+	formatCheck := widget.NewCheck(tr("Wait for punctuation and capitals (slower)"), nil)
+	formatCheck.SetChecked(cfg.SnippetFormat)
+
+	content := container.NewVBox(injectRadio, formatCheck)
+	read := func(s *Settings) {
+		s.SnippetInject = snippetCopy
+		if injectRadio.Selected == tr("Type into the focused app") {
+			s.SnippetInject = snippetType
+		}
+		s.SnippetFormat = formatCheck.Checked
+	}
+	return content, read
+}
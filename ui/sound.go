@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"dict/audio"
+)
+
+var chimeNotes = []audio.Note{{Freq: 880, Duration: 150}, {Freq: 1320, Duration: 250}}
+
+func playChime() {
+	go func() {
+		if err := audio.Play(context.Background(), audio.Tones(chimeNotes), audio.ToneRate, nil); err != nil {
+			slog.Warn("failed to play chime", "err", err)
+		}
+	}()
+}
+
+// Cues tell users dictating into another app, with this window hidden, that
+// recording changed state: a short sound, and the window icon flashing.
+const (
+	cueStart = iota
+	cueStop
+	cueReconnect
+	cueError
+)
+
+const (
+	iconFlashes   = 3
+	flashInterval = 250 * time.Millisecond
+)
+
+var cueNotes = map[int][]audio.Note{
+	cueStart:     {{Freq: 660, Duration: 70}, {Freq: 990, Duration: 110}},
+	cueStop:      {{Freq: 990, Duration: 70}, {Freq: 660, Duration: 110}},
+	cueReconnect: {{Freq: 880, Duration: 60}, {Freq: 880, Duration: 60}},
+	cueError:     {{Freq: 220, Duration: 350}},
+}
+
+func cueIcon(kind int) fyne.Resource {
+	switch kind {
+	case cueStart:
+		return theme.NewErrorThemedResource(theme.MediaRecordIcon())
+	case cueStop:
+		return theme.MediaStopIcon()
+	case cueReconnect:
+		return theme.ViewRefreshIcon()
+	}
+	return theme.NewErrorThemedResource(theme.ErrorIcon())
+}
+
+// cue plays kind's sound and flashes the window icon, as the settings allow.
+// Call it from any goroutine.
+func (a *App) cue(kind int) {
+	cfg := a.settings()
+	if cfg.SoundCues {
+		go func() {
+			if err := audio.Play(context.Background(), audio.Tones(cueNotes[kind]), audio.ToneRate, nil); err != nil {
+				slog.Warn("failed to play cue", "err", err)
+			}
+		}()
+	}
+	if !cfg.FlashCues {
+		return
+	}
+	// A later cue's flashing takes over
+	flash := a.flashes.Add(1)
+	icon := cueIcon(kind)
+	go func() {
+		for i := 0; i < iconFlashes*2; i++ {
+			on := i%2 == 0
+			fyne.Do(func() {
+				if a.flashes.Load() != flash {
+					return
+				}
+				if on {
+					a.window.SetIcon(icon)
+				} else {
+					a.window.SetIcon(a.stateIcon())
+				}
+			})
+			time.Sleep(flashInterval)
+		}
+	}()
+}
+
+// stateIcon is the window icon between flashes: red while recording, or
+// else the app's.
+func (a *App) stateIcon() fyne.Resource {
+	if a.recording.Load() && a.settings().FlashCues {
+		return theme.NewErrorThemedResource(theme.MediaRecordIcon())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SoundsLike replaces a phrase the recognizer often gets wrong with the
+// intended word, e.g. "cooper netties" with "Kubernetes".
+type SoundsLike struct {
+	pattern *regexp.Regexp
+	written string
+}
+
+// parseSoundsLike parses one mapping per line, "heard => written". Several
+// heard forms can share a line, separated by commas. Blank lines and lines
+// starting with # are ignored.
+func parseSoundsLike(text string) ([]SoundsLike, error) {
+	var mappings []SoundsLike
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		heard, written, ok := strings.Cut(line, "=>")
+		written = strings.TrimSpace(written)
+		if !ok || written == "" {
+			return nil, fmt.Errorf("sounds-like line %d: use \"heard => written\"", i+1)
+		}
+		for _, phrase := range strings.Split(heard, ",") {
+			words := strings.Fields(phrase)
+			if len(words) == 0 {
+				return nil, fmt.Errorf("sounds-like line %d: missing the heard phrase", i+1)
+			}
+			for j, word := range words {
+				words[j] = regexp.QuoteMeta(word)
+			}
+			// Words may be split by spaces or hyphens in the transcript
+			pattern := regexp.MustCompile(`(?i)` + strings.Join(words, `[\s-]+`))
+			mappings = append(mappings, SoundsLike{pattern: pattern, written: written})
+		}
+	}
+	return mappings, nil
+}
+
+// isWordRune reports whether r is part of a word, so a match next to it
+// isn't a whole word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+}
+
+// replaceWholeWords replaces the matches of pattern that start and end on
+// word boundaries, so "cooper" doesn't match inside "coopers".
+func replaceWholeWords(pattern *regexp.Regexp, text, replacement string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if loc[0] == loc[1] || (loc[0] > 0 && isWordRune(before)) || (loc[1] < len(text) && isWordRune(after)) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(replacement)
+		last = loc[1]
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// applySoundsLike replaces misrecognized phrases in order.
+func applySoundsLike(mappings []SoundsLike, text string) string {
+	for _, m := range mappings {
+		text = replaceWholeWords(m.pattern, text, m.written)
+	}
+	return text
+}
+
+// correctTranscript applies the sounds-like mappings to a transcript from
+// the recognizer.
+func (a *App) correctTranscript(text string) string {
+	mappings, err := parseSoundsLike(a.settings().SoundsLike)
+	if err != nil {
+		// Settings validation rejects bad mappings, so only a hand-edited
+		// config gets here
+		slog.Warn("ignoring invalid sounds-like mappings", "err", err)
+		return text
+	}
+	return applySoundsLike(mappings, text)
+}
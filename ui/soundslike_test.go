@@ -0,0 +1,42 @@
+package ui
+
+import "testing"
+
+func TestApplySoundsLike(t *testing.T) {
+	mappings, err := parseSoundsLike(`
+# Heard forms can share a line
+cooper netties, cuber nettys => Kubernetes
+
+post gress => Postgres
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{"We run cooper netties.", "We run Kubernetes."},
+		{"Cuber-nettys and post  gress", "Kubernetes and Postgres"},
+		{"COOPER NETTIES", "Kubernetes"},
+		// Only whole words are replaced
+		{"The coopers netties", "The coopers netties"},
+		{"cooper nettiest", "cooper nettiest"},
+		{"nothing to fix", "nothing to fix"},
+	} {
+		if got := applySoundsLike(mappings, tt.text); got != tt.want {
+			t.Errorf("applySoundsLike(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseSoundsLikeErrors(t *testing.T) {
+	for _, text := range []string{
+		"cooper netties",
+		"cooper netties =>",
+		" , cuber => Kubernetes",
+	} {
+		if _, err := parseSoundsLike(text); err == nil {
+			t.Errorf("parseSoundsLike(%q) succeeded", text)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const maxSourceGain = 2
+
+var sourceLevelNames = map[string]string{
+	captureSourceMicrophone: trMark("Mic"),
+	captureSourceSystem:     trMark("System"),
+}
+
+// SourceControls are the mute button and level slider for one source.
+type SourceControls struct {
+	mute  *TipButton
+	level *widget.Slider
+	value *widget.Label
+}
+
+// newSourceLevelsBox builds the per-source mute and level controls shown
+// while recording a meeting, so either side can be left out for a while.
+func (a *App) newSourceLevelsBox() fyne.CanvasObject {
+	a.sourceLevels = map[string]*SourceLevel{}
+	a.sourceControls = map[string]*SourceControls{}
+	row := container.NewGridWithColumns(2)
+	for _, source := range []string{captureSourceMicrophone, captureSourceSystem} {
+		source := source
+		level := newSourceLevel()
+		c := &SourceControls{value: widget.NewLabel("")}
+		c.mute = newTipButton("", theme.VolumeUpIcon(), tr("Mute or unmute this source"), func() {
+			level.muted.Store(!level.muted.Load())
+			slog.Info("source muted", "source", source, "muted", level.muted.Load())
+			a.refreshSourceControls(source)
+		})
+		c.level = widget.NewSlider(0, maxSourceGain)
+		c.level.Step = 0.05
+		c.level.SetValue(1)
+		c.level.OnChanged = func(v float64) {
+			level.gain.Store(math.Float64bits(v))
+			a.refreshSourceControls(source)
+		}
+		a.sourceLevels[source] = level
+		a.sourceControls[source] = c
+		name := widget.NewLabel(tr(sourceLevelNames[source]))
+		row.Add(container.NewBorder(nil, nil, container.NewHBox(name, c.mute), c.value, c.level))
+		a.refreshSourceControls(source)
+	}
+	a.sourceBox = container.NewVBox(row)
+	a.sourceBox.Hide()
+	return a.sourceBox
+}
+
+func (a *App) refreshSourceControls(source string) {
+	level, c := a.sourceLevels[source], a.sourceControls[source]
+	if level.muted.Load() {
+		c.mute.SetIcon(theme.VolumeMuteIcon())
+		c.value.SetText(tr("Muted"))
+		return
+	}
+	c.mute.SetIcon(theme.VolumeUpIcon())
+	c.value.SetText(fmt.Sprintf("%d%%", int(math.Round(c.level.Value*100))))
+}
+
+// resetSourceLevels unmutes every source at full level, so a mute doesn't
+// carry over into the next session unnoticed.
+func (a *App) resetSourceLevels() {
+	for source, level := range a.sourceLevels {
+		level.muted.Store(false)
+		a.sourceControls[source].level.SetValue(1)
+		a.refreshSourceControls(source)
+	}
+}
+
+// showSourceLevels shows the controls while recording a meeting.
+func (a *App) showSourceLevels(show bool) {
+	if show {
+		a.sourceBox.Show()
+	} else {
+		a.sourceBox.Hide()
+	}
+}
+
+// sourceGain returns the current gain for a capture source.
+func (a *App) sourceGain(source string) float64 {
+	if level, ok := a.sourceLevels[source]; ok {
+		return level.factor()
+	}
+	return 1
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestSourceControlsActOnTheirSource(t *testing.T) {
+	test.NewTempApp(t)
+	a := &App{}
+	a.newSourceLevelsBox()
+	mic, system := a.sourceControls[captureSourceMicrophone], a.sourceControls[captureSourceSystem]
+
+	test.Tap(mic.mute)
+	system.level.SetValue(0.5)
+	if got := a.sourceGain(captureSourceMicrophone); got != 0 {
+		t.Errorf("muted microphone gain = %v, want 0", got)
+	}
+	if got := a.sourceGain(captureSourceSystem); got != 0.5 {
+		t.Errorf("system gain = %v, want 0.5", got)
+	}
+	if mic.value.Text != "Muted" || system.value.Text != "50%" {
+		t.Errorf("levels show %q and %q, want %q and %q", mic.value.Text, system.value.Text, "Muted", "50%")
+	}
+
+	test.Tap(mic.mute)
+	if got := a.sourceGain(captureSourceMicrophone); got != 1 || mic.value.Text != "100%" {
+		t.Errorf("unmuted microphone gain = %v, showing %q, want 1, %q", got, mic.value.Text, "100%")
+	}
+}
@@ -0,0 +1,493 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxSuggestions is how many corrections the word menu offers.
+const maxSuggestions = 8
+
+// Misspelling is a word the spell checker doesn't know.
+type Misspelling struct {
+	Start, End  int // Byte offsets in the checked text
+	Word        string
+	Suggestions []string
+}
+
+// SpellChecker talks to hunspell, or aspell, in pipe mode: the ispell
+// protocol, one line of text in and one reply per unknown word out.
+type SpellChecker struct {
+	mu   sync.Mutex
+	lang string
+	cmd  *exec.Cmd
+	in   io.WriteCloser
+	out  *bufio.Reader
+}
+
+func spellCommand(lang string) (*exec.Cmd, error) {
+	if path, err := exec.LookPath("hunspell"); err == nil {
+		args := []string{"-a"}
+		if lang != "" {
+			args = append(args, "-d", lang)
+		}
+		return exec.Command(path, args...), nil
+	}
+	if path, err := exec.LookPath("aspell"); err == nil {
+		args := []string{"-a"}
+		if lang != "" {
+			args = append(args, "--lang="+lang)
+		}
+		return exec.Command(path, args...), nil
+	}
+	return nil, errors.New("install hunspell (or aspell) and a dictionary to check spelling")
+}
+
+// startSpellChecker starts a checker for lang, the system default if empty,
+// that accepts the known words too.
+func startSpellChecker(lang string, known []string) (*SpellChecker, error) {
+	cmd, err := spellCommand(lang)
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start spell checker: %v", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start spell checker: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start spell checker: %v", err)
+	}
+	s := &SpellChecker{lang: lang, cmd: cmd, in: in, out: bufio.NewReader(stdout)}
+	// The first line is a version banner; without a dictionary there's none
+	if _, err := s.out.ReadString('\n'); err != nil {
+		s.close()
+		return nil, fmt.Errorf("spell checker failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	// Terse mode: reply only for unknown words
+	if _, err := io.WriteString(in, "!\n"); err != nil {
+		s.close()
+		return nil, fmt.Errorf("spell checker failed: %v", err)
+	}
+	for _, term := range known {
+		for _, word := range strings.Fields(term) {
+			s.accept(word)
+		}
+	}
+	return s, nil
+}
+
+// accept adds word to the checker's dictionary until it stops.
+func (s *SpellChecker) accept(word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.in, "@%s\n", word)
+	return err
+}
+
+// check returns the unknown words in text, in order.
+func (s *SpellChecker) check(text string) ([]Misspelling, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []Misspelling
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		body := strings.TrimRight(line, "\r\n")
+		// ^ stops a line that starts with a command character being taken
+		// for a command
+		if _, err := fmt.Fprintf(s.in, "^%s\n", body); err != nil {
+			return nil, fmt.Errorf("spell checker stopped: %v", err)
+		}
+		pos := 0
+		for {
+			reply, err := s.out.ReadString('\n')
+			if err != nil {
+				return nil, fmt.Errorf("spell checker stopped: %v", err)
+			}
+			reply = strings.TrimRight(reply, "\r\n")
+			if reply == "" {
+				break
+			}
+			m, ok := parseSpellReply(reply)
+			if !ok {
+				continue
+			}
+			// Replies give character offsets, which checkers count
+			// differently, so find the word from the last one instead
+			i := strings.Index(body[pos:], m.Word)
+			if i < 0 {
+				continue
+			}
+			m.Start = offset + pos + i
+			m.End = m.Start + len(m.Word)
+			pos += i + len(m.Word)
+			found = append(found, m)
+		}
+		offset += len(line)
+	}
+	return found, nil
+}
+
+// parseSpellReply reads "& word count offset: suggestion, ..." or, with no
+// suggestions, "# word offset".
+func parseSpellReply(reply string) (Misspelling, bool) {
+	fields := strings.Fields(reply)
+	if len(fields) < 3 || (fields[0] != "&" && fields[0] != "#") {
+		return Misspelling{}, false
+	}
+	m := Misspelling{Word: fields[1]}
+	if _, list, ok := strings.Cut(reply, ": "); ok && fields[0] == "&" {
+		for _, suggestion := range strings.Split(list, ", ") {
+			m.Suggestions = append(m.Suggestions, strings.TrimSpace(suggestion))
+		}
+	}
+	return m, true
+}
+
+func (s *SpellChecker) close() {
+	s.in.Close()
+	s.cmd.Wait()
+}
+
+// customTerms parses the custom vocabulary, one term per line.
+func customTerms(text string) []string {
+	var terms []string
+	for _, line := range strings.Split(text, "\n") {
+		if term := strings.TrimSpace(line); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// mergeVocabulary adds the custom terms to the team's, within AssemblyAI's
+// limits.
+func mergeVocabulary(team, custom []string) []string {
+	terms := append([]string{}, team...)
+	for _, term := range custom {
+		if len(terms) == maxKeyterms {
+			break
+		}
+		if len(term) <= maxKeytermLength && !containsFold(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// misspeltSegment shows an unknown word underlined; clicking it opens the
+// suggestions menu.
+type misspeltSegment struct {
+	word     string
+	onTapped func(fyne.Position)
+}
+
+func (s *misspeltSegment) Inline() bool    { return true }
+func (s *misspeltSegment) Textual() string { return s.word }
+
+func (s *misspeltSegment) Visual() fyne.CanvasObject {
+	w := &misspeltWord{}
+	w.ExtendBaseWidget(w)
+	s.Update(w)
+	return w
+}
+
+func (s *misspeltSegment) Update(o fyne.CanvasObject) {
+	w := o.(*misspeltWord)
+	w.word, w.onTapped = s.word, s.onTapped
+	w.Refresh()
+}
+
+func (s *misspeltSegment) Select(begin, end fyne.Position) {}
+func (s *misspeltSegment) SelectedText() string            { return "" }
+func (s *misspeltSegment) Unselect()                       {}
+
+var misspeltStyle = widget.RichTextStyle{
+	Inline:    true,
+	ColorName: theme.ColorNameError,
+	TextStyle: fyne.TextStyle{Underline: true},
+}
+
+type misspeltWord struct {
+	widget.BaseWidget
+	word     string
+	onTapped func(fyne.Position)
+	focused  bool
+}
+
+func (w *misspeltWord) CreateRenderer() fyne.WidgetRenderer {
+	text := widget.NewRichText(&widget.TextSegment{Style: misspeltStyle})
+	return &misspeltRenderer{w: w, text: text}
+}
+
+func (w *misspeltWord) Tapped(e *fyne.PointEvent) {
+	if w.onTapped != nil {
+		w.onTapped(e.AbsolutePosition)
+	}
+}
+
+func (w *misspeltWord) TappedSecondary(e *fyne.PointEvent) {
+	w.Tapped(e)
+}
+
+// Unknown words can be reached with Tab, and Space or Return opens the
+// suggestions menu below the word.
+func (w *misspeltWord) FocusGained() {
+	w.focused = true
+	w.Refresh()
+}
+
+func (w *misspeltWord) FocusLost() {
+	w.focused = false
+	w.Refresh()
+}
+
+func (w *misspeltWord) TypedRune(r rune) {
+	if r == ' ' {
+		w.openMenu()
+	}
+}
+
+func (w *misspeltWord) TypedKey(e *fyne.KeyEvent) {
+	if e.Name == fyne.KeyReturn || e.Name == fyne.KeyEnter {
+		w.openMenu()
+	}
+}
+
+func (w *misspeltWord) openMenu() {
+	if w.onTapped == nil {
+		return
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(w)
+	w.onTapped(pos.Add(fyne.NewPos(0, w.Size().Height)))
+}
+
+// misspeltRenderer draws the word as rich text without the padding around
+// it, so it sits in line with the text either side.
+type misspeltRenderer struct {
+	w    *misspeltWord
+	text *widget.RichText
+}
+
+func (r *misspeltRenderer) Layout(size fyne.Size) {
+	pad := theme.InnerPadding()
+	r.text.Move(fyne.NewPos(-pad, -pad))
+	r.text.Resize(size.Add(fyne.NewSize(pad*2, pad*2)))
+}
+
+func (r *misspeltRenderer) MinSize() fyne.Size {
+	pad := theme.InnerPadding()
+	return r.text.MinSize().Subtract(fyne.NewSize(pad*2, pad*2))
+}
+
+func (r *misspeltRenderer) Refresh() {
+	segment := r.text.Segments[0].(*widget.TextSegment)
+	segment.Text = r.w.word
+	segment.Style = misspeltStyle
+	if r.w.focused {
+		segment.Style.ColorName = theme.ColorNamePrimary
+	}
+	r.text.Refresh()
+}
+
+func (r *misspeltRenderer) Objects() []fyne.CanvasObject { return []fyne.CanvasObject{r.text} }
+func (r *misspeltRenderer) Destroy()                     {}
+
+func (a *App) newSpellingView() fyne.CanvasObject {
+	a.spellText = widget.NewRichText()
+	a.spellText.Wrapping = fyne.TextWrapWord
+	a.spellScroll = container.NewVScroll(a.spellText)
+	a.spellScroll.Hide()
+	return a.spellScroll
+}
+
+// newSpellingBox is the bar shown while checking spelling.
+func (a *App) newSpellingBox() fyne.CanvasObject {
+	a.spellCountLbl = widget.NewLabel("")
+	doneBtn := widget.NewButtonWithIcon(tr("Done"), theme.ConfirmIcon(), a.closeSpelling)
+	hint := widget.NewLabel(tr("Click an underlined word for suggestions, or Tab to it and press Space"))
+	hint.Importance = widget.LowImportance
+	a.spellBox = container.NewBorder(nil, nil, a.spellCountLbl, doneBtn, hint)
+	a.spellBox.Hide()
+	return a.spellBox
+}
+
+// toggleSpelling checks the transcript's spelling, or goes back to editing.
+func (a *App) toggleSpelling() {
+	if a.spellScroll.Visible() {
+		a.closeSpelling()
+		return
+	}
+	a.checkSpelling()
+}
+
+// checkSpelling shows the transcript with unknown words underlined.
+func (a *App) checkSpelling() {
+	if a.recording.Load() {
+		a.updateStatus(tr("Stop recording to check spelling"))
+		return
+	}
+	text := a.textArea.Text
+	if strings.TrimSpace(text) == "" {
+		a.updateStatus(tr("No text to check"))
+		return
+	}
+	a.stopReadAloud()
+	cfg := a.settings()
+	if a.spellChecker != nil && a.spellChecker.lang != cfg.SpellLanguage {
+		a.spellChecker.close()
+		a.spellChecker = nil
+	}
+	if a.spellChecker == nil {
+		checker, err := startSpellChecker(cfg.SpellLanguage, cfg.Vocabulary)
+		if err != nil {
+			a.showError(err)
+			return
+		}
+		a.spellChecker = checker
+	}
+	checker := a.spellChecker
+	a.updateStatus(tr("Checking spelling..."))
+	go func() {
+		found, err := checker.check(text)
+		fyne.Do(func() {
+			if err != nil {
+				slog.Warn("spell check failed", "err", err)
+				checker.close()
+				if a.spellChecker == checker {
+					a.spellChecker = nil
+				}
+				a.showError(err)
+				return
+			}
+			if a.textArea.Text != text || a.recording.Load() {
+				return
+			}
+			a.showSpelling(text, found)
+		})
+	}()
+}
+
+func (a *App) showSpelling(text string, found []Misspelling) {
+	var segments []widget.RichTextSegment
+	last := 0
+	for _, m := range found {
+		m := m
+		segments = append(segments,
+			&widget.TextSegment{Style: widget.RichTextStyleInline, Text: text[last:m.Start]},
+			&misspeltSegment{word: m.Word, onTapped: func(pos fyne.Position) { a.showSpellingMenu(text, m, pos) }},
+		)
+		last = m.End
+	}
+	segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleParagraph, Text: text[last:]})
+	a.spellText.Segments = segments
+	a.spellText.Refresh()
+
+	switch len(found) {
+	case 0:
+		a.spellCountLbl.SetText(tr("No unknown words"))
+	case 1:
+		a.spellCountLbl.SetText(tr("1 unknown word"))
+	default:
+		a.spellCountLbl.SetText(tr("%d unknown words", len(found)))
+	}
+	a.editView.Hide()
+	a.spellScroll.Show()
+	a.spellBox.Show()
+	a.updateStatus(tr("Spelling checked"))
+}
+
+func (a *App) showSpellingMenu(text string, m Misspelling, pos fyne.Position) {
+	var items []*fyne.MenuItem
+	for _, suggestion := range m.Suggestions[:min(len(m.Suggestions), maxSuggestions)] {
+		suggestion := suggestion
+		items = append(items, fyne.NewMenuItem(suggestion, func() { a.correctSpelling(text, m, suggestion) }))
+	}
+	if len(items) == 0 {
+		item := fyne.NewMenuItem(tr("No suggestions"), nil)
+		item.Disabled = true
+		items = append(items, item)
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(tr("Add to Dictionary"), func() { a.addToDictionary(m.Word) }),
+		fyne.NewMenuItem(tr("Ignore"), func() { a.ignoreSpelling(m.Word) }),
+		fyne.NewMenuItem(tr("Edit Here"), func() {
+			a.closeSpelling()
+			a.textArea.moveCaretTo(m.Start)
+		}),
+	)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), a.window.Canvas(), pos)
+}
+
+// correctSpelling replaces one unknown word. It can be undone like other
+// changes to the whole text.
+func (a *App) correctSpelling(text string, m Misspelling, suggestion string) {
+	if a.textArea.Text != text {
+		return
+	}
+	a.previousText = text
+	a.textArea.SetText(text[:m.Start] + suggestion + text[m.End:])
+	a.undoBtn.Enable()
+	a.checkSpelling()
+}
+
+// addToDictionary accepts word from now on, and adds it to the custom
+// vocabulary so transcription gets it right too.
+func (a *App) addToDictionary(word string) {
+	if !containsFold(customTerms(a.settings().CustomVocabulary), word) {
+		a.updateSettings(func(s *Settings) {
+			s.CustomVocabulary = strings.TrimSpace(s.CustomVocabulary + "\n" + word)
+			s.Vocabulary = mergeVocabulary(s.Vocabulary, []string{word})
+		})
+		if err := a.writeSettings(a.store, a.settings()); err != nil {
+			a.showError(fmt.Errorf("failed to save the custom vocabulary: %v", err))
+		}
+	}
+	a.ignoreSpelling(word)
+}
+
+// ignoreSpelling accepts word until the app quits.
+func (a *App) ignoreSpelling(word string) {
+	if a.spellChecker != nil {
+		if err := a.spellChecker.accept(word); err != nil {
+			slog.Warn("failed to add word to spell checker", "word", word, "err", err)
+		}
+	}
+	a.checkSpelling()
+}
+
+func (a *App) closeSpelling() {
+	if !a.spellScroll.Visible() {
+		return
+	}
+	a.spellBox.Hide()
+	a.spellScroll.Hide()
+	a.editView.Show()
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Sprint is a time-boxed dictation session with an optional word goal.
+type Sprint struct {
+	start     time.Time
+	duration  time.Duration
+	goal      int
+	baseWords int
+	goalMet   bool
+	stop      chan struct{}
+}
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func (a *App) showSprintDialog() {
+	if a.sprint != nil {
+		dialog.ShowConfirm(tr("Dictation Sprint"), tr("End the current sprint?"), func(ok bool) {
+			if ok {
+				a.endSprint("Sprint ended")
+			}
+		}, a.window)
+		return
+	}
+
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetText("15")
+	goalEntry := widget.NewEntry()
+	goalEntry.SetPlaceHolder(tr("Optional"))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Minutes"), minutesEntry),
+		widget.NewFormItem(tr("Word goal"), goalEntry),
+	}
+	dialog.ShowForm(tr("Dictation Sprint"), tr("Start"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		minutes, err := strconv.Atoi(strings.TrimSpace(minutesEntry.Text))
+		if err != nil || minutes <= 0 {
+			dialog.ShowError(fmt.Errorf("Enter the sprint length in minutes"), a.window)
+			return
+		}
+		goal := 0
+		if text := strings.TrimSpace(goalEntry.Text); text != "" {
+			goal, err = strconv.Atoi(text)
+			if err != nil || goal < 0 {
+				dialog.ShowError(fmt.Errorf("Word goal must be a positive number"), a.window)
+				return
+			}
+		}
+		a.startSprint(time.Duration(minutes)*time.Minute, goal)
+	}, a.window)
+}
+
+func (a *App) startSprint(duration time.Duration, goal int) {
+	a.sprint = &Sprint{
+		start:     time.Now(),
+		duration:  duration,
+		goal:      goal,
+		baseWords: countWords(a.textArea.Text),
+		stop:      make(chan struct{}),
+	}
+	a.sprintBar.SetValue(0)
+	a.sprintBox.Show()
+	a.updateSprint()
+
+	stop := a.sprint.stop
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(a.updateSprint)
+			}
+		}
+	}()
+}
+
+func (a *App) updateSprint() {
+	sp := a.sprint
+	if sp == nil {
+		return
+	}
+
+	elapsed := time.Since(sp.start)
+	remaining := max(sp.duration-elapsed, 0).Round(time.Second)
+	words := max(countWords(a.textArea.Text)-sp.baseWords, 0)
+
+	progress := float64(elapsed) / float64(sp.duration)
+	label := fmt.Sprintf("Sprint: %s left, %d words", remaining, words)
+	if sp.goal > 0 {
+		progress = max(progress, float64(words)/float64(sp.goal))
+		label = fmt.Sprintf("Sprint: %s left, %d / %d words", remaining, words, sp.goal)
+	}
+	a.sprintBar.SetValue(min(progress, 1))
+	a.sprintLbl.SetText(label)
+
+	if sp.goal > 0 && words >= sp.goal && !sp.goalMet {
+		sp.goalMet = true
+		playChime()
+		a.updateStatus(tr("Word goal reached: %d words", words))
+	}
+	if remaining == 0 {
+		a.endSprint(fmt.Sprintf("Sprint finished: %d words", words))
+	}
+}
+
+func (a *App) endSprint(status string) {
+	if a.sprint == nil {
+		return
+	}
+	close(a.sprint.stop)
+	a.sprint = nil
+	a.sprintBox.Hide()
+	playChime()
+	a.updateStatus(status)
+}
+
+func (a *App) newSprintBox() fyne.CanvasObject {
+	a.sprintLbl = widget.NewLabel("")
+	a.sprintBar = widget.NewProgressBar()
+	a.sprintBar.TextFormatter = func() string { return "" }
+	a.sprintBox = container.NewBorder(nil, nil, a.sprintLbl, nil, a.sprintBar)
+	a.sprintBox.Hide()
+	return a.sprintBox
+}
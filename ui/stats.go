@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+// Stats times the current (or last) recording session.
+type Stats struct {
+	start time.Time
+	end   time.Time // Zero while recording
+	stop  chan struct{}
+}
+
+func (a *App) newStatsLabel() fyne.CanvasObject {
+	a.statsLbl = widget.NewLabel("")
+	a.statsLbl.Alignment = fyne.TextAlignTrailing
+	a.updateStats()
+	return a.statsLbl
+}
+
+// startStats starts timing a recording session and refreshes the readout
+// (and connection health) every second so the duration keeps moving during
+// pauses. The silence suppression indicator, if on, refreshes faster.
+func (a *App) startStats() {
+	stats := &Stats{start: time.Now(), stop: make(chan struct{})}
+	a.mu.Lock()
+	a.stats = stats
+	a.mu.Unlock()
+
+	stop := stats.stop
+	vad := a.sessionCfg.VADBackend != vadOff
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		var indicator <-chan time.Time
+		if vad {
+			t := time.NewTicker(miniRefresh)
+			defer t.Stop()
+			indicator = t.C
+		}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					a.updateStats()
+					a.updateHealth()
+					a.updateStreamStats()
+				})
+			case <-indicator:
+				fyne.Do(a.updateVADIndicator)
+			}
+		}
+	}()
+}
+
+// stopStats freezes the session duration. Caller must hold a.mu.
+func (a *App) stopStats() {
+	if a.stats == nil || !a.stats.end.IsZero() {
+		return
+	}
+	a.stats.end = time.Now()
+	close(a.stats.stop)
+}
+
+// sessionWords counts the words dictated in the current session, which unlike
+// the text area excludes earlier text and typing. Caller must hold a.mu.
+func (a *App) sessionWords() int {
+	words := 0
+	for _, turn := range a.turns {
+		if turn.Session == a.session {
+			words += countWords(turn.Text)
+		}
+	}
+	return words
+}
+
+// updateStats refreshes the word, character, duration and WPM readout.
+func (a *App) updateStats() {
+	if a.statsLbl == nil {
+		return
+	}
+	text := a.textArea.Text
+	label := fmt.Sprintf("%d words · %d chars", countWords(text), utf8.RuneCountInString(text))
+
+	a.mu.RLock()
+	stats := a.stats
+	var elapsed time.Duration
+	var dictated int
+	if stats != nil {
+		end := stats.end
+		if end.IsZero() {
+			end = time.Now()
+		}
+		elapsed = end.Sub(stats.start)
+		dictated = a.sessionWords()
+	}
+	a.mu.RUnlock()
+
+	if stats != nil {
+		label += " · " + clockTime(elapsed)
+		if elapsed >= 10*time.Second {
+			label += fmt.Sprintf(" · %.0f WPM", float64(dictated)/elapsed.Minutes())
+		}
+	}
+	a.statsLbl.SetText(label)
+}
+
+// StreamStats totals what the session's streams have sent and received, so
+// users can see at a glance that data is flowing.
+type StreamStats struct {
+	sent    int64         // Bytes of audio
+	turns   int64         // Finished turns
+	latency time.Duration // How far the transcript lags the audio sent, at worst
+	dropped int64         // Chunks of audio dropped because the network couldn't keep up
+}
+
+func (s StreamStats) String() string {
+	return fmt.Sprintf("Streamed %.1fs (%s) · %d turns · %.1fs behind · %d chunks dropped",
+		audio.Duration(s.sent).Seconds(), formatBytes(s.sent), s.turns, s.latency.Seconds(), s.dropped)
+}
+
+// streamStats adds up the current session's streams.
+func (a *App) streamStats() StreamStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var stats StreamStats
+	for _, st := range a.streams {
+		stats.sent += st.sentBytes.Load()
+		stats.turns += st.turnsReceived.Load()
+		stats.latency = max(stats.latency, st.health.status().delay)
+		stats.dropped += st.droppedChunks.Load()
+	}
+	return stats
+}
+
+func (a *App) newStreamStatsLabel() fyne.CanvasObject {
+	a.streamLbl = widget.NewLabel("")
+	a.streamLbl.Importance = widget.LowImportance
+	a.streamLbl.Hide()
+	return a.streamLbl
+}
+
+// updateStreamStats refreshes the streaming readout, shown while recording.
+func (a *App) updateStreamStats() {
+	if a.streamLbl == nil {
+		return
+	}
+	if !a.recording.Load() {
+		a.streamLbl.Hide()
+		return
+	}
+	a.streamLbl.SetText(a.streamStats().String())
+	a.streamLbl.Show()
+}
@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/llm"
+)
+
+// maxStructuredAttempts is how many replies are asked for before giving up
+// on one that matches the schema.
+const maxStructuredAttempts = 3
+
+// Schema is the part of JSON Schema replies are checked against: types,
+// object properties, array items and enums.
+type Schema struct {
+	Type        any                `json:"type"` // A type name or a list of them
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+}
+
+func parseSchema(data json.RawMessage) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+	if len(s.types()) == 0 {
+		return nil, fmt.Errorf("invalid schema: it needs a \"type\"")
+	}
+	return &s, nil
+}
+
+func (s *Schema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, name := range t {
+			if name, ok := name.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func jsonTypeMatches(name string, v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case float64:
+		return name == "number" || name == "integer" && v == math.Trunc(v)
+	case string:
+		return name == "string"
+	case []any:
+		return name == "array"
+	case map[string]any:
+		return name == "object"
+	}
+	return false
+}
+
+// validate checks v, decoded from JSON, against the schema. The error says
+// where it doesn't match, so the model can be asked to fix it.
+func (s *Schema) validate(v any, path string) error {
+	if s == nil {
+		return nil
+	}
+	where := strings.TrimPrefix(path, ".")
+	if where == "" {
+		where = "the reply"
+	}
+	if types := s.types(); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return jsonTypeMatches(t, v) }) {
+		return fmt.Errorf("%s should be %s", where, strings.Join(types, " or "))
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		return fmt.Errorf("%s should be one of the schema's enum values", where)
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				return fmt.Errorf("%s is missing %q", where, key)
+			}
+		}
+		for _, key := range s.keys(v) {
+			if err := s.Properties[key].validate(v[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keys orders an object's keys as the schema requires them, then the rest
+// alphabetically.
+func (s *Schema) keys(v map[string]any) []string {
+	var keys, rest []string
+	if s != nil {
+		for _, key := range s.Required {
+			if _, ok := v[key]; ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	for key := range v {
+		if !slices.Contains(keys, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func (s *Schema) property(key string) *Schema {
+	if s == nil {
+		return nil
+	}
+	return s.Properties[key]
+}
+
+func (s *Schema) items() *Schema {
+	if s == nil {
+		return nil
+	}
+	return s.Items
+}
+
+// parseStructured decodes a JSON reply, tolerating a code fence around it,
+// and checks it against the schema.
+func parseStructured(reply string, s *Schema) (any, error) {
+	text := strings.TrimSpace(reply)
+	if m := replyFencePattern.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return nil, fmt.Errorf("the reply isn't valid JSON: %v", err)
+	}
+	if err := s.validate(v, ""); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// unsupportedFormat reports whether a request failed because the provider
+// or model doesn't do JSON Schema output.
+func unsupportedFormat(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "response_format") || strings.Contains(msg, "json_schema")
+}
+
+// callStructured asks a model for JSON matching schema. It uses the
+// provider's JSON Schema output, falling back to JSON mode where that isn't
+// offered, and asks again with the problem when a reply doesn't match.
+func (a *App) callStructured(ctx context.Context, model string, params llm.Params, systemPrompt, text, name string, schema json.RawMessage) (any, string, error) {
+	s, err := parseSchema(schema)
+	if err != nil {
+		return nil, "", err
+	}
+	provider := a.settings().llmProvider()
+	// JSON mode needs "JSON" in the prompt, and the schema helps either way
+	systemPrompt += "\n\nReply with only JSON matching this JSON Schema:\n" + string(schema)
+	request := llm.Request{
+		Model: model,
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: text},
+		},
+		Params:         params,
+		ResponseFormat: &llm.ResponseFormat{Type: "json_schema", JSONSchema: &llm.JSONSchemaFormat{Name: schemaName(name), Schema: schema}},
+	}
+	for attempt := 1; ; attempt++ {
+		reply, err := a.callProvider(ctx, provider, request)
+		if err != nil && request.ResponseFormat.Type == "json_schema" && unsupportedFormat(err) {
+			slog.Info("JSON Schema output unavailable, using JSON mode", "model", model, "err", err)
+			request.ResponseFormat = &llm.ResponseFormat{Type: "json_object"}
+			attempt--
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		v, err := parseStructured(reply, s)
+		if err == nil {
+			formatted, _ := json.MarshalIndent(v, "", "  ")
+			return v, string(formatted), nil
+		}
+		if attempt == maxStructuredAttempts {
+			return nil, "", fmt.Errorf("no reply matched the schema in %d attempts: %v", attempt, err)
+		}
+		slog.Warn("reply didn't match the schema, asking again", "model", model, "attempt", attempt, "err", err)
+		request.Messages = append(request.Messages,
+			llm.Message{Role: "assistant", Content: reply},
+			llm.Message{Role: "user", Content: "That isn't valid: " + err.Error() + ". Reply again with only the corrected JSON."},
+		)
+	}
+}
+
+// schemaName makes a step name fit the names providers accept for schemas.
+func schemaName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" {
+		return "reply"
+	}
+	return name[:min(len(name), 64)]
+}
+
+// fieldLabel turns a JSON key like "action_items" into "Action items".
+func fieldLabel(key string) string {
+	label := strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(key))
+	if label == "" {
+		return key
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func scalarText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "—"
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return true
+}
+
+// structuredMarkdown renders a structured reply as Markdown for the
+// transcript: a section per top-level field, bullets for lists, and the
+// fields of list items on one line.
+func structuredMarkdown(v any, s *Schema) string {
+	var b strings.Builder
+	writeStructured(&b, v, s, 0)
+	return strings.TrimSpace(b.String())
+}
+
+func writeStructured(b *strings.Builder, v any, s *Schema, depth int) {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth > 0 {
+			var fields []string
+			for _, key := range s.keys(v) {
+				if v[key] != nil {
+					fields = append(fields, fieldLabel(key)+": "+inlineStructured(v[key]))
+				}
+			}
+			b.WriteString(strings.Join(fields, "; ") + "\n")
+			return
+		}
+		for _, key := range s.keys(v) {
+			fmt.Fprintf(b, "## %s\n", fieldLabel(key))
+			writeStructured(b, v[key], s.property(key), depth+1)
+			b.WriteString("\n")
+		}
+	case []any:
+		if len(v) == 0 {
+			b.WriteString("None.\n")
+		}
+		for _, item := range v {
+			b.WriteString("- ")
+			writeStructured(b, item, s.items(), depth+1)
+		}
+	default:
+		b.WriteString(scalarText(v) + "\n")
+	}
+}
+
+func inlineStructured(v any) string {
+	if list, ok := v.([]any); ok && len(list) > 0 && isScalar(list[0]) {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = scalarText(item)
+		}
+		return strings.Join(items, ", ")
+	}
+	return scalarText(v)
+}
+
+// structuredView shows a structured reply natively: objects as forms,
+// lists as numbered cards, and values as text.
+func structuredView(v any, s *Schema) fyne.CanvasObject {
+	switch v := v.(type) {
+	case map[string]any:
+		form := widget.NewForm()
+		for _, key := range s.keys(v) {
+			form.Append(fieldLabel(key), structuredView(v[key], s.property(key)))
+		}
+		return form
+	case []any:
+		box := container.NewVBox()
+		if len(v) == 0 {
+			box.Add(widget.NewLabel(tr("None")))
+		}
+		for i, item := range v {
+			if isScalar(item) {
+				box.Add(wrappedLabel("• " + scalarText(item)))
+				continue
+			}
+			box.Add(widget.NewCard("", fmt.Sprintf("%d", i+1), structuredView(item, s.items())))
+		}
+		return box
+	}
+	return wrappedLabel(scalarText(v))
+}
+
+func wrappedLabel(text string) *widget.Label {
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+	return label
+}
+
+// extractionPipelines are the built-in structured extractions.
+var extractionPipelines = []Pipeline{
+	{Name: "Extract action items", Steps: []PipelineStep{{
+		Name:   "Action items",
+		Prompt: "List every action item in the text: the task, who owns it and when it's due, using null where the text doesn't say.",
+		Schema: json.RawMessage(`{"type": "object", "properties": {"action_items": {"type": "array", "items": {"type": "object", "properties": {"task": {"type": "string"}, "owner": {"type": ["string", "null"]}, "due": {"type": ["string", "null"]}}, "required": ["task", "owner", "due"]}}}, "required": ["action_items"]}`),
+	}}},
+	{Name: "Extract entities", Steps: []PipelineStep{{
+		Name:   "Entities",
+		Prompt: "List the people, organizations, places, products and dates mentioned in the text, each once, as written.",
+		Schema: json.RawMessage(`{"type": "object", "properties": {"people": {"type": "array", "items": {"type": "string"}}, "organizations": {"type": "array", "items": {"type": "string"}}, "places": {"type": "array", "items": {"type": "string"}}, "products": {"type": "array", "items": {"type": "string"}}, "dates": {"type": "array", "items": {"type": "string"}}}, "required": ["people", "organizations", "places", "products", "dates"]}`),
+	}}},
+	{Name: "Extract questions and answers", Steps: []PipelineStep{{
+		Name:   "Q&A",
+		Prompt: "List the questions asked in the text, each with the answer given, or null if it wasn't answered.",
+		Schema: json.RawMessage(`{"type": "object", "properties": {"questions": {"type": "array", "items": {"type": "object", "properties": {"question": {"type": "string"}, "answer": {"type": ["string", "null"]}}, "required": ["question", "answer"]}}}, "required": ["questions"]}`),
+	}}},
+}
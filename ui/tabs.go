@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TranscriptTab is a document with its own text, turns, undo and revisions.
+// The selected tab's state lives in the App's transcript fields and is
+// swapped in and out when switching tabs.
+type TranscriptTab struct {
+	item           *container.TabItem
+	text           string
+	turns          []Turn
+	committedText  string
+	committedTurns int
+	previousText   string
+	document       *HistorySession
+}
+
+// newDocumentTabs builds the tab bar above the transcript. The tabs share
+// the transcript view, so each tab's content is just a placeholder.
+func (a *App) newDocumentTabs() fyne.CanvasObject {
+	a.activeTab = a.newTranscriptTab()
+	a.transcriptTabs = []*TranscriptTab{a.activeTab}
+	a.docTabs = container.NewDocTabs(a.activeTab.item)
+	a.docTabs.CreateTab = func() *container.TabItem {
+		if a.recording.Load() {
+			a.updateStatus(tr("Stop recording to open another tab"))
+			return nil
+		}
+		t := a.newTranscriptTab()
+		a.transcriptTabs = append(a.transcriptTabs, t)
+		return t.item
+	}
+	a.docTabs.OnSelected = func(item *container.TabItem) {
+		a.switchTab(a.tabFor(item))
+	}
+	a.docTabs.CloseIntercept = func(item *container.TabItem) {
+		a.closeTab(a.tabFor(item))
+	}
+	return a.docTabs
+}
+
+func (a *App) newTranscriptTab() *TranscriptTab {
+	a.tabCount++
+	return &TranscriptTab{item: container.NewTabItem(tr("Document %d", a.tabCount), layout.NewSpacer())}
+}
+
+func (a *App) tabFor(item *container.TabItem) *TranscriptTab {
+	for _, t := range a.transcriptTabs {
+		if t.item == item {
+			return t
+		}
+	}
+	return nil
+}
+
+// switchTab saves the current tab's state and loads t's. Recording goes to
+// the tab it started in, so switching waits until it stops.
+func (a *App) switchTab(t *TranscriptTab) {
+	if t == nil || t == a.activeTab {
+		return
+	}
+	if a.recording.Load() {
+		a.docTabs.Select(a.activeTab.item)
+		a.updateStatus(tr("Stop recording to switch tabs"))
+		return
+	}
+
+	a.stopReadAloud()
+	a.closeSpelling()
+	old := a.activeTab
+	a.mu.Lock()
+	old.text = a.textArea.Text
+	old.turns, old.committedText, old.committedTurns = a.turns, a.committedText, a.committedTurns
+	a.turns, a.committedText, a.committedTurns = t.turns, t.committedText, t.committedTurns
+	a.mu.Unlock()
+	old.previousText, old.document = a.previousText, a.document
+	a.previousText, a.document = t.previousText, t.document
+	a.activeTab = t
+
+	a.textArea.SetText(t.text)
+	a.setLiveSegments(nil)
+	if a.previousText != "" {
+		a.undoBtn.Enable()
+	} else {
+		a.undoBtn.Disable()
+	}
+}
+
+// closeTab asks before closing a tab with text; the last tab can't be
+// closed, only cleared.
+func (a *App) closeTab(t *TranscriptTab) {
+	if t == nil {
+		return
+	}
+	if len(a.transcriptTabs) == 1 {
+		a.updateStatus(tr("The last tab can't be closed; use Clear instead"))
+		return
+	}
+	if t == a.activeTab && a.recording.Load() {
+		a.updateStatus(tr("Stop recording before closing this tab"))
+		return
+	}
+	text := t.text
+	if t == a.activeTab {
+		text = a.textArea.Text
+	}
+	remove := func() {
+		for i, other := range a.transcriptTabs {
+			if other == t {
+				a.transcriptTabs = append(a.transcriptTabs[:i], a.transcriptTabs[i+1:]...)
+				break
+			}
+		}
+		wasActive := t == a.activeTab
+		a.docTabs.Remove(t.item)
+		if wasActive {
+			// Removing the selected tab shows its neighbour without
+			// reporting it as selected
+			a.switchTab(a.tabFor(a.docTabs.Selected()))
+		}
+	}
+	if text == "" {
+		remove()
+		return
+	}
+	dialog.ShowConfirm(tr("Close Tab"), tr("Close %q? Its text is discarded unless it was saved to History.", t.item.Text), func(ok bool) {
+		if ok {
+			remove()
+		}
+	}, a.window)
+}
+
+// newTab opens an empty tab and switches to it.
+func (a *App) newTab() {
+	if item := a.docTabs.CreateTab(); item != nil {
+		a.docTabs.Append(item)
+		a.docTabs.Select(item)
+	}
+}
+
+func (a *App) closeCurrentTab() {
+	a.closeTab(a.activeTab)
+}
+
+// renameTab names the current tab.
+func (a *App) renameTab() {
+	t := a.activeTab
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(t.item.Text)
+	dialog.ShowForm(tr("Rename Tab"), tr("Rename"), tr("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(tr("Name"), nameEntry),
+	}, func(ok bool) {
+		if ok && nameEntry.Text != "" {
+			t.item.Text = nameEntry.Text
+			a.docTabs.Refresh()
+		}
+	}, a.window)
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func newTabsApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	return New(test.NewTempApp(t), Options{})
+}
+
+func TestTabsKeepTheirOwnText(t *testing.T) {
+	a := newTabsApp(t)
+	first := a.activeTab
+	a.textArea.SetText("First draft")
+	a.previousText = "First"
+
+	a.newTab()
+	second := a.activeTab
+	if second == first || a.textArea.Text != "" {
+		t.Fatalf("new tab shows %q, want an empty tab", a.textArea.Text)
+	}
+	if !a.undoBtn.Disabled() {
+		t.Error("undo enabled in a new tab")
+	}
+	a.textArea.SetText("Second draft")
+
+	a.docTabs.Select(first.item)
+	if a.activeTab != first || a.textArea.Text != "First draft" || a.previousText != "First" {
+		t.Errorf("back in the first tab, text = %q, undo = %q", a.textArea.Text, a.previousText)
+	}
+	if a.undoBtn.Disabled() {
+		t.Error("undo disabled in a tab with something to undo")
+	}
+
+	a.docTabs.Select(second.item)
+	if a.textArea.Text != "Second draft" {
+		t.Errorf("back in the second tab, text = %q, want %q", a.textArea.Text, "Second draft")
+	}
+}
+
+func TestCloseTab(t *testing.T) {
+	a := newTabsApp(t)
+	first := a.activeTab
+	a.closeTab(first)
+	if len(a.transcriptTabs) != 1 {
+		t.Fatal("closed the last tab")
+	}
+
+	a.newTab()
+	a.closeTab(a.activeTab)
+	if len(a.transcriptTabs) != 1 || a.activeTab != first {
+		t.Errorf("after closing an empty tab, %d tabs with the first active = %v, want 1, true",
+			len(a.transcriptTabs), a.activeTab == first)
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// TextSnippet is stored text inserted by speaking its trigger phrase in
+// command mode, e.g. "insert signature".
+type TextSnippet struct {
+	trigger string
+	text    string
+}
+
+// snippetEscapes turns the escapes in a snippet's text into the line breaks
+// and tabs a single settings line can't hold.
+var snippetEscapes = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\\`, `\`)
+
+// parseTextSnippets parses one snippet per line, "trigger => text", where
+// \n in the text starts a new line. Blank lines and lines starting with #
+// are ignored.
+func parseTextSnippets(text string) ([]TextSnippet, error) {
+	var snippets []TextSnippet
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		trigger, body, ok := strings.Cut(line, "=>")
+		trigger = normalizeCommand(trigger)
+		body = strings.TrimSpace(body)
+		if !ok || trigger == "" || body == "" {
+			return nil, fmt.Errorf("text snippet line %d: use \"trigger => text\"", i+1)
+		}
+		if seen[trigger] {
+			return nil, fmt.Errorf("text snippet line %d: %q is already a trigger", i+1, trigger)
+		}
+		seen[trigger] = true
+		snippets = append(snippets, TextSnippet{trigger: trigger, text: snippetEscapes.Replace(body)})
+	}
+	return snippets, nil
+}
+
+// findTextSnippet matches a spoken command against the snippet triggers.
+func findTextSnippet(snippets []TextSnippet, text string) *TextSnippet {
+	spoken := normalizeCommand(text)
+	for i := range snippets {
+		if snippets[i].trigger == spoken {
+			return &snippets[i]
+		}
+	}
+	return nil
+}
+
+// runTextSnippet expands a spoken snippet trigger, reporting whether text
+// matched one. Call it on the UI thread.
+func (a *App) runTextSnippet(text string) bool {
+	snippets, err := parseTextSnippets(a.settings().TextSnippets)
+	if err != nil {
+		// Settings validation rejects bad snippets, so only a hand-edited
+		// config gets here
+		slog.Warn("ignoring invalid text snippets", "err", err)
+		return false
+	}
+	snippet := findTextSnippet(snippets, text)
+	if snippet == nil {
+		return false
+	}
+	slog.Info("text snippet", "trigger", snippet.trigger)
+	a.insertTextSnippet(snippet.text)
+	a.updateStatus(tr("Snippet: %s", snippet.trigger))
+	return true
+}
+
+// insertTextSnippet puts a snippet's text where the next turn would go: at
+// the caret, or after the transcript while it's being appended to live.
+func (a *App) insertTextSnippet(text string) {
+	if a.textArea.Visible() {
+		a.window.Canvas().Focus(a.textArea)
+	}
+	if !a.recording.Load() || a.editMode || a.sessionCfg.TurnPlacement == turnPlacementCursor {
+		a.insertAtCaret(text)
+		return
+	}
+
+	// The live transcript is rebuilt from its turns, so the snippet becomes
+	// part of the committed text that new turns follow
+	a.mu.Lock()
+	current := a.transcriptText()
+	if current != "" && !unicode.IsSpace(rune(current[len(current)-1])) {
+		current += " "
+	}
+	a.commitText(current+text, len(a.turns))
+	displayText := a.transcriptText()
+	segments := a.liveSegments()
+	a.mu.Unlock()
+	a.textArea.SetText(displayText)
+	a.setLiveSegments(segments)
+}
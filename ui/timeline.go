@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+// Played either side of a turn so its first and last words aren't clipped
+const replayPadding = 250 * time.Millisecond
+
+// recordingPath names a stream's recording after the session's start.
+func (a *App) recordingPath(st *Stream) string {
+	name := fmt.Sprintf("%s-%d.wav", time.Now().Format("20060102-150405"), st.index)
+	return filepath.Join(a.getRecordingsDir(), name)
+}
+
+// replayTurn plays a turn's audio from the session recording, stopping any
+// replay already playing.
+func (a *App) replayTurn(turn Turn) {
+	if turn.Audio == "" {
+		a.updateStatus(tr("No audio was recorded for this turn (see Record session audio in Settings)"))
+		return
+	}
+	if a.replayCancel != nil {
+		a.replayCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.replayCancel = cancel
+	a.updateStatus(tr("Replaying %s – %s", clockTime(turn.AudioStart), clockTime(turn.AudioEnd)))
+	go func() {
+		defer cancel()
+		pcm, err := audio.ReadSegment(turn.Audio, turn.AudioStart-replayPadding, turn.AudioEnd+replayPadding)
+		if err == nil {
+			err = audio.Play(ctx, pcm, audio.SampleRate, nil)
+		}
+		if err != nil {
+			slog.Error("replay failed", "path", turn.Audio, "err", err)
+			fyne.Do(func() { a.showError(err) })
+		}
+	}()
+}
+
+// timelineLabel shows when a turn was spoken and where it is in the session
+// audio, e.g. "14:05:32 · 0:03:10".
+func timelineLabel(turn Turn) string {
+	label := turn.Start.Format("15:04:05")
+	if turn.Audio != "" {
+		label += " · " + clockTime(turn.AudioStart)
+	}
+	return label
+}
+
+func (a *App) newTimelinePane() fyne.CanvasObject {
+	a.timelineList = widget.NewList(
+		func() int { return len(a.timeline) },
+		func() fyne.CanvasObject {
+			when := widget.NewLabel("")
+			when.Importance = widget.LowImportance
+			text := widget.NewLabel("")
+			text.Truncation = fyne.TextTruncateEllipsis
+			retranscribe := newTipButton("", theme.ViewRefreshIcon(), tr("Re-transcribe this turn from its recorded audio"), nil)
+			retranscribe.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, container.NewHBox(widget.NewIcon(theme.MediaPlayIcon()), when), retranscribe, text)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			turn := a.timeline[id]
+			row := item.(*fyne.Container)
+			text := row.Objects[0].(*widget.Label)
+			left := row.Objects[1].(*fyne.Container)
+			icon := left.Objects[0].(*widget.Icon)
+			retranscribe := row.Objects[2].(*TipButton)
+			retranscribe.OnTapped = func() { a.retranscribeTurn(turn) }
+			if turn.Audio != "" {
+				icon.Show()
+				retranscribe.Show()
+			} else {
+				icon.Hide()
+				retranscribe.Hide()
+			}
+			left.Objects[1].(*widget.Label).SetText(timelineLabel(turn))
+			text.SetText(truncateWords(turn.display(), 8))
+		},
+	)
+	a.timelineList.OnSelected = func(id widget.ListItemID) {
+		a.timelineList.UnselectAll()
+		if id < len(a.timeline) {
+			a.jumpToTurn(a.timeline[id])
+			a.replayTurn(a.timeline[id])
+		}
+	}
+
+	spacer := canvas.NewRectangle(nil)
+	spacer.SetMinSize(fyne.NewSize(260, 0))
+	a.timelinePane = container.NewStack(spacer, a.timelineList)
+	a.timelinePane.Hide()
+	return a.timelinePane
+}
+
+func (a *App) toggleTimeline() {
+	if a.timelinePane.Visible() {
+		a.timelinePane.Hide()
+		return
+	}
+	a.timelinePane.Show()
+	a.refreshTimeline()
+}
+
+func (a *App) refreshTimeline() {
+	if a.timelinePane == nil || !a.timelinePane.Visible() {
+		return
+	}
+	a.mu.RLock()
+	a.timeline = a.timeline[:0]
+	for _, turn := range a.turns {
+		if turn.Text != "" {
+			a.timeline = append(a.timeline, turn)
+		}
+	}
+	a.mu.RUnlock()
+	a.timelineList.Refresh()
+}
+
+// jumpToTurn moves the caret to the last place the turn's text appears, if
+// it's still in the transcript.
+func (a *App) jumpToTurn(turn Turn) {
+	text := a.textArea.Text
+	i := strings.LastIndex(text, turn.Text)
+	if i < 0 {
+		return
+	}
+	if a.liveScroll.Visible() {
+		a.jumpToLine(strings.Count(text[:i], "\n"))
+		return
+	}
+	a.textArea.moveCaretTo(i)
+	a.window.Canvas().Focus(a.textArea)
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const defaultAutoStopSilence = 5 // Seconds
+
+// startAutoStopTimer arms the session's silence timer, which each turn
+// resets, and its hard time limit, so a forgotten open microphone doesn't
+// stream for hours.
+func (a *App) startAutoStopTimer() {
+	cfg := a.sessionCfg
+	a.lastActivityTime = time.Now()
+	a.autoStopIdle = time.Duration(cfg.AutoStopSilence) * time.Second
+	if a.autoStopIdle > 0 {
+		a.autoStopTimer = time.AfterFunc(a.autoStopIdle, func() {
+			slog.Info("auto-stop timer expired", "idle", a.autoStopIdle)
+			a.autoStop("Auto-stopping due to silence...")
+		})
+	}
+	if cfg.SessionLimit > 0 {
+		limit := time.Duration(cfg.SessionLimit) * time.Minute
+		a.limitTimer = time.AfterFunc(limit, func() {
+			slog.Info("session limit reached", "limit", limit)
+			a.autoStop(fmt.Sprintf("Auto-stopping at the %d minute session limit...", cfg.SessionLimit))
+		})
+	}
+	slog.Debug("auto-stop timer started", "idle", a.autoStopIdle, "limit", cfg.SessionLimit)
+}
+
+func (a *App) autoStop(status string) {
+	fyne.Do(func() {
+		if a.recording.Load() {
+			a.updateStatus(status)
+			a.stopRecording()
+		}
+	})
+}
+
+func (a *App) stopAutoStopTimer() {
+	if a.autoStopTimer != nil {
+		a.autoStopTimer.Stop()
+		a.autoStopTimer = nil
+		slog.Debug("auto-stop timer stopped")
+	}
+	if a.limitTimer != nil {
+		a.limitTimer.Stop()
+		a.limitTimer = nil
+	}
+}
+
+func (a *App) resetAutoStopTimer() {
+	if !a.recording.Load() {
+		return
+	}
+
+	a.lastActivityTime = time.Now()
+	if a.autoStopTimer != nil {
+		a.autoStopTimer.Reset(a.autoStopIdle)
+		slog.Debug("auto-stop timer reset")
+	}
+}
+
+// archiveAndClear saves the transcript to history, then clears it, for
+// Clear after Copy.
+func (a *App) archiveAndClear() {
+	if a.textArea.Text == "" {
+		return
+	}
+	source := revisionEdited
+	if a.document == nil {
+		source = revisionTranscript
+	}
+	a.addRevision(source)
+	a.clearText()
+	a.updateStatus(tr("Copied; the transcript was cleared and kept in history"))
+}
+
+// newTimerSettings builds the auto-stop and auto-clear settings, returning
+// them with functions to read and validate them.
+func newTimerSettings(cfg *Settings) (fyne.CanvasObject, func(s *Settings), func() error) {
+	numberEntry := func(value int, placeholder string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(placeholder)
+		if value > 0 {
+			e.SetText(strconv.Itoa(value))
+		}
+		return e
+	}
+	silenceEntry := numberEntry(cfg.AutoStopSilence, "Never")
+	limitEntry := numberEntry(cfg.SessionLimit, "No limit")
+	clearCheck := widget.NewCheck(tr("Clear the transcript after Copy (it's kept in history)"), nil)
+	clearCheck.SetChecked(cfg.ClearAfterCopy)
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Stop after silence (seconds)"), silenceEntry),
+			widget.NewFormItem(tr("Stop after (minutes)"), limitEntry),
+		),
+		clearCheck,
+	)
+	read := func(s *Settings) {
+		s.AutoStopSilence, _ = strconv.Atoi(strings.TrimSpace(silenceEntry.Text))
+		s.SessionLimit, _ = strconv.Atoi(strings.TrimSpace(limitEntry.Text))
+		s.ClearAfterCopy = clearCheck.Checked
+	}
+	validate := func() error {
+		for _, e := range []*widget.Entry{silenceEntry, limitEntry} {
+			if text := strings.TrimSpace(e.Text); text != "" {
+				if n, err := strconv.Atoi(text); err != nil || n < 0 {
+					return fmt.Errorf("Auto-stop times must be whole numbers, or empty for none")
+				}
+			}
+		}
+		return nil
+	}
+	return content, read, validate
+}
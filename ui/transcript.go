@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+	"dict/stt"
+)
+
+// Stream is one streaming transcription session, fed by one or more capture
+// sources. Sources after the first are mixed into the first.
+type Stream struct {
+	session int // Incremented per recording; AssemblyAI restarts turn order each session
+	index   int
+	label   string // Speaker label for turns from this stream, empty if unattributed
+	sources []string
+
+	cfg *Settings // Snapshot the stream was started with
+
+	connMu  sync.Mutex
+	conn    stt.Conn      // Nil while connecting or reconnecting
+	started time.Time     // Connection time, which word timestamps are relative to
+	closing atomic.Bool   // Set when stopping, so a dropped connection isn't retried
+	done    chan struct{} // Closed when the current connection's handler exits
+	health  Health
+
+	// Turn order restarts with each connection, so turns after a reconnect
+	// are numbered from orderBase.
+	orderBase int
+	nextOrder int
+
+	// Turns finished so far, for the streaming stats. finalOrder is one past
+	// the last finished turn's order.
+	turnsReceived atomic.Int64
+	finalOrder    int
+
+	sentBytes     atomic.Int64 // Audio sent, for usage tracking
+	connBytes     int64        // sentBytes when the current connection opened
+	billedSeconds float64      // Audio duration reported on termination of the current connection
+	mixer         *audio.Mixer
+	vad           *audio.VAD       // Nil without silence suppression
+	audio         *audio.Recording // Nil unless session audio is recorded
+
+	// Audio captured before the stream connects, or while it reconnects,
+	// sent as soon as it can be. Only the sender uses it.
+	pending []byte
+
+	// Audio messages are chunkBytes, and never shorter than minChunkBytes;
+	// unsent is what's left over until there's enough for another. Set on
+	// first connecting; only the sender uses them after that.
+	chunkBytes    int
+	minChunkBytes int
+	unsent        []byte
+
+	// Captured audio waiting to be sent, so a slow network never blocks the
+	// capture callback. See sendqueue.go.
+	queue         chan []byte
+	commands      chan connCommand
+	quit          chan struct{}
+	sent          chan struct{} // Closed when the sender has finished
+	queuedBytes   atomic.Int64
+	queuePeak     atomic.Int64
+	droppedBytes  atomic.Int64
+	droppedChunks atomic.Int64
+}
+
+func (st *Stream) connection() stt.Conn {
+	st.connMu.Lock()
+	defer st.connMu.Unlock()
+	return st.conn
+}
+
+// setConnection replaces the stream's connection, returning the old one.
+func (st *Stream) setConnection(conn stt.Conn) stt.Conn {
+	st.connMu.Lock()
+	defer st.connMu.Unlock()
+	old := st.conn
+	st.conn = conn
+	return old
+}
+
+// Turn is a finalized transcript turn from one stream.
+type Turn struct {
+	Session int
+	Stream  int
+	Order   int
+	Speaker string
+	Text    string
+	Start   time.Time
+	End     time.Time
+
+	// Where the turn is in the stream's session recording, if there is one
+	Audio      string
+	AudioStart time.Duration
+	AudioEnd   time.Duration
+
+	Confidence float64       // Mean word confidence
+	Latency    time.Duration // From the end of speech to the latest text
+}
+
+func (t Turn) display() string {
+	if t.Speaker == "" {
+		return t.Text
+	}
+	return t.Speaker + ": " + t.Text
+}
+
+// newStreams builds the streaming sessions for the capture source in the
+// session's settings. Caller must hold a.mu.
+func (a *App) newStreams() []*Stream {
+	a.session++
+	cfg := a.sessionCfg
+	if cfg.CaptureSource != captureSourceMeeting {
+		return []*Stream{{session: a.session, cfg: cfg, sources: []string{cfg.CaptureSource}}}
+	}
+
+	if cfg.MeetingMixed {
+		return []*Stream{{session: a.session, cfg: cfg, sources: []string{captureSourceMicrophone, captureSourceSystem}}}
+	}
+	me, others := speakerLabels(cfg)
+	return []*Stream{
+		{session: a.session, cfg: cfg, index: streamMe, label: me, sources: []string{captureSourceMicrophone}},
+		{session: a.session, cfg: cfg, index: streamOthers, label: others, sources: []string{captureSourceSystem}},
+	}
+}
+
+// applyFinalTurn records a finalized turn. AssemblyAI may send the same turn
+// twice (unformatted, then formatted), in which case the text is replaced.
+// Caller must hold a.mu.
+func (a *App) applyFinalTurn(st *Stream, order int, text string, words []stt.Word) Turn {
+	delete(a.partialTexts, st.index)
+	now := time.Now()
+	start, end := now, now
+	var audioPath string
+	var audioStart, audioEnd time.Duration
+	if len(words) > 0 && !st.started.IsZero() {
+		start = st.started.Add(time.Duration(words[0].Start) * time.Millisecond)
+		end = st.started.Add(time.Duration(words[len(words)-1].End) * time.Millisecond)
+		// Word times restart with each connection, and the recording holds
+		// exactly the audio sent, so the offset before it is what was sent
+		// on earlier connections
+		if st.audio != nil {
+			audioPath = st.audio.Path
+			audioStart = audio.Duration(st.connBytes) + time.Duration(words[0].Start)*time.Millisecond
+			audioEnd = audio.Duration(st.connBytes) + time.Duration(words[len(words)-1].End)*time.Millisecond
+		}
+	}
+	// Measured on every version of the turn, so it ends up covering the
+	// formatted text
+	latency := max(now.Sub(end), 0)
+	var confidence float64
+	for _, word := range words {
+		confidence += word.Confidence / float64(len(words))
+	}
+
+	for i := range a.turns {
+		if a.turns[i].Session == st.session && a.turns[i].Stream == st.index && a.turns[i].Order == order {
+			a.turns[i].Text = text
+			a.turns[i].Start, a.turns[i].End = start, end
+			a.turns[i].Audio, a.turns[i].AudioStart, a.turns[i].AudioEnd = audioPath, audioStart, audioEnd
+			a.turns[i].Confidence = confidence
+			a.turns[i].Latency = latency
+			return a.turns[i]
+		}
+	}
+	turn := Turn{Session: st.session, Stream: st.index, Order: order, Speaker: st.label, Text: text, Start: start, End: end, Audio: audioPath, AudioStart: audioStart, AudioEnd: audioEnd, Confidence: confidence, Latency: latency}
+	a.turns = append(a.turns, turn)
+	return turn
+}
+
+// transcriptText renders the committed text followed by the turns finalized
+// since. Caller must hold a.mu.
+func (a *App) transcriptText() string {
+	return a.committedText + strings.Join(a.newTurnPieces(), "")
+}
+
+// newTurnPieces renders the turns finalized since the committed text, each
+// with the separator that joins it on. Caller must hold a.mu.
+func (a *App) newTurnPieces() []string {
+	committed := min(a.committedTurns, len(a.turns))
+	var prev *Turn
+	for i := committed - 1; i >= 0; i-- {
+		if a.turns[i].Text != "" {
+			prev = &a.turns[i]
+			break
+		}
+	}
+	return joinTurns(a.settings(), a.committedText, prev, a.turns[committed:])
+}
+
+// commitText makes text the base of the transcript, standing in for every
+// turn finalized so far, so that user edits survive further dictation.
+// Caller must hold a.mu.
+func (a *App) commitText(text string, turns int) {
+	a.committedText = text
+	a.committedTurns = turns
+}
+
+// liveSegments renders the finalized turns followed by the in-progress
+// partial transcripts, which are styled so it's clear they may still change.
+// Caller must hold a.mu.
+func (a *App) liveSegments() []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	if a.committedText != "" {
+		segments = append(segments, &widget.TextSegment{
+			Style: widget.RichTextStyleInline,
+			Text:  a.committedText,
+		})
+	}
+	var shown []Turn
+	for _, turn := range a.turns[min(a.committedTurns, len(a.turns)):] {
+		if turn.Text != "" {
+			shown = append(shown, turn)
+		}
+	}
+	// Turns are joined inline so their separators decide the line breaks
+	for i, piece := range a.newTurnPieces() {
+		segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleInline, Text: piece})
+		if a.settings().ShowLatency {
+			segments = append(segments,
+				&widget.TextSegment{Style: widget.RichTextStyleInline, Text: "  "},
+				&widget.TextSegment{Style: latencyBadgeStyle, Text: latencyBadge(shown[i].Latency)},
+			)
+		}
+	}
+	if len(segments) > 0 {
+		// End the line before any partial transcripts
+		segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleParagraph})
+	}
+	for _, st := range a.streams {
+		if partial := a.partialTexts[st.index]; partial != "" {
+			segments = append(segments, &widget.TextSegment{
+				Style: partialTextStyle,
+				Text:  Turn{Speaker: st.label, Text: partial}.display(),
+			})
+		}
+	}
+	return segments
+}
+
+// latencyBadgeStyle follows the turn with a small muted badge.
+var latencyBadgeStyle = widget.RichTextStyle{
+	Inline:    true,
+	ColorName: theme.ColorNamePlaceHolder,
+	SizeName:  theme.SizeNameCaptionText,
+}
+
+// latencyBadge formats the delay between the end of speech and a turn's
+// final text, e.g. "⏱ 0.8s".
+func latencyBadge(latency time.Duration) string {
+	return fmt.Sprintf("⏱ %.1fs", latency.Seconds())
+}
+
+var partialTextStyle = widget.RichTextStyle{
+	ColorName: theme.ColorNamePlaceHolder,
+	SizeName:  theme.SizeNameText,
+	TextStyle: fyne.TextStyle{Italic: true},
+}
+
+// newTranscriptView stacks the editable text area with the read-only live
+// view used while recording.
+func (a *App) newTranscriptView() fyne.CanvasObject {
+	textScroll := container.NewScroll(a.textArea)
+	textScroll.SetMinSize(fyne.NewSize(580, 300))
+
+	a.liveText = widget.NewRichText()
+	a.liveText.Wrapping = fyne.TextWrapWord
+	a.liveScroll = container.NewVScroll(a.liveText)
+	a.liveScroll.Hide()
+
+	a.editView = textScroll
+	return container.NewStack(textScroll, a.liveScroll, a.newProofView(), a.newSpellingView())
+}
+
+func (a *App) setLiveSegments(segments []widget.RichTextSegment) {
+	a.liveText.Segments = segments
+	a.liveText.Refresh()
+	a.liveScroll.ScrollToBottom()
+}
+
+// showLiveView switches between the live view and the editable text area.
+func (a *App) showLiveView(live bool) {
+	if live {
+		a.editView.Hide()
+		a.liveScroll.Show()
+	} else {
+		a.liveScroll.Hide()
+		a.editView.Show()
+	}
+}
+
+// partialText joins the in-progress partial transcripts of every stream.
+// Caller must hold a.mu.
+func (a *App) partialText() string {
+	var parts []string
+	for _, st := range a.streams {
+		if partial := a.partialTexts[st.index]; partial != "" {
+			parts = append(parts, Turn{Speaker: st.label, Text: partial}.display())
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// setEditMode switches between live mode, where streaming updates are shown
+// read-only, and edit mode, where the text area is editable and new turns are
+// held back until live mode resumes.
+func (a *App) setEditMode(edit bool) {
+	if edit == a.editMode {
+		return
+	}
+	a.editMode = edit
+
+	a.mu.Lock()
+	if edit {
+		// Everything finalized so far is already in the text area
+		a.editBase = len(a.turns)
+	} else {
+		a.commitText(a.textArea.Text, a.editBase)
+	}
+	displayText := a.transcriptText()
+	segments := a.liveSegments()
+	a.mu.Unlock()
+
+	if edit {
+		a.modeBtn.SetText(tr("Live"))
+		a.modeBtn.SetIcon(theme.VisibilityIcon())
+		a.showLiveView(false)
+		a.window.Canvas().Focus(a.textArea)
+		a.updateStatus(tr("Editing — new turns are held until you return to Live"))
+		a.showHintOnce(hintEditMode)
+		return
+	}
+
+	a.modeBtn.SetText(tr("Edit"))
+	a.modeBtn.SetIcon(theme.DocumentCreateIcon())
+	a.textArea.SetText(displayText)
+	a.setLiveSegments(segments)
+	if a.recording.Load() {
+		a.showLiveView(true)
+		a.updateStatus(tr("Recording..."))
+	}
+}
+
+func (a *App) toggleEditMode() {
+	a.setEditMode(!a.editMode)
+}
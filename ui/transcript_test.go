@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTranscriptApp(update func(s *Settings)) *App {
+	a := &App{partialTexts: make(map[int]string)}
+	s := defaultSettings()
+	if update != nil {
+		update(s)
+	}
+	a.config.Store(s)
+	return a
+}
+
+func TestApplyFinalTurnReplacesSameTurn(t *testing.T) {
+	a := newTranscriptApp(nil)
+	st := &Stream{session: 1}
+	other := &Stream{session: 1, index: streamOthers}
+	later := &Stream{session: 2}
+
+	a.partialTexts[st.index] = "hello wor"
+	a.applyFinalTurn(st, 0, "hello world", nil)
+	if _, ok := a.partialTexts[st.index]; ok {
+		t.Error("partial text kept after the turn ended")
+	}
+	a.applyFinalTurn(st, 0, "Hello world.", nil)
+	// The same order on another stream or in another session is a new turn
+	a.applyFinalTurn(other, 0, "Hi.", nil)
+	a.applyFinalTurn(later, 0, "Next session.", nil)
+	a.applyFinalTurn(st, 1, "Second.", nil)
+
+	var texts []string
+	for _, turn := range a.turns {
+		texts = append(texts, turn.Text)
+	}
+	if got, want := strings.Join(texts, "|"), "Hello world.|Hi.|Next session.|Second."; got != want {
+		t.Errorf("turns = %q, want %q", got, want)
+	}
+}
+
+func TestTranscriptTextAfterCommittedText(t *testing.T) {
+	tests := []struct {
+		name      string
+		separator string
+		smartJoin bool
+		committed string
+		turns     []string
+		want      string
+	}{
+		{"empty", separatorNewline, false, "", []string{"One.", "Two."}, "One.\nTwo."},
+		{"after edits", separatorNewline, false, "Edited.", []string{"One."}, "Edited.\nOne."},
+		{"spaces", separatorSpace, false, "Notes:", []string{"one", "two"}, "Notes: one two"},
+		{"blank lines", separatorBlankLine, false, "", []string{"One.", "Two."}, "One.\n\nTwo."},
+		// Stripped turns, such as commands, leave no gap
+		{"empty turn", separatorNewline, false, "", []string{"One.", "", "Two."}, "One.\nTwo."},
+		{"smart join continues", separatorSpace, true, "I went", []string{"To the shop.", "Then home."}, "I went to the shop. Then home."},
+		{"smart join punctuation", separatorSpace, true, "Yes", []string{", I did."}, "Yes, I did."},
+		{"smart join keeps acronyms", separatorSpace, true, "We use", []string{"NASA data"}, "We use NASA data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTranscriptApp(func(s *Settings) {
+				s.TurnSeparator = tt.separator
+				s.SmartJoin = tt.smartJoin
+			})
+			st := &Stream{session: 1}
+			a.commitText(tt.committed, 0)
+			for i, text := range tt.turns {
+				a.applyFinalTurn(st, i, text, nil)
+			}
+			if got := a.transcriptText(); got != tt.want {
+				t.Errorf("transcript = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitTextStandsInForEarlierTurns(t *testing.T) {
+	a := newTranscriptApp(nil)
+	st := &Stream{session: 1}
+	a.applyFinalTurn(st, 0, "Dictated.", nil)
+	// The user edits the text area, which then replaces the turn so far
+	a.commitText("Dictated and edited.", len(a.turns))
+	a.applyFinalTurn(st, 1, "More.", nil)
+	// A late formatted version of a committed turn doesn't reappear
+	a.applyFinalTurn(st, 0, "Dictated!", nil)
+
+	if got, want := a.transcriptText(), "Dictated and edited.\nMore."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestParagraphAfterPause(t *testing.T) {
+	cfg := defaultSettings()
+	cfg.ParagraphPause = 3
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	turns := []Turn{
+		{Text: "One.", Start: start, End: start.Add(time.Second)},
+		{Text: "Two.", Start: start.Add(2 * time.Second), End: start.Add(3 * time.Second)},
+		{Text: "Three.", Start: start.Add(7 * time.Second), End: start.Add(8 * time.Second)},
+		{Speaker: "Me", Text: "Four.", Start: start.Add(9 * time.Second), End: start.Add(10 * time.Second)},
+	}
+	got := strings.Join(joinTurns(cfg, "", nil, turns), "")
+	if want := "One.\nTwo.\n\nThree.\nMe: Four."; got != want {
+		t.Errorf("joined = %q, want %q", got, want)
+	}
+}
+
+func TestSpliceSelectedTurns(t *testing.T) {
+	turns := []Turn{
+		{Order: 0, Text: "First point."},
+		{Order: 1, Text: "And another."},
+		{Order: 2, Text: "Okay."},
+		{Order: 3, Text: "Okay."},
+	}
+	// Space separated with smart joining, an edit before the first turn, and
+	// a turn that repeats the one before it
+	text := "Notes: first point. And another. Okay. Okay. Typed meanwhile."
+	spans := locateTurns(text, turns)
+	for i, want := range []string{"first point.", "And another.", "Okay.", "Okay."} {
+		if spans[i].start < 0 || text[spans[i].start:spans[i].end] != want {
+			t.Fatalf("turn %d found at %v, want %q", i, spans[i], want)
+		}
+	}
+	if spans[2].start == spans[3].start {
+		t.Fatal("repeated turn found at the same place")
+	}
+
+	got := spliceTurns(text, []turnSpan{spans[1], spans[3]}, "Summary.")
+	if want := "Notes: first point. Summary. Okay. Typed meanwhile."; got != want {
+		t.Errorf("spliced = %q, want %q", got, want)
+	}
+
+	if span := locateTurns("Rewritten entirely.", turns[:1])[0]; span.start >= 0 {
+		t.Errorf("edited-out turn found at %v", span)
+	}
+}
+
+func TestFindTurn(t *testing.T) {
+	for _, tt := range []struct {
+		text, body string
+		from       int
+		want       string
+	}{
+		{"Notes: first point.", "First point.", 0, "first point."},
+		{"ok. OK. ok.", "Ok.", 0, "ok."},
+		{"ok. OK. ok.", "OK.", 1, "OK."},
+		{"ok. OK. ok.", "Ok.", 1, "ok."},
+		{"Answer: b. A.", "a", 0, "A"},
+		{"This is it. I", "I", 0, "I"},
+		{"Answer: b. A.", "B", 0, "b"},
+		{"Él dijo: él.", "Él", 1, "él"},
+		{"Élan, é", "É", 0, "é"},
+		{"Nothing here.", "Missing.", 0, ""},
+		{"Nothing here.", "x", 0, ""},
+	} {
+		span := findTurn(tt.text, tt.body, tt.from)
+		got := ""
+		if span.start >= 0 {
+			got = tt.text[span.start:span.end]
+		}
+		if got != tt.want {
+			t.Errorf("findTurn(%q, %q, %d) found %q, want %q", tt.text, tt.body, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestLocateTurnsWithEachSeparator(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	turns := []Turn{
+		{Order: 0, Text: "One, then.", Start: start, End: start.Add(time.Second)},
+		{Order: 1, Text: "Two.", Start: start.Add(2 * time.Second), End: start.Add(3 * time.Second)},
+		{Order: 2, Text: "three more.", Start: start.Add(7 * time.Second), End: start.Add(8 * time.Second)},
+		{Order: 3, Speaker: "Me", Text: "Four.", Start: start.Add(9 * time.Second), End: start.Add(10 * time.Second)},
+	}
+	for _, sep := range []string{separatorSpace, separatorNewline, separatorBlankLine} {
+		cfg := defaultSettings()
+		cfg.TurnSeparator = sep
+		cfg.ParagraphPause = 3
+		cfg.SmartJoin = true
+		text := strings.Join(joinTurns(cfg, "", nil, turns), "")
+
+		// Lines needn't be turns; every turn is still found, in order
+		spans := locateTurns(text, turns)
+		for i, span := range spans {
+			if span.start < 0 || i > 0 && span.start < spans[i-1].end {
+				t.Fatalf("%s: turn %d found at %v in %q", sep, i, span, text)
+			}
+		}
+		if got := text[spans[3].start:spans[3].end]; got != "Me: Four." {
+			t.Errorf("%s: labelled turn found as %q", sep, got)
+		}
+		got := spliceTurns(text, spans[1:3], "X.")
+		if want := text[:spans[1].start] + "X." + text[spans[2].end:]; got != want {
+			t.Errorf("%s: spliced = %q, want %q", sep, got, want)
+		}
+	}
+}
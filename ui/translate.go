@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/apierr"
+)
+
+const (
+	translateLLM    = "llm"
+	translateDeepL  = "deepl"
+	translateGoogle = "google"
+
+	autoDetectLanguage = "Auto-detect"
+)
+
+var translateProviderLabels = map[string]string{
+	translateLLM:    "LLM",
+	translateDeepL:  "DeepL",
+	translateGoogle: "Google Translate",
+}
+
+// Language is a translation language with its ISO 639-1 code.
+type Language struct {
+	Name string
+	Code string
+}
+
+var translateLanguages = []Language{
+	{"Arabic", "ar"}, {"Chinese", "zh"}, {"Czech", "cs"}, {"Danish", "da"}, {"Dutch", "nl"},
+	{"English", "en"}, {"Finnish", "fi"}, {"French", "fr"}, {"German", "de"}, {"Greek", "el"},
+	{"Hindi", "hi"}, {"Hungarian", "hu"}, {"Indonesian", "id"}, {"Italian", "it"}, {"Japanese", "ja"},
+	{"Korean", "ko"}, {"Norwegian", "no"}, {"Polish", "pl"}, {"Portuguese", "pt"}, {"Romanian", "ro"},
+	{"Russian", "ru"}, {"Spanish", "es"}, {"Swedish", "sv"}, {"Turkish", "tr"}, {"Ukrainian", "uk"},
+}
+
+func languageByName(name string) (Language, bool) {
+	for _, l := range translateLanguages {
+		if l.Name == name {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+func languageNames() []string {
+	var names []string
+	for _, l := range translateLanguages {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// deeplTarget maps a code to DeepL's target language, which needs a variant
+// for English and Portuguese.
+func deeplTarget(code string) string {
+	switch code {
+	case "en":
+		return "EN-US"
+	case "pt":
+		return "PT-PT"
+	case "no":
+		return "NB"
+	}
+	return strings.ToUpper(code)
+}
+
+// translate translates text with the configured provider. An empty source
+// lets the provider detect the language.
+func (a *App) translate(ctx context.Context, text string, source, target Language) (string, error) {
+	cfg := a.settings()
+	switch cfg.TranslateProvider {
+	case translateDeepL:
+		if cfg.DeepLAPIKey == "" {
+			return "", &apierr.AuthError{Service: "DeepL", Err: errors.New("Please configure a DeepL API key in Settings")}
+		}
+		return a.translateWith(ctx, "DeepL", func() (string, error) {
+			return a.requestDeepL(ctx, cfg.DeepLAPIKey, text, source, target)
+		})
+	case translateGoogle:
+		if cfg.GoogleTranslateKey == "" {
+			return "", &apierr.AuthError{Service: "Google Translate", Err: errors.New("Please configure a Google Translate API key in Settings")}
+		}
+		return a.translateWith(ctx, "Google Translate", func() (string, error) {
+			return a.requestGoogleTranslate(ctx, cfg.GoogleTranslateKey, text, source, target)
+		})
+	}
+
+	if cfg.GroqAPIKey == "" {
+		return "", &apierr.AuthError{Service: "Groq", Err: errors.New("Please configure Groq API key in Settings")}
+	}
+	from := "its original language"
+	if source.Name != "" {
+		from = source.Name
+	}
+	prompt := fmt.Sprintf("Translate the following text from %s to %s. Keep line breaks and any \"Name:\" speaker labels. Output only the translation.", from, target.Name)
+	translated, err := a.callGroqModel(ctx, cfg.GroqModel, cfg.LLMParams, prompt, text)
+	if err != nil {
+		return "", err
+	}
+	return a.cleanLLMReply(translated, text), nil
+}
+
+// translateWith calls a translation API with retries.
+func (a *App) translateWith(ctx context.Context, service string, call func() (string, error)) (string, error) {
+	var result string
+	err := a.withRetries(ctx, service, a.settings().LLMMaxRetries, func() error {
+		var err error
+		result, err = call()
+		return err
+	})
+	return result, err
+}
+
+// postTranslation sends a JSON request to a translation API and decodes the
+// response into out.
+func (a *App) postTranslation(ctx context.Context, service, endpoint string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client, err := a.httpClient(0)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return &apierr.RetryableError{Err: &apierr.NetworkError{Service: service, Err: fmt.Errorf("failed to call %s: %v", service, err)}}
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := apierr.FromStatus(service, resp.StatusCode, fmt.Errorf("%s error (status %d): %s", service, resp.StatusCode, string(respBody)))
+		if apierr.RetryableStatus(resp.StatusCode) {
+			return &apierr.RetryableError{Err: err, RetryAfter: apierr.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return err
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %v", service, err)
+	}
+	return nil
+}
+
+func (a *App) requestDeepL(ctx context.Context, apiKey, text string, source, target Language) (string, error) {
+	// Free plan keys end in ":fx" and use a separate host
+	endpoint := "https://api.deepl.com/v2/translate"
+	if strings.HasSuffix(apiKey, ":fx") {
+		endpoint = "https://api-free.deepl.com/v2/translate"
+	}
+	body := map[string]any{
+		"text":        []string{text},
+		"target_lang": deeplTarget(target.Code),
+	}
+	if source.Code != "" {
+		body["source_lang"] = strings.ToUpper(source.Code)
+	}
+	var resp struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	headers := map[string]string{"Authorization": "DeepL-Auth-Key " + apiKey}
+	if err := a.postTranslation(ctx, "DeepL", endpoint, headers, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Translations) == 0 {
+		return "", fmt.Errorf("DeepL returned no translation")
+	}
+	return resp.Translations[0].Text, nil
+}
+
+func (a *App) requestGoogleTranslate(ctx context.Context, apiKey, text string, source, target Language) (string, error) {
+	endpoint := "https://translation.googleapis.com/language/translate/v2"
+	body := map[string]string{"q": text, "target": target.Code, "format": "text"}
+	if source.Code != "" {
+		body["source"] = source.Code
+	}
+	var resp struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	// The key goes in a header so it can't leak into errors and logs via the URL
+	headers := map[string]string{"X-Goog-Api-Key": apiKey}
+	if err := a.postTranslation(ctx, "Google Translate", endpoint, headers, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Data.Translations) == 0 {
+		return "", fmt.Errorf("Google Translate returned no translation")
+	}
+	return resp.Data.Translations[0].TranslatedText, nil
+}
+
+// showTranslate asks for the languages and whether to append or replace,
+// then translates the transcript.
+func (a *App) showTranslate() {
+	cfg := a.settings()
+	text := a.textArea.Text
+	if strings.TrimSpace(text) == "" {
+		a.updateStatus(tr("No text to translate"))
+		return
+	}
+
+	sourceSelect := widget.NewSelect(append([]string{autoDetectLanguage}, languageNames()...), nil)
+	sourceSelect.SetSelected(autoDetectLanguage)
+	if _, ok := languageByName(cfg.TranslateSource); ok {
+		sourceSelect.SetSelected(cfg.TranslateSource)
+	}
+	targetSelect := widget.NewSelect(languageNames(), nil)
+	targetSelect.SetSelected("English")
+	if _, ok := languageByName(cfg.TranslateTarget); ok {
+		targetSelect.SetSelected(cfg.TranslateTarget)
+	}
+	placementRadio := widget.NewRadioGroup([]string{tr("Append"), tr("Replace")}, nil)
+	placementRadio.Horizontal = true
+	placementRadio.SetSelected(tr("Append"))
+
+	provider := translateProviderLabels[cfg.TranslateProvider]
+	if provider == "" {
+		provider = translateProviderLabels[translateLLM]
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("From"), sourceSelect),
+		widget.NewFormItem(tr("To"), targetSelect),
+		widget.NewFormItem(tr("Result"), placementRadio),
+		widget.NewFormItem(tr("Using"), widget.NewLabel(tr("%s (change in Settings)", provider))),
+	}
+	dialog.ShowForm(tr("Translate"), tr("Translate"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		source, _ := languageByName(sourceSelect.Selected)
+		target, _ := languageByName(targetSelect.Selected)
+		a.saveTranslateLanguages(sourceSelect.Selected, targetSelect.Selected)
+		a.startTranslation(text, source, target, placementRadio.Selected == tr("Replace"))
+	}, a.window)
+}
+
+// saveTranslateLanguages remembers the last languages used.
+func (a *App) saveTranslateLanguages(source, target string) {
+	if cfg := a.settings(); cfg.TranslateSource == source && cfg.TranslateTarget == target {
+		return
+	}
+	a.updateSettings(func(s *Settings) {
+		s.TranslateSource, s.TranslateTarget = source, target
+	})
+	if err := a.writeSettings(a.store, a.settings()); err != nil {
+		slog.Warn("failed to save translation languages", "err", err)
+	}
+}
+
+func (a *App) startTranslation(text string, source, target Language, replace bool) {
+	slog.Info("translating", "from", source.Code, "to", target.Code, "provider", a.settings().TranslateProvider)
+	a.previousText = a.textArea.Text
+	a.updateStatus(tr("Translating to %s...", target.Name))
+	ctx, done := a.startLLMTask()
+	go func() {
+		translated, err := a.translate(ctx, text, source, target)
+		fyne.Do(func() {
+			done()
+			if err := a.llmError(err); err != nil {
+				a.updateStatus(tr("Translation failed: %s", err))
+				a.showError(err)
+				return
+			}
+			if translated == "" {
+				a.updateStatus(tr("Translation cancelled"))
+				return
+			}
+			if replace {
+				a.textArea.SetText(translated)
+			} else {
+				a.textArea.SetText(a.textArea.Text + "\n\n" + translated)
+			}
+			a.undoBtn.Enable()
+			a.addRevision("Translated to " + target.Name)
+			a.rememberClip("Translated to "+target.Name, a.textArea.Text)
+			a.reportResult(tr("Translated to %s", target.Name))
+		})
+	}()
+}
+
+// newTranslateSettings is the Translation section of the settings dialog.
+func newTranslateSettings(cfg *Settings) (fyne.CanvasObject, func(s *Settings)) {
+	providers := []string{translateProviderLabels[translateLLM], translateProviderLabels[translateDeepL], translateProviderLabels[translateGoogle]}
+	providerSelect := widget.NewSelect(providers, nil)
+	providerSelect.SetSelected(translateProviderLabels[translateLLM])
+	if label, ok := translateProviderLabels[cfg.TranslateProvider]; ok {
+		providerSelect.SetSelected(label)
+	}
+	deeplEntry := widget.NewPasswordEntry()
+	deeplEntry.SetPlaceHolder(tr("DeepL API key (optional)"))
+	deeplEntry.SetText(cfg.DeepLAPIKey)
+	googleEntry := widget.NewPasswordEntry()
+	googleEntry.SetPlaceHolder(tr("Google Translate API key (optional)"))
+	googleEntry.SetText(cfg.GoogleTranslateKey)
+
+	content := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Translate with:")), nil, providerSelect),
+		deeplEntry,
+		googleEntry,
+	)
+	return content, func(s *Settings) {
+		for provider, label := range translateProviderLabels {
+			if label == providerSelect.Selected {
+				s.TranslateProvider = provider
+			}
+		}
+		s.DeepLAPIKey = strings.TrimSpace(deeplEntry.Text)
+		s.GoogleTranslateKey = strings.TrimSpace(googleEntry.Text)
+	}
+}
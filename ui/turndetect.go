@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/config"
+)
+
+// TurnDetection holds AssemblyAI's end-of-turn tuning parameters.
+type TurnDetection struct {
+	Confidence       float64 // end_of_turn_confidence_threshold, 0–1
+	MinSilenceMillis int     // min_end_of_turn_silence_when_confident
+	MaxSilenceMillis int     // max_turn_silence
+}
+
+// Allowed ranges for the turn detection sliders
+const (
+	minTurnSilenceLow  = 0
+	minTurnSilenceHigh = 2000
+	maxTurnSilenceLow  = 400
+	maxTurnSilenceHigh = 10000
+)
+
+type TurnPreset struct {
+	Name     string
+	Settings TurnDetection
+}
+
+var turnPresets = []TurnPreset{
+	{"Balanced", TurnDetection{Confidence: 0.7, MinSilenceMillis: 160, MaxSilenceMillis: 2400}},
+	{"Fast dictation", TurnDetection{Confidence: 0.4, MinSilenceMillis: 160, MaxSilenceMillis: 1280}},
+	{"Long-form thinking", TurnDetection{Confidence: 0.8, MinSilenceMillis: 800, MaxSilenceMillis: 5000}},
+}
+
+const customTurnPreset = "Custom"
+
+var defaultTurnDetection = turnPresets[0].Settings
+
+func (td TurnDetection) presetName() string {
+	for _, preset := range turnPresets {
+		if preset.Settings == td {
+			return preset.Name
+		}
+	}
+	return customTurnPreset
+}
+
+func (td TurnDetection) toConfig() config.TurnDetection {
+	return config.TurnDetection{Confidence: td.Confidence, MinSilence: td.MinSilenceMillis, MaxSilence: td.MaxSilenceMillis}
+}
+
+func turnDetectionFromConfig(c config.TurnDetection) TurnDetection {
+	td := defaultTurnDetection
+	if c.Confidence >= 0 && c.Confidence <= 1 {
+		td.Confidence = c.Confidence
+	}
+	if c.MinSilence >= minTurnSilenceLow && c.MinSilence <= minTurnSilenceHigh {
+		td.MinSilenceMillis = c.MinSilence
+	}
+	if c.MaxSilence >= maxTurnSilenceLow && c.MaxSilence <= maxTurnSilenceHigh {
+		td.MaxSilenceMillis = c.MaxSilence
+	}
+	return td
+}
+
+// newTurnDetectionForm builds the settings controls. The returned function
+// reads back the values currently set in the form.
+func newTurnDetectionForm(current TurnDetection) (fyne.CanvasObject, func() TurnDetection) {
+	confidenceLbl := widget.NewLabel("")
+	confidence := widget.NewSlider(0, 1)
+	confidence.Step = 0.05
+
+	minSilenceLbl := widget.NewLabel("")
+	minSilence := widget.NewSlider(minTurnSilenceLow, minTurnSilenceHigh)
+	minSilence.Step = 20
+
+	maxSilenceLbl := widget.NewLabel("")
+	maxSilence := widget.NewSlider(maxTurnSilenceLow, maxTurnSilenceHigh)
+	maxSilence.Step = 100
+
+	read := func() TurnDetection {
+		return TurnDetection{
+			Confidence:       math.Round(confidence.Value*100) / 100,
+			MinSilenceMillis: int(minSilence.Value),
+			MaxSilenceMillis: int(maxSilence.Value),
+		}
+	}
+
+	names := []string{}
+	for _, preset := range turnPresets {
+		names = append(names, preset.Name)
+	}
+	names = append(names, customTurnPreset)
+	presetSelect := widget.NewSelect(names, nil)
+
+	updating := false
+	refresh := func() {
+		td := read()
+		confidenceLbl.SetText(tr("End-of-turn confidence: %.2f", td.Confidence))
+		minSilenceLbl.SetText(tr("Silence when confident: %d ms", td.MinSilenceMillis))
+		maxSilenceLbl.SetText(tr("Maximum turn silence: %d ms", td.MaxSilenceMillis))
+		if !updating {
+			presetSelect.SetSelected(td.presetName())
+		}
+	}
+	set := func(td TurnDetection) {
+		updating = true
+		confidence.SetValue(td.Confidence)
+		minSilence.SetValue(float64(td.MinSilenceMillis))
+		maxSilence.SetValue(float64(td.MaxSilenceMillis))
+		updating = false
+		refresh()
+	}
+
+	onSlide := func(float64) { refresh() }
+	confidence.OnChanged = onSlide
+	minSilence.OnChanged = onSlide
+	maxSilence.OnChanged = onSlide
+	presetSelect.OnChanged = func(name string) {
+		if updating {
+			return
+		}
+		for _, preset := range turnPresets {
+			if preset.Name == name && preset.Settings != read() {
+				set(preset.Settings)
+			}
+		}
+	}
+	set(current)
+
+	form := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Preset:")), nil, presetSelect),
+		confidenceLbl, confidence,
+		minSilenceLbl, minSilence,
+		maxSilenceLbl, maxSilence,
+	)
+	return form, read
+}
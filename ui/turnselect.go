@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// turnSpan is where a turn is in the transcript; start is -1 if it isn't.
+type turnSpan struct {
+	start, end int
+}
+
+// findTurn returns where body appears in text at or after from, allowing for
+// smart joining having changed the case of its first letter.
+func findTurn(text, body string, from int) turnSpan {
+	first, size := utf8.DecodeRuneInString(body)
+	rest := body[size:]
+	if rest == "" {
+		// A one letter turn, such as "I", standing on its own
+		for at := from; at < len(text); {
+			r, n := utf8.DecodeRuneInString(text[at:])
+			before, _ := utf8.DecodeLastRuneInString(text[:at])
+			after, _ := utf8.DecodeRuneInString(text[at+n:])
+			if unicode.ToLower(r) == unicode.ToLower(first) && !isWordRune(before) && !isWordRune(after) {
+				return turnSpan{at, at + n}
+			}
+			at += n
+		}
+		return turnSpan{-1, -1}
+	}
+	for from <= len(text) {
+		i := strings.Index(text[from:], rest)
+		if i < 0 {
+			break
+		}
+		at := from + i
+		if r, n := utf8.DecodeLastRuneInString(text[from:at]); n > 0 && unicode.ToLower(r) == unicode.ToLower(first) {
+			return turnSpan{at - n, at + len(rest)}
+		}
+		_, n := utf8.DecodeRuneInString(text[at:])
+		from = at + n
+	}
+	return turnSpan{-1, -1}
+}
+
+// locateTurns finds each turn in the transcript, in order, so a turn whose
+// text repeats an earlier one's is found after it. Turns edited out of the
+// transcript aren't found.
+func locateTurns(text string, turns []Turn) []turnSpan {
+	spans := make([]turnSpan, len(turns))
+	from := 0
+	for i, turn := range turns {
+		spans[i] = turnSpan{-1, -1}
+		if turn.Text == "" {
+			continue
+		}
+		span := findTurn(text, turn.display(), from)
+		if span.start < 0 {
+			span = findTurn(text, turn.Text, from)
+		}
+		if span.start >= 0 {
+			spans[i] = span
+			from = span.end
+		}
+	}
+	return spans
+}
+
+// spliceTurns puts replacement in place of the first of spans, which are in
+// order, and drops the rest along with the separator before each.
+func spliceTurns(text string, spans []turnSpan, replacement string) string {
+	for i := len(spans) - 1; i > 0; i-- {
+		start := len(strings.TrimRightFunc(text[:spans[i].start], unicode.IsSpace))
+		text = text[:start] + text[spans[i].end:]
+	}
+	return text[:spans[0].start] + replacement + text[spans[0].end:]
+}
+
+func truncateLabel(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// turnKey identifies a turn across updates to its text.
+func turnKey(t Turn) [3]int {
+	return [3]int{t.Session, t.Stream, t.Order}
+}
+
+// showTurnSelection picks turns of the current session to copy, export or
+// process.
+func (a *App) showTurnSelection() {
+	a.mu.RLock()
+	var turns []Turn
+	for _, turn := range a.turns {
+		if turn.Text != "" {
+			turns = append(turns, turn)
+		}
+	}
+	a.mu.RUnlock()
+	doc := ExportDocument{Title: a.sessionTitle, Attendees: a.sessionAttendees, Locale: a.settings().Locale}
+	a.showTurnPicker(turns, doc, a.processSelectedTurns)
+}
+
+// processSelectedTurns runs the selected turns through the LLM. The result
+// takes the place of the first of them still in the transcript, and the rest
+// are dropped, leaving whatever else was written meanwhile alone.
+func (a *App) processSelectedTurns(selected []Turn, text string) {
+	keys := make(map[[3]int]bool)
+	for _, turn := range selected {
+		keys[turnKey(turn)] = true
+	}
+	a.processTextWithLLM(text, func(processed string) {
+		a.mu.RLock()
+		turns := append([]Turn(nil), a.turns...)
+		a.mu.RUnlock()
+
+		current := a.textArea.Text
+		var spans []turnSpan
+		for i, span := range locateTurns(current, turns) {
+			if keys[turnKey(turns[i])] && span.start >= 0 {
+				spans = append(spans, span)
+			}
+		}
+		a.previousText = current
+		if len(spans) == 0 {
+			// Edited out while processing; keep the result rather than lose it
+			a.textArea.SetText(strings.TrimRightFunc(current, unicode.IsSpace) + "\n" + processed)
+			return
+		}
+		a.textArea.SetText(spliceTurns(current, spans, processed))
+	})
+}
+
+// showTurnPicker lists turns to check and copy, export, or, given process,
+// send to the LLM. doc is the document exports start from.
+func (a *App) showTurnPicker(turns []Turn, doc ExportDocument, process func(selected []Turn, text string)) {
+	if len(turns) == 0 {
+		a.updateStatus(tr("No turns to select"))
+		return
+	}
+
+	checks := make([]*widget.Check, len(turns))
+	list := container.NewVBox()
+	for i, turn := range turns {
+		checks[i] = widget.NewCheck(fmt.Sprintf("%d. %s", i+1, truncateLabel(turn.display(), 80)), nil)
+		list.Add(checks[i])
+	}
+	listScroll := container.NewScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(520, 300))
+
+	setRange := func(from, to int, checked bool) {
+		for i := from; i <= to; i++ {
+			checks[i].SetChecked(checked)
+		}
+	}
+
+	selectedTurns := func() ([]Turn, string) {
+		var selected []Turn
+		var parts []string
+		for i, turn := range turns {
+			if checks[i].Checked {
+				selected = append(selected, turn)
+				parts = append(parts, turn.display())
+			}
+		}
+		return selected, strings.Join(parts, "\n")
+	}
+
+	// Range selection
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder(tr("From"))
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder(tr("To"))
+	rangeBtn := widget.NewButton(tr("Select Range"), func() {
+		from, err1 := strconv.Atoi(strings.TrimSpace(fromEntry.Text))
+		to, err2 := strconv.Atoi(strings.TrimSpace(toEntry.Text))
+		if err1 != nil || err2 != nil || from < 1 || to > len(turns) || from > to {
+			dialog.ShowError(errors.New(tr("Enter a turn range between 1 and %d", len(turns))), a.window)
+			return
+		}
+		setRange(from-1, to-1, true)
+	})
+	allBtn := widget.NewButton(tr("All"), func() { setRange(0, len(turns)-1, true) })
+	noneBtn := widget.NewButton(tr("None"), func() { setRange(0, len(turns)-1, false) })
+	rangeRow := container.NewHBox(allBtn, noneBtn, widget.NewSeparator(),
+		container.NewGridWrap(fyne.NewSize(70, fromEntry.MinSize().Height), fromEntry),
+		container.NewGridWrap(fyne.NewSize(70, toEntry.MinSize().Height), toEntry),
+		rangeBtn)
+
+	var selectionDialog dialog.Dialog
+
+	copyBtn := widget.NewButtonWithIcon(tr("Copy Selected"), theme.ContentCopyIcon(), func() {
+		_, text := selectedTurns()
+		if text == "" {
+			a.updateStatus(tr("No turns selected"))
+			return
+		}
+		a.copyToClipboard("Copied turns", text)
+		a.updateStatus(tr("Selected turns copied"))
+	})
+
+	exportBtn := widget.NewButtonWithIcon(tr("Export Selected"), theme.DocumentSaveIcon(), nil)
+	exportBtn.OnTapped = func() {
+		selected, text := selectedTurns()
+		if len(selected) == 0 {
+			a.updateStatus(tr("No turns selected"))
+			return
+		}
+		subset := doc
+		subset.Text = text
+		subset.addTurns(selected)
+		a.showMenuBelow(exportBtn, a.formatMenuItems(func(f Formatter) { a.exportDocumentAs(f, subset, "turns") }))
+	}
+
+	buttons := container.NewHBox(copyBtn, exportBtn)
+	if process != nil {
+		buttons.Add(widget.NewButtonWithIcon(tr("Process Selected"), theme.ComputerIcon(), func() {
+			selected, text := selectedTurns()
+			if len(selected) == 0 {
+				a.updateStatus(tr("No turns selected"))
+				return
+			}
+			selectionDialog.Hide()
+			process(selected, text)
+		}))
+	}
+
+	content := container.NewBorder(rangeRow, buttons, nil, nil, listScroll)
+
+	selectionDialog = dialog.NewCustom(tr("Select Turns"), tr("Close"), content, a.window)
+	selectionDialog.Resize(fyne.NewSize(560, 460))
+	selectionDialog.Show()
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+	"dict/config"
+)
+
+// Default rates in USD: AssemblyAI streaming per hour of audio, and LLM
+// tokens per million.
+const (
+	defaultAudioRate      = 0.15
+	defaultPromptRate     = 0.20
+	defaultCompletionRate = 0.60
+)
+
+// Usage is the API usage for a session or a month.
+type Usage struct {
+	AudioSeconds     float64 `json:"audio_seconds"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+}
+
+// UsageRates prices usage. Zero rates are free.
+type UsageRates struct {
+	AudioPerHour         float64
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+	MonthlyBudget        float64 // Zero disables the budget warning
+}
+
+var defaultUsageRates = UsageRates{
+	AudioPerHour:         defaultAudioRate,
+	PromptPerMillion:     defaultPromptRate,
+	CompletionPerMillion: defaultCompletionRate,
+}
+
+func (u Usage) cost(rates UsageRates) float64 {
+	return u.AudioSeconds/3600*rates.AudioPerHour +
+		float64(u.PromptTokens)/1e6*rates.PromptPerMillion +
+		float64(u.CompletionTokens)/1e6*rates.CompletionPerMillion
+}
+
+func (u *Usage) add(other Usage) {
+	u.AudioSeconds += other.AudioSeconds
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+}
+
+func usageMonth(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+func (a *App) getUsagePath() string {
+	return filepath.Join(a.getConfigDir(), "usage.json")
+}
+
+// loadUsage reads the monthly usage totals.
+func (a *App) loadUsage() {
+	a.monthlyUsage = make(map[string]Usage)
+	data, err := os.ReadFile(a.getUsagePath())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &a.monthlyUsage); err != nil {
+		slog.Warn("failed to parse usage file", "err", err)
+	}
+}
+
+// saveUsage writes the monthly usage totals. Caller must hold a.mu.
+func (a *App) saveUsage() {
+	data, err := json.MarshalIndent(a.monthlyUsage, "", "  ")
+	if err != nil {
+		slog.Error("failed to marshal usage", "err", err)
+		return
+	}
+	if err := os.MkdirAll(a.getConfigDir(), 0755); err != nil {
+		slog.Error("failed to create config directory", "err", err)
+		return
+	}
+	if err := os.WriteFile(a.getUsagePath(), data, 0600); err != nil {
+		slog.Error("failed to save usage", "err", err)
+	}
+}
+
+// recordUsage adds usage to the current session and month, and warns once a
+// month when the budget is exceeded.
+func (a *App) recordUsage(u Usage) {
+	a.mu.Lock()
+	a.sessionUsage.add(u)
+	month := usageMonth(time.Now())
+	total := a.monthlyUsage[month]
+	total.add(u)
+	a.monthlyUsage[month] = total
+	a.saveUsage()
+
+	rates := a.settings().UsageRates
+	cost := total.cost(rates)
+	overBudget := rates.MonthlyBudget > 0 && cost > rates.MonthlyBudget && a.budgetWarnedMonth != month
+	if overBudget {
+		a.budgetWarnedMonth = month
+	}
+	a.mu.Unlock()
+
+	if overBudget {
+		message := tr("Estimated usage this month is $%.2f, over your $%.2f budget", cost, rates.MonthlyBudget)
+		slog.Warn("monthly budget exceeded", "cost", cost, "budget", rates.MonthlyBudget)
+		a.fyneApp.SendNotification(fyne.NewNotification(tr("Monthly budget exceeded"), message))
+		fyne.Do(func() {
+			a.updateStatus(message)
+		})
+	}
+}
+
+// streamAudioSeconds is the audio billed for a stream: the duration reported
+// by AssemblyAI when the connection terminated cleanly, otherwise the audio
+// sent over it.
+func streamAudioSeconds(st *Stream) float64 {
+	if st.billedSeconds > 0 {
+		return st.billedSeconds
+	}
+	return float64(st.sentBytes.Load()-st.connBytes) / (audio.SampleRate * 2)
+}
+
+func formatUsage(u Usage, rates UsageRates) string {
+	return fmt.Sprintf("%s audio, %d + %d tokens — $%.4f",
+		clockTime(time.Duration(u.AudioSeconds*float64(time.Second))),
+		u.PromptTokens, u.CompletionTokens, u.cost(rates))
+}
+
+func (a *App) showUsagePanel() {
+	a.mu.RLock()
+	session := a.sessionUsage
+	rates := a.settings().UsageRates
+	var months []string
+	for month := range a.monthlyUsage {
+		months = append(months, month)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(months)))
+	monthly := make(map[string]Usage, len(a.monthlyUsage))
+	for month, u := range a.monthlyUsage {
+		monthly[month] = u
+	}
+	a.mu.RUnlock()
+
+	current := usageMonth(time.Now())
+	monthCost := monthly[current].cost(rates)
+
+	summary := container.NewVBox(
+		widget.NewLabelWithStyle(tr("This Session"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(formatUsage(session, rates)),
+		widget.NewLabelWithStyle(tr("This Month"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(formatUsage(monthly[current], rates)),
+	)
+	if rates.MonthlyBudget > 0 {
+		budget := widget.NewProgressBar()
+		budget.SetValue(min(monthCost/rates.MonthlyBudget, 1))
+		budget.TextFormatter = func() string {
+			return fmt.Sprintf("$%.2f of $%.2f budget", monthCost, rates.MonthlyBudget)
+		}
+		summary.Add(budget)
+		if monthCost > rates.MonthlyBudget {
+			warning := widget.NewLabel(tr("Over budget this month"))
+			warning.Importance = widget.DangerImportance
+			summary.Add(warning)
+		}
+	}
+
+	history := container.NewVBox(widget.NewLabelWithStyle(tr("History"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, month := range months {
+		history.Add(widget.NewLabel(month + ": " + formatUsage(monthly[month], rates)))
+	}
+
+	audioRateEntry := widget.NewEntry()
+	audioRateEntry.SetText(strconv.FormatFloat(rates.AudioPerHour, 'f', -1, 64))
+	promptRateEntry := widget.NewEntry()
+	promptRateEntry.SetText(strconv.FormatFloat(rates.PromptPerMillion, 'f', -1, 64))
+	completionRateEntry := widget.NewEntry()
+	completionRateEntry.SetText(strconv.FormatFloat(rates.CompletionPerMillion, 'f', -1, 64))
+	budgetEntry := widget.NewEntry()
+	budgetEntry.SetPlaceHolder(tr("No budget"))
+	if rates.MonthlyBudget > 0 {
+		budgetEntry.SetText(strconv.FormatFloat(rates.MonthlyBudget, 'f', -1, 64))
+	}
+
+	rateForm := widget.NewForm(
+		widget.NewFormItem(tr("Audio ($/hour)"), audioRateEntry),
+		widget.NewFormItem(tr("LLM input ($/1M tokens)"), promptRateEntry),
+		widget.NewFormItem(tr("LLM output ($/1M tokens)"), completionRateEntry),
+		widget.NewFormItem(tr("Monthly budget ($)"), budgetEntry),
+	)
+
+	var usageDialog dialog.Dialog
+	saveBtn := widget.NewButton(tr("Save Rates"), func() {
+		parse := func(text string) (float64, error) {
+			if text == "" {
+				return 0, nil
+			}
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("Rates must be positive numbers")
+			}
+			return v, nil
+		}
+		var newRates UsageRates
+		var err error
+		for _, field := range []struct {
+			entry *widget.Entry
+			value *float64
+		}{
+			{audioRateEntry, &newRates.AudioPerHour},
+			{promptRateEntry, &newRates.PromptPerMillion},
+			{completionRateEntry, &newRates.CompletionPerMillion},
+			{budgetEntry, &newRates.MonthlyBudget},
+		} {
+			if *field.value, err = parse(field.entry.Text); err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+		}
+
+		a.updateSettings(func(s *Settings) { s.UsageRates = newRates })
+		usageDialog.Hide()
+		a.saveConfig()
+	})
+
+	content := container.NewVBox(
+		summary,
+		widget.NewSeparator(),
+		history,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(tr("Rates"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		rateForm,
+		saveBtn,
+		widget.NewLabel(tr("Costs are estimates; check your provider dashboards for billing.")),
+	)
+	usageDialog = dialog.NewCustom(tr("Usage"), tr("Close"), container.NewVScroll(content), a.window)
+	usageDialog.Resize(fyne.NewSize(480, 520))
+	usageDialog.Show()
+}
+
+func (r UsageRates) toConfig() config.Usage {
+	return config.Usage{
+		AudioPerHour:         r.AudioPerHour,
+		PromptPerMillion:     r.PromptPerMillion,
+		CompletionPerMillion: r.CompletionPerMillion,
+		MonthlyBudget:        r.MonthlyBudget,
+	}
+}
+
+func usageRatesFromConfig(c config.Usage) UsageRates {
+	rates := defaultUsageRates
+	for _, v := range []struct {
+		from float64
+		to   *float64
+	}{
+		{c.AudioPerHour, &rates.AudioPerHour},
+		{c.PromptPerMillion, &rates.PromptPerMillion},
+		{c.CompletionPerMillion, &rates.CompletionPerMillion},
+		{c.MonthlyBudget, &rates.MonthlyBudget},
+	} {
+		if v.from >= 0 {
+			*v.to = v.from
+		}
+	}
+	return rates
+}
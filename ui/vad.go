@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"dict/audio"
+)
+
+// Voice activity detection backends for silence suppression
+const (
+	vadOff    = ""
+	vadEnergy = audio.Energy
+	vadWebRTC = audio.WebRTC
+	vadSilero = audio.Silero
+)
+
+// Sensitivity presets: higher sensitivity lets quieter speech through but
+// also more background noise
+const (
+	vadLow    = audio.Low
+	vadMedium = audio.Medium
+	vadHigh   = audio.High
+)
+
+var vadBackendLabels = map[string]string{
+	vadOff:    trMark("Off"),
+	vadEnergy: trMark("Energy (built in)"),
+	vadWebRTC: trMark("WebRTC (needs voice-typing-vad)"),
+	vadSilero: trMark("Silero (needs voice-typing-vad)"),
+}
+
+var vadBackendOrder = []string{vadOff, vadEnergy, vadWebRTC, vadSilero}
+
+var vadSensitivityLabels = []string{trMark("Low"), trMark("Medium"), trMark("High")}
+
+var vadSensitivities = []string{vadLow, vadMedium, vadHigh}
+
+func vadBackendFromLabel(label string) string {
+	for backend, l := range vadBackendLabels {
+		if tr(l) == label {
+			return backend
+		}
+	}
+	return vadOff
+}
+
+func (a *App) newVADLabel() fyne.CanvasObject {
+	a.vadLbl = widget.NewLabel("")
+	a.vadLbl.Hide()
+	return a.vadLbl
+}
+
+// updateVADIndicator shows whether silence suppression currently hears
+// speech on the first stream.
+func (a *App) updateVADIndicator() {
+	if a.vadLbl == nil {
+		return
+	}
+	if !a.recording.Load() || a.sessionCfg.VADBackend == vadOff {
+		a.vadLbl.Hide()
+		return
+	}
+	text, importance := "○ "+tr("Silence"), widget.LowImportance
+	if a.vadSpeech.Load() {
+		text, importance = "● "+tr("Speech"), widget.SuccessImportance
+	}
+	if a.vadLbl.Text != text || !a.vadLbl.Visible() {
+		a.vadLbl.Importance = importance
+		a.vadLbl.SetText(text)
+		a.vadLbl.Show()
+	}
+}
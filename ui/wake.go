@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+
+	"dict/audio"
+)
+
+const (
+	wakeHelper         = "voice-typing-wake" // Runs the wake word detector, see wake/
+	wakeQueueChunks    = 64                  // Audio chunks the helper may fall behind by
+	defaultWakeWord    = "hey_jarvis"
+	defaultSleepPhrase = "stop listening"
+)
+
+// WakeListener listens to the microphone for the wake word with a local
+// detector, so nothing is streamed until it's heard.
+type WakeListener struct {
+	cmd     *exec.Cmd
+	in      io.WriteCloser
+	audio   chan []byte // Waiting to be written to the helper
+	dropped int
+	ready   bool // Set on the UI thread once started
+
+	capture audio.Capture
+
+	mu      sync.Mutex
+	recent  []byte // The last preRoll bytes heard
+	preRoll int
+}
+
+// start starts the detector helper and the microphone, calling onWake each
+// time the helper hears the wake word, and onFail if the helper stops taking
+// audio. The last preRoll milliseconds of audio are kept for the recording
+// it starts.
+func (w *WakeListener) start(word string, preRoll int, onWake func(), onFail func(error)) error {
+	w.preRoll = preRollBytes(preRoll)
+	w.audio = make(chan []byte, wakeQueueChunks)
+	w.cmd = exec.Command(wakeHelper, "--word", word)
+	var stderr bytes.Buffer
+	w.cmd.Stderr = &stderr
+	in, err := w.cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %v", wakeHelper, err)
+	}
+	out, err := w.cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %v", wakeHelper, err)
+	}
+	if err := w.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %v", wakeHelper, err)
+	}
+	w.in = in
+
+	// Loading a model can take a few seconds, so wait until it's ready
+	lines := bufio.NewScanner(out)
+	if !lines.Scan() || strings.TrimSpace(lines.Text()) != "ready" {
+		w.close()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %s", wakeHelper, msg)
+		}
+		return fmt.Errorf("%s failed to start", wakeHelper)
+	}
+	go func() {
+		for lines.Scan() {
+			if strings.TrimSpace(lines.Text()) == "wake" {
+				onWake()
+			}
+		}
+	}()
+	go w.writeAudio(onFail)
+
+	if err := w.startMicrophone(); err != nil {
+		w.close()
+		return err
+	}
+	return nil
+}
+
+func (w *WakeListener) startMicrophone() error {
+	return w.capture.Start(audio.Microphone, func(pcm []byte) {
+		// The helper is written to from writeAudio, so a slow one can't
+		// hold up the capture callback
+		select {
+		case w.audio <- append([]byte(nil), pcm...):
+		default:
+			w.dropped++
+		}
+		w.mu.Lock()
+		w.recent = audio.KeepRecent(w.recent, pcm, w.preRoll)
+		w.mu.Unlock()
+	})
+}
+
+// writeAudio passes captured audio to the helper until close.
+func (w *WakeListener) writeAudio(onFail func(error)) {
+	for pcm := range w.audio {
+		if _, err := w.in.Write(pcm); err != nil {
+			onFail(fmt.Errorf("failed to write to %s: %v", wakeHelper, err))
+			return
+		}
+	}
+}
+
+// recentAudio returns a copy of the pre-roll audio.
+func (w *WakeListener) recentAudio() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.recent...)
+}
+
+func (w *WakeListener) close() {
+	w.capture.Close()
+	close(w.audio)
+	w.in.Close()
+	w.cmd.Process.Kill()
+	w.cmd.Wait()
+	if w.dropped > 0 {
+		slog.Warn("wake word detection fell behind, skipped audio", "helper", wakeHelper, "chunks", w.dropped)
+	}
+}
+
+// wakePhrase is how a wake word model name is spoken, e.g. "hey jarvis" for
+// hey_jarvis. Model files have no reliable spoken form.
+func wakePhrase(word string) string {
+	if strings.ContainsAny(word, `/\.`) {
+		return ""
+	}
+	return strings.ReplaceAll(word, "_", " ")
+}
+
+// heardSleepPhrase reports whether a turn includes the sleep phrase, which
+// stops recording in hands-free mode.
+func heardSleepPhrase(cfg *Settings, text string) bool {
+	if !cfg.HandsFree || cfg.SleepPhrase == "" {
+		return false
+	}
+	return newPhraseStripper([]string{cfg.SleepPhrase}).strip(text) != text
+}
+
+// listenForWakeWord starts listening for the wake word if hands-free is on
+// and nothing is being recorded. It's called again whenever recording stops.
+func (a *App) listenForWakeWord() {
+	cfg := a.settings()
+	if !cfg.HandsFree || a.recording.Load() || a.wake != nil {
+		return
+	}
+	w := &WakeListener{}
+	a.wake = w
+	word := cfg.WakeWord
+	go func() {
+		capture, err := a.newCapture()
+		if err == nil {
+			w.capture = capture
+			err = w.start(word, cfg.PreRoll, func() {
+				fyne.Do(func() { a.onWakeWord(w) })
+			}, func(err error) {
+				fyne.Do(func() { a.onWakeFailed(w, err) })
+			})
+		}
+		fyne.Do(func() {
+			if err != nil {
+				if a.wake == w {
+					a.wake = nil
+				}
+				slog.Error("wake word listener failed", "err", err)
+				a.updateStatus(tr("Hands-free: %s", err))
+				return
+			}
+			if a.wake != w {
+				// Stopped while starting
+				w.close()
+				return
+			}
+			w.ready = true
+			slog.Info("listening for wake word", "word", word)
+			a.updateStatus(tr("Listening for %q...", word))
+		})
+	}()
+}
+
+// stopWakeListener stops listening for the wake word.
+func (a *App) stopWakeListener() {
+	w := a.wake
+	if w == nil {
+		return
+	}
+	a.wake = nil
+	if w.ready {
+		w.close()
+	}
+}
+
+// onWakeWord starts recording when the wake word is heard.
+func (a *App) onWakeWord(w *WakeListener) {
+	if a.wake != w || a.recording.Load() {
+		return
+	}
+	slog.Info("wake word heard")
+	if !needsConsent(a.settings()) {
+		// Otherwise consent is asked first, which the pre-roll would predate
+		a.mu.Lock()
+		a.wakeAudio = w.recentAudio()
+		a.mu.Unlock()
+	}
+	a.stopWakeListener()
+	a.startRecording()
+}
+
+// onWakeFailed stops a listener whose helper has gone, rather than leave
+// hands-free mode deaf.
+func (a *App) onWakeFailed(w *WakeListener, err error) {
+	if a.wake != w {
+		return
+	}
+	slog.Error("wake word listener failed", "err", err)
+	a.stopWakeListener()
+	a.updateStatus(tr("Hands-free: %s", err))
+}
+
+// applyWakeWord starts or restarts the wake word listener after the
+// settings change.
+func (a *App) applyWakeWord() {
+	a.stopWakeListener()
+	a.listenForWakeWord()
+}